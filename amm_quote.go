@@ -0,0 +1,188 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Default fee parameters for Raydium constant-product pools
+const (
+	AMM_V4_SWAP_FEE_NUMERATOR     = 25
+	AMM_V4_SWAP_FEE_DENOMINATOR   = 10_000
+	CPSWAP_FEE_RATE_DENOMINATOR   = 1_000_000
+	CPSWAP_DEFAULT_TRADE_FEE_RATE = 2_500
+	MAX_SLIPPAGE_BPS              = 10_000
+)
+
+// Errors returned by the constant-product quote engine
+var (
+	ErrPoolZeroAmount            = errors.New("swap amount must be greater than zero")
+	ErrPoolInsufficientLiquidity = errors.New("swap amount exceeds pool liquidity")
+	ErrPoolInvalidFee            = errors.New("pool fee must be below 100%")
+)
+
+// ConstantProductPool holds the state needed to quote an AMM v4 or CP-Swap pool.
+// Reserves are the tradable vault balances, i.e. after pending PnL or protocol/fund fees
+// have been subtracted.
+type ConstantProductPool struct {
+	ProgramID      solana.PublicKey
+	Address        solana.PublicKey
+	BaseMint       solana.PublicKey
+	QuoteMint      solana.PublicKey
+	BaseReserve    uint64
+	QuoteReserve   uint64
+	FeeNumerator   uint64
+	FeeDenominator uint64
+}
+
+// NewAmmV4Pool returns a quotable AMM v4 pool with the standard 0.25% swap fee
+func NewAmmV4Pool(baseReserve, quoteReserve uint64) ConstantProductPool {
+	return ConstantProductPool{
+		ProgramID:      RaydiumV4ProgramID,
+		BaseReserve:    baseReserve,
+		QuoteReserve:   quoteReserve,
+		FeeNumerator:   AMM_V4_SWAP_FEE_NUMERATOR,
+		FeeDenominator: AMM_V4_SWAP_FEE_DENOMINATOR,
+	}
+}
+
+// NewCpSwapPool returns a quotable CP-Swap pool using the trade fee rate from its AmmConfig
+func NewCpSwapPool(token0Reserve, token1Reserve, tradeFeeRate uint64) ConstantProductPool {
+	return ConstantProductPool{
+		ProgramID:      RaydiumCpSwapProgramID,
+		BaseReserve:    token0Reserve,
+		QuoteReserve:   token1Reserve,
+		FeeNumerator:   tradeFeeRate,
+		FeeDenominator: CPSWAP_FEE_RATE_DENOMINATOR,
+	}
+}
+
+// SwapQuote represents the expected result of a constant-product swap
+type SwapQuote struct {
+	BaseToQuote bool
+	ExactIn     bool
+
+	AmountIn  uint64
+	AmountOut uint64
+	// MinimumAmountOut is AmountOut after slippage; set for exact-in (swap base in) quotes
+	MinimumAmountOut uint64
+	// MaximumAmountIn is AmountIn after slippage; set for exact-out (swap base out) quotes
+	MaximumAmountIn uint64
+	// Fee is charged in the input token
+	Fee uint64
+
+	// Prices are in raw output units per raw input unit and exclude fees
+	SpotPrice      float64
+	ExecutionPrice float64
+	PriceImpact    float64
+}
+
+// ApplyToSwap fills a swap builder's direction and amount fields from the quote and
+// returns the builder
+func (q *SwapQuote) ApplyToSwap(s *SwapInstruction) *SwapInstruction {
+	if !q.ExactIn {
+		return s.SetBaseOut(true).SetMaxAmountIn(q.MaximumAmountIn).SetAmountOut(q.AmountOut).SetExpectedAmountIn(q.AmountIn)
+	}
+	return s.SetBaseOut(false).SetAmountIn(q.AmountIn).SetMinimumAmountOut(q.MinimumAmountOut).SetExpectedAmountOut(q.AmountOut)
+}
+
+// QuoteSwapBaseIn quotes swapping exactly amountIn of the input token.
+// baseToQuote selects the direction: true sells the base (token 0) for the quote (token 1).
+func QuoteSwapBaseIn(pool ConstantProductPool, amountIn uint64, baseToQuote bool, slippageBps uint16) (*SwapQuote, error) {
+	if err := checkSwapQuote(pool, amountIn, slippageBps); err != nil {
+		return nil, err
+	}
+	reserveIn, reserveOut := pool.reserves(baseToQuote)
+
+	fee := mulDivCeil(amountIn, pool.FeeNumerator, pool.FeeDenominator)
+	if fee >= amountIn {
+		return nil, fmt.Errorf("amount in %d does not cover swap fee of %d", amountIn, fee)
+	}
+	amountInLessFee := amountIn - fee
+
+	numerator := new(big.Int).Mul(bigU64(amountInLessFee), bigU64(reserveOut))
+	denominator := new(big.Int).Add(bigU64(reserveIn), bigU64(amountInLessFee))
+	amountOut := numerator.Quo(numerator, denominator).Uint64()
+	if amountOut == 0 || amountOut >= reserveOut {
+		return nil, fmt.Errorf("%w: %d in yields %d out", ErrPoolInsufficientLiquidity, amountIn, amountOut)
+	}
+
+	quote := &SwapQuote{
+		BaseToQuote:      baseToQuote,
+		ExactIn:          true,
+		AmountIn:         amountIn,
+		AmountOut:        amountOut,
+		MinimumAmountOut: applySlippageDown(amountOut, slippageBps),
+		Fee:              fee,
+	}
+	quote.setPrices(reserveIn, reserveOut, amountInLessFee)
+	return quote, nil
+}
+
+// QuoteSwapBaseOut quotes receiving exactly amountOut of the output token.
+// baseToQuote selects the direction: true sells the base (token 0) for the quote (token 1).
+func QuoteSwapBaseOut(pool ConstantProductPool, amountOut uint64, baseToQuote bool, slippageBps uint16) (*SwapQuote, error) {
+	if err := checkSwapQuote(pool, amountOut, slippageBps); err != nil {
+		return nil, err
+	}
+	reserveIn, reserveOut := pool.reserves(baseToQuote)
+	if amountOut >= reserveOut {
+		return nil, fmt.Errorf("%w: %d out of reserve %d", ErrPoolInsufficientLiquidity, amountOut, reserveOut)
+	}
+
+	numerator := new(big.Int).Mul(bigU64(amountOut), bigU64(reserveIn))
+	amountInLessFee := ceilDiv(numerator, bigU64(reserveOut-amountOut))
+	amountInBig := ceilDiv(new(big.Int).Mul(amountInLessFee, bigU64(pool.FeeDenominator)), bigU64(pool.FeeDenominator-pool.FeeNumerator))
+	if !amountInBig.IsUint64() {
+		return nil, fmt.Errorf("%w: required input overflows u64", ErrPoolInsufficientLiquidity)
+	}
+	amountIn := amountInBig.Uint64()
+
+	quote := &SwapQuote{
+		BaseToQuote:     baseToQuote,
+		ExactIn:         false,
+		AmountIn:        amountIn,
+		AmountOut:       amountOut,
+		MaximumAmountIn: applySlippageUp(amountIn, slippageBps),
+		Fee:             amountIn - amountInLessFee.Uint64(),
+	}
+	quote.setPrices(reserveIn, reserveOut, amountInLessFee.Uint64())
+	return quote, nil
+}
+
+func checkSwapQuote(pool ConstantProductPool, amount uint64, slippageBps uint16) error {
+	if amount == 0 {
+		return ErrPoolZeroAmount
+	}
+	if pool.FeeDenominator == 0 || pool.FeeNumerator >= pool.FeeDenominator {
+		return ErrPoolInvalidFee
+	}
+	if pool.BaseReserve == 0 || pool.QuoteReserve == 0 {
+		return fmt.Errorf("%w: pool has an empty reserve", ErrPoolInsufficientLiquidity)
+	}
+	if slippageBps > MAX_SLIPPAGE_BPS {
+		return fmt.Errorf("slippage %d bps exceeds %d", slippageBps, MAX_SLIPPAGE_BPS)
+	}
+	return nil
+}
+
+func (p ConstantProductPool) reserves(baseToQuote bool) (reserveIn uint64, reserveOut uint64) {
+	if baseToQuote {
+		return p.BaseReserve, p.QuoteReserve
+	}
+	return p.QuoteReserve, p.BaseReserve
+}
+
+// setPrices records spot and execution prices; amountInLessFee is the input that reached the curve
+func (q *SwapQuote) setPrices(reserveIn, reserveOut, amountInLessFee uint64) {
+	q.SpotPrice = float64(reserveOut) / float64(reserveIn)
+	if amountInLessFee > 0 {
+		q.ExecutionPrice = float64(q.AmountOut) / float64(amountInLessFee)
+	}
+	if q.SpotPrice > 0 {
+		q.PriceImpact = 1 - q.ExecutionPrice/q.SpotPrice
+	}
+}
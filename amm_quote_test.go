@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestQuoteSwapBaseInAmmV4(t *testing.T) {
+	// 1000 SOL (9 decimals) against 150000 USDC (6 decimals)
+	pool := NewAmmV4Pool(1_000_000_000_000, 150_000_000_000)
+
+	quote, err := QuoteSwapBaseIn(pool, 1_000_000_000, true, 50)
+	if err != nil {
+		t.Fatalf("Failed to quote swap: %v", err)
+	}
+
+	if quote.Fee != 2_500_000 {
+		t.Errorf("Expected fee 2500000, got %d", quote.Fee)
+	}
+	// 997_500_000 * 150e9 / (1e12 + 997_500_000)
+	if quote.AmountOut != 149_475_897 {
+		t.Errorf("Expected amount out 149475897, got %d", quote.AmountOut)
+	}
+	if expected := quote.AmountOut * 9950 / 10000; quote.MinimumAmountOut != expected {
+		t.Errorf("Expected minimum amount out %d, got %d", expected, quote.MinimumAmountOut)
+	}
+	if quote.PriceImpact <= 0 || quote.PriceImpact > 0.001 {
+		t.Errorf("Expected price impact around 0.1%%, got %f", quote.PriceImpact)
+	}
+
+	reverse, err := QuoteSwapBaseIn(pool, 150_000_000, false, 50)
+	if err != nil {
+		t.Fatalf("Failed to quote reverse swap: %v", err)
+	}
+	if reverse.AmountOut >= 1_000_000_000 {
+		t.Errorf("Expected less than 1 SOL for 150 USDC after fees, got %d", reverse.AmountOut)
+	}
+}
+
+func TestQuoteSwapBaseOutMatchesBaseIn(t *testing.T) {
+	pools := map[string]ConstantProductPool{
+		"amm_v4":  NewAmmV4Pool(1_000_000_000_000, 150_000_000_000),
+		"cp_swap": NewCpSwapPool(42_000_000_000, 7_300_000_000_000, CPSWAP_DEFAULT_TRADE_FEE_RATE),
+	}
+
+	for name, pool := range pools {
+		t.Run(name, func(t *testing.T) {
+			for _, baseToQuote := range []bool{true, false} {
+				in, err := QuoteSwapBaseIn(pool, 3_000_000_000, baseToQuote, 100)
+				if err != nil {
+					t.Fatalf("Base in failed: %v", err)
+				}
+				out, err := QuoteSwapBaseOut(pool, in.AmountOut, baseToQuote, 100)
+				if err != nil {
+					t.Fatalf("Base out failed: %v", err)
+				}
+				if out.AmountIn > in.AmountIn {
+					t.Errorf("Receiving %d should cost at most %d, quoted %d", in.AmountOut, in.AmountIn, out.AmountIn)
+				}
+				if out.MaximumAmountIn < out.AmountIn {
+					t.Errorf("Maximum amount in %d below amount in %d", out.MaximumAmountIn, out.AmountIn)
+				}
+
+				// Feeding the exact-out input back through the exact-in path must deliver the target
+				check, err := QuoteSwapBaseIn(pool, out.AmountIn, baseToQuote, 0)
+				if err != nil {
+					t.Fatalf("Check quote failed: %v", err)
+				}
+				if check.AmountOut < out.AmountOut {
+					t.Errorf("Input %d yields %d, short of target %d", out.AmountIn, check.AmountOut, out.AmountOut)
+				}
+			}
+		})
+	}
+}
+
+func TestQuoteSwapErrors(t *testing.T) {
+	pool := NewAmmV4Pool(1_000_000, 1_000_000)
+
+	if _, err := QuoteSwapBaseIn(pool, 0, true, 0); !errors.Is(err, ErrPoolZeroAmount) {
+		t.Errorf("Expected ErrPoolZeroAmount, got %v", err)
+	}
+	if _, err := QuoteSwapBaseOut(pool, 1_000_000, true, 0); !errors.Is(err, ErrPoolInsufficientLiquidity) {
+		t.Errorf("Expected ErrPoolInsufficientLiquidity, got %v", err)
+	}
+	if _, err := QuoteSwapBaseIn(NewAmmV4Pool(0, 1_000_000), 1000, true, 0); !errors.Is(err, ErrPoolInsufficientLiquidity) {
+		t.Errorf("Expected ErrPoolInsufficientLiquidity for empty pool, got %v", err)
+	}
+	if _, err := QuoteSwapBaseIn(NewCpSwapPool(1000, 1000, CPSWAP_FEE_RATE_DENOMINATOR), 10, true, 0); !errors.Is(err, ErrPoolInvalidFee) {
+		t.Errorf("Expected ErrPoolInvalidFee, got %v", err)
+	}
+	if _, err := QuoteSwapBaseIn(pool, 1000, true, MAX_SLIPPAGE_BPS+1); err == nil {
+		t.Errorf("Expected error for slippage above 100%%")
+	}
+}
+
+func TestSwapQuoteFillsBuilder(t *testing.T) {
+	pool := NewAmmV4Pool(1_000_000_000_000, 150_000_000_000)
+
+	quote, err := QuoteSwapBaseIn(pool, 1_000_000_000, true, 100)
+	if err != nil {
+		t.Fatalf("Failed to quote swap: %v", err)
+	}
+	swap := quote.ApplyToSwap(NewSwapInstruction())
+	if swap.amountIn != quote.AmountIn || swap.minimumAmountOut != quote.MinimumAmountOut {
+		t.Errorf("Builder amounts %d/%d do not match quote %d/%d", swap.amountIn, swap.minimumAmountOut, quote.AmountIn, quote.MinimumAmountOut)
+	}
+
+	exactOut, err := QuoteSwapBaseOut(pool, 100_000_000, true, 100)
+	if err != nil {
+		t.Fatalf("Failed to quote exact-out swap: %v", err)
+	}
+	exactOut.ApplyToSwap(swap)
+	if swap.tag() != AMM_V4_INSTRUCTION_SWAP_BASE_OUT || swap.maxAmountIn != exactOut.MaximumAmountIn || swap.amountOut != exactOut.AmountOut {
+		t.Errorf("Builder not switched to swapBaseOut with quote amounts %d/%d", exactOut.MaximumAmountIn, exactOut.AmountOut)
+	}
+}
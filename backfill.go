@@ -0,0 +1,273 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// MAX_SIGNATURES_PAGE_SIZE is the most signatures getSignaturesForAddress returns per call
+const MAX_SIGNATURES_PAGE_SIZE = 1000
+
+// ErrCheckpointMismatch is returned when a checkpoint file belongs to a different address
+var ErrCheckpointMismatch = errors.New("checkpoint is for a different address")
+
+// SignatureLister pages through the signatures that mention an address, newest first.
+// Signatures older than before (when set) and newer than until (when set) are returned.
+type SignatureLister interface {
+	ListSignatures(ctx context.Context, address solana.PublicKey, before, until solana.Signature, limit int) ([]*rpc.TransactionSignature, error)
+}
+
+// BackfillSource lists and fetches transactions; RPCFetcher implements it
+type BackfillSource interface {
+	TransactionFetcher
+	SignatureLister
+}
+
+// BackfillItem is one transaction of a backfill with its position in the history
+type BackfillItem struct {
+	BatchResult
+	Slot uint64
+	// BlockTime is zero when the node does not know the block time
+	BlockTime time.Time
+	// TxErr is the transaction's execution error; failed transactions are parsed as well
+	TxErr interface{}
+}
+
+// BackfillCheckpoint records how far a backfill has progressed, newest to oldest
+type BackfillCheckpoint struct {
+	Address solana.PublicKey `json:"address"`
+	// Before is the oldest signature handled so far; the backfill resumes below it
+	Before    solana.Signature `json:"before"`
+	Processed int              `json:"processed"`
+	Done      bool             `json:"done"`
+	UpdatedAt time.Time        `json:"updatedAt"`
+	// Retry lists the transactions whose fetch failed; the next run fetches them again first
+	Retry []*rpc.TransactionSignature `json:"retry,omitempty"`
+}
+
+// Backfill walks an address's transaction history from newest to oldest, fetching and
+// parsing every transaction and handing it to a callback in history order
+type Backfill struct {
+	source     BackfillSource
+	address    solana.PublicKey
+	before     solana.Signature
+	until      solana.Signature
+	from       time.Time
+	to         time.Time
+	pageSize   int
+	workers    int
+	checkpoint string
+	opts       []ParseOption
+}
+
+// NewBackfill creates a backfill over the whole history of address, such as a pool or a
+// Raydium program
+func NewBackfill(source BackfillSource, address solana.PublicKey) *Backfill {
+	return &Backfill{
+		source:   source,
+		address:  address,
+		pageSize: MAX_SIGNATURES_PAGE_SIZE,
+		workers:  DEFAULT_BATCH_WORKERS,
+	}
+}
+
+// SetBefore starts the backfill below signature instead of at the newest transaction
+func (b *Backfill) SetBefore(signature solana.Signature) *Backfill {
+	b.before = signature
+	return b
+}
+
+// SetUntil stops the backfill at signature, which is not included
+func (b *Backfill) SetUntil(signature solana.Signature) *Backfill {
+	b.until = signature
+	return b
+}
+
+// SetTimeRange limits the backfill to blocks in [from, to); a zero bound is open
+func (b *Backfill) SetTimeRange(from, to time.Time) *Backfill {
+	b.from = from
+	b.to = to
+	return b
+}
+
+// SetPageSize sets how many signatures are listed per request
+func (b *Backfill) SetPageSize(size int) *Backfill {
+	b.pageSize = min(max(size, 1), MAX_SIGNATURES_PAGE_SIZE)
+	return b
+}
+
+// SetWorkers sets how many transactions of a page are fetched at once
+func (b *Backfill) SetWorkers(workers int) *Backfill {
+	b.workers = workers
+	return b
+}
+
+// SetCheckpointFile saves progress to path after every page and resumes from it when it exists
+func (b *Backfill) SetCheckpointFile(path string) *Backfill {
+	b.checkpoint = path
+	return b
+}
+
+// SetParseOptions sets the options transactions are parsed with
+func (b *Backfill) SetParseOptions(opts ...ParseOption) *Backfill {
+	b.opts = opts
+	return b
+}
+
+// Run handles every transaction in range, newest first, and returns how many were handled.
+// Fetch and parse failures are reported in the item; transactions whose fetch failed are
+// also kept in the checkpoint and handed over again, fetched afresh, at the start of the
+// next run. An error from handle or from listing signatures stops the run, and a later run
+// with the same checkpoint file continues after the last handled transaction.
+func (b *Backfill) Run(ctx context.Context, handle func(BackfillItem) error) (int, error) {
+	checkpoint := &BackfillCheckpoint{Address: b.address, Before: b.before}
+	if b.checkpoint != "" {
+		saved, err := LoadBackfillCheckpoint(b.checkpoint)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return 0, err
+		}
+		if saved != nil {
+			if !saved.Address.Equals(b.address) {
+				return 0, fmt.Errorf("%w: %s holds %s", ErrCheckpointMismatch, b.checkpoint, saved.Address)
+			}
+			checkpoint = saved
+		}
+	}
+
+	retry := checkpoint.Retry
+	checkpoint.Retry = nil
+	handled, err := b.handleEntries(ctx, checkpoint, retry, false, handle)
+	if err != nil {
+		return handled, errors.Join(err, b.save(checkpoint))
+	}
+	if len(retry) > 0 {
+		if err := b.save(checkpoint); err != nil {
+			return handled, err
+		}
+	}
+
+	for !checkpoint.Done {
+		page, err := b.source.ListSignatures(ctx, b.address, checkpoint.Before, b.until, b.pageSize)
+		if err != nil {
+			return handled, err
+		}
+
+		inRange := make([]*rpc.TransactionSignature, 0, len(page))
+		for _, entry := range page {
+			if entry.BlockTime != nil && !b.from.IsZero() && entry.BlockTime.Time().Before(b.from) {
+				// Older than the range, and so is everything after it
+				checkpoint.Done = true
+				break
+			}
+			if entry.BlockTime != nil && !b.to.IsZero() && !entry.BlockTime.Time().Before(b.to) {
+				// Newer than the range; only moves the cursor
+				checkpoint.Before = entry.Signature
+				continue
+			}
+			inRange = append(inRange, entry)
+		}
+		if len(page) < b.pageSize {
+			checkpoint.Done = true
+		}
+
+		n, err := b.handleEntries(ctx, checkpoint, inRange, true, handle)
+		handled += n
+		if err != nil {
+			checkpoint.Done = false
+			return handled, errors.Join(err, b.save(checkpoint))
+		}
+
+		if err := b.save(checkpoint); err != nil {
+			return handled, err
+		}
+	}
+	return handled, nil
+}
+
+// handleEntries fetches, parses and handles entries in order and queues the ones whose
+// fetch failed for retry. With advance set the cursor moves past each handled entry;
+// otherwise the entries are earlier failures, and those not handled go back on the queue.
+func (b *Backfill) handleEntries(ctx context.Context, checkpoint *BackfillCheckpoint, entries []*rpc.TransactionSignature, advance bool, handle func(BackfillItem) error) (int, error) {
+	inputs := make([]string, len(entries))
+	for i, entry := range entries {
+		inputs[i] = entry.Signature.String()
+	}
+
+	handled := 0
+	for i, result := range ParseBatch(ctx, b.source, inputs, b.workers, b.opts...) {
+		entry := entries[i]
+		var err error
+		if result.Err != nil && ctx.Err() != nil {
+			// Entries cut short by cancellation are left for the next run
+			err = ctx.Err()
+		} else {
+			item := BackfillItem{BatchResult: result, Slot: entry.Slot, TxErr: entry.Err}
+			if entry.BlockTime != nil {
+				item.BlockTime = entry.BlockTime.Time()
+			}
+			err = handle(item)
+		}
+		if err != nil {
+			if !advance {
+				checkpoint.Retry = append(checkpoint.Retry, entries[i:]...)
+			}
+			return handled, err
+		}
+
+		if result.Err != nil && !errors.Is(result.Err, ErrParseFailed) {
+			checkpoint.Retry = append(checkpoint.Retry, entry)
+		}
+		if advance {
+			// Retried entries were counted the first time round
+			checkpoint.Before = entry.Signature
+			checkpoint.Processed++
+		}
+		handled++
+	}
+	return handled, nil
+}
+
+func (b *Backfill) save(checkpoint *BackfillCheckpoint) error {
+	if b.checkpoint == "" {
+		return nil
+	}
+	checkpoint.UpdatedAt = time.Now().UTC()
+	return checkpoint.Save(b.checkpoint)
+}
+
+// LoadBackfillCheckpoint reads a checkpoint written by Save
+func LoadBackfillCheckpoint(path string) (*BackfillCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var checkpoint BackfillCheckpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	}
+	return &checkpoint, nil
+}
+
+// Save writes the checkpoint to path, replacing it atomically so an interrupted write
+// leaves the previous checkpoint intact
+func (c *BackfillCheckpoint) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace checkpoint: %w", err)
+	}
+	return nil
+}
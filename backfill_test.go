@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// backfillHistory is an address's transaction history, newest first, one minute apart
+type backfillHistory struct {
+	signatures []solana.Signature
+	blockTimes []int64
+	txBytes    map[string][]byte
+}
+
+func newBackfillHistory(t *testing.T, count int) *backfillHistory {
+	t.Helper()
+	history := &backfillHistory{txBytes: map[string][]byte{}}
+	for i := 0; i < count; i++ {
+		tx, txBytes := signedBuyTransaction(t)
+		history.signatures = append(history.signatures, tx.Signatures[0])
+		history.blockTimes = append(history.blockTimes, 1_700_000_000-int64(i)*60)
+		history.txBytes[tx.Signatures[0].String()] = txBytes
+	}
+	return history
+}
+
+// endpoint serves the history over getSignaturesForAddress and getTransaction
+func (h *backfillHistory) endpoint(t *testing.T) (*fakeRPC, string) {
+	return newFakeEndpoint(t, map[string]func(json.RawMessage) (interface{}, error){
+		"getSignaturesForAddress": func(params json.RawMessage) (interface{}, error) {
+			var args []json.RawMessage
+			json.Unmarshal(params, &args)
+			var options struct {
+				Limit  int
+				Before string
+				Until  string
+			}
+			json.Unmarshal(args[1], &options)
+
+			start := 0
+			if options.Before != "" {
+				for start < len(h.signatures) && h.signatures[start].String() != options.Before {
+					start++
+				}
+				start++
+			}
+			page := []interface{}{}
+			for i := start; i < len(h.signatures) && len(page) < options.Limit; i++ {
+				if h.signatures[i].String() == options.Until {
+					break
+				}
+				page = append(page, map[string]interface{}{
+					"signature": h.signatures[i].String(),
+					"slot":      5000 - i,
+					"blockTime": h.blockTimes[i],
+					"err":       nil,
+				})
+			}
+			return page, nil
+		},
+		"getTransaction": func(params json.RawMessage) (interface{}, error) {
+			var args []interface{}
+			json.Unmarshal(params, &args)
+			signature, _ := args[0].(string)
+			txBytes, ok := h.txBytes[signature]
+			if !ok {
+				return nil, nil
+			}
+			return getTransactionResult(txBytes, 5000, nil), nil
+		},
+	})
+}
+
+// fetcher is an RPCFetcher for endpoint
+func (h *backfillHistory) fetcher(t *testing.T) (*fakeRPC, *RPCFetcher) {
+	fake, url := h.endpoint(t)
+	return fake, NewRPCFetcher(url).SetRateLimit(0)
+}
+
+func TestBackfillResumes(t *testing.T) {
+	history := newBackfillHistory(t, 5)
+	fake, fetcher := history.fetcher(t)
+	address := solana.NewWallet().PublicKey()
+	checkpointPath := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	var seen []solana.Signature
+	stopAfter := 2
+	handle := func(item BackfillItem) error {
+		if len(seen) == stopAfter {
+			return errors.New("interrupted")
+		}
+		if item.Err != nil || len(item.Transaction.Trade) != 1 {
+			t.Errorf("Expected a parsed buy for %s: %v", item.Input, item.Err)
+		}
+		seen = append(seen, item.Signature)
+		return nil
+	}
+
+	backfill := NewBackfill(fetcher, address).SetPageSize(2).SetWorkers(2).SetCheckpointFile(checkpointPath)
+	handled, err := backfill.Run(context.Background(), handle)
+	if err == nil || handled != 2 {
+		t.Fatalf("Expected the run to stop after 2 transactions, got %d (%v)", handled, err)
+	}
+	checkpoint, err := LoadBackfillCheckpoint(checkpointPath)
+	if err != nil || checkpoint.Before != history.signatures[1] || checkpoint.Processed != 2 || checkpoint.Done {
+		t.Fatalf("Unexpected checkpoint %+v (%v)", checkpoint, err)
+	}
+
+	// A new run picks up below the last handled signature
+	stopAfter = -1
+	handled, err = NewBackfill(fetcher, address).SetPageSize(2).SetCheckpointFile(checkpointPath).Run(context.Background(), handle)
+	if err != nil || handled != 3 {
+		t.Fatalf("Expected the remaining 3 transactions, got %d (%v)", handled, err)
+	}
+	for i, signature := range history.signatures {
+		if i >= len(seen) || seen[i] != signature {
+			t.Fatalf("Expected history order %v, got %v", history.signatures, seen)
+		}
+	}
+	if checkpoint, _ := LoadBackfillCheckpoint(checkpointPath); !checkpoint.Done || checkpoint.Processed != 5 {
+		t.Errorf("Expected a finished checkpoint, got %+v", checkpoint)
+	}
+
+	// A finished checkpoint lists nothing more
+	listed := fake.count("getSignaturesForAddress")
+	if handled, err := backfill.Run(context.Background(), handle); handled != 0 || err != nil || fake.count("getSignaturesForAddress") != listed {
+		t.Errorf("Expected a finished backfill to do nothing, handled %d (%v)", handled, err)
+	}
+
+	other := NewBackfill(fetcher, solana.NewWallet().PublicKey()).SetCheckpointFile(checkpointPath)
+	if _, err := other.Run(context.Background(), handle); !errors.Is(err, ErrCheckpointMismatch) {
+		t.Errorf("Expected ErrCheckpointMismatch, got %v", err)
+	}
+}
+
+func TestBackfillRetriesFailedFetches(t *testing.T) {
+	history := newBackfillHistory(t, 4)
+	_, fetcher := history.fetcher(t)
+	address := solana.NewWallet().PublicKey()
+	checkpointPath := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	// The second transaction is unknown to the node for now
+	missing := history.signatures[1].String()
+	txBytes := history.txBytes[missing]
+	delete(history.txBytes, missing)
+
+	var failed, parsed []solana.Signature
+	handle := func(item BackfillItem) error {
+		if item.Err != nil {
+			failed = append(failed, item.Signature)
+		} else {
+			parsed = append(parsed, item.Signature)
+		}
+		return nil
+	}
+	backfill := NewBackfill(fetcher, address).SetPageSize(2).SetCheckpointFile(checkpointPath)
+	if handled, err := backfill.Run(context.Background(), handle); err != nil || handled != 4 {
+		t.Fatalf("Expected 4 handled transactions, got %d (%v)", handled, err)
+	}
+	if len(failed) != 1 || failed[0] != history.signatures[1] || len(parsed) != 3 {
+		t.Fatalf("Expected only %s to fail, got failed %v parsed %v", missing, failed, parsed)
+	}
+	checkpoint, err := LoadBackfillCheckpoint(checkpointPath)
+	if err != nil || !checkpoint.Done || checkpoint.Processed != 4 || len(checkpoint.Retry) != 1 || checkpoint.Retry[0].Signature != history.signatures[1] {
+		t.Fatalf("Expected the failed fetch queued for retry, got %+v (%v)", checkpoint, err)
+	}
+
+	// The next run fetches it again, even though the history is done
+	history.txBytes[missing] = txBytes
+	failed, parsed = nil, nil
+	if handled, err := backfill.Run(context.Background(), handle); err != nil || handled != 1 {
+		t.Fatalf("Expected the retried transaction only, got %d (%v)", handled, err)
+	}
+	if len(failed) != 0 || len(parsed) != 1 || parsed[0] != history.signatures[1] {
+		t.Errorf("Expected %s to be parsed on retry, got failed %v parsed %v", missing, failed, parsed)
+	}
+	if checkpoint, _ := LoadBackfillCheckpoint(checkpointPath); len(checkpoint.Retry) != 0 || checkpoint.Processed != 4 {
+		t.Errorf("Expected an empty retry queue, got %+v", checkpoint)
+	}
+}
+
+func TestBackfillRange(t *testing.T) {
+	history := newBackfillHistory(t, 6)
+	fake, fetcher := history.fetcher(t)
+
+	// Block times run from 1_700_000_000 down in minute steps: keep entries 1 to 3
+	from := time.Unix(history.blockTimes[3], 0)
+	to := time.Unix(history.blockTimes[0], 0)
+	var seen []solana.Signature
+	handled, err := NewBackfill(fetcher, solana.NewWallet().PublicKey()).
+		SetPageSize(2).
+		SetTimeRange(from, to).
+		Run(context.Background(), func(item BackfillItem) error {
+			if !item.BlockTime.Before(to) || item.BlockTime.Before(from) {
+				t.Errorf("Block time %v is outside the range", item.BlockTime)
+			}
+			seen = append(seen, item.Signature)
+			return nil
+		})
+	if err != nil || handled != 3 || seen[0] != history.signatures[1] || seen[2] != history.signatures[3] {
+		t.Fatalf("Expected entries 1 to 3, got %d (%v)", handled, err)
+	}
+	// The third page reaches an entry older than the range and paging stops there
+	if pages := fake.count("getSignaturesForAddress"); pages != 3 {
+		t.Errorf("Expected 3 pages, listed %d", pages)
+	}
+
+	seen = nil
+	handled, err = NewBackfill(fetcher, solana.NewWallet().PublicKey()).
+		SetBefore(history.signatures[0]).
+		SetUntil(history.signatures[2]).
+		Run(context.Background(), func(item BackfillItem) error {
+			seen = append(seen, item.Signature)
+			return nil
+		})
+	if err != nil || handled != 1 || seen[0] != history.signatures[1] {
+		t.Errorf("Expected only the entry between the cursors, got %v (%v)", seen, err)
+	}
+}
+
+func TestBackfillCommand(t *testing.T) {
+	history := newBackfillHistory(t, 3)
+	_, fetcher := history.fetcher(t)
+
+	var out bytes.Buffer
+	err := runBackfillCommand(context.Background(), []string{
+		"-address", RaydiumLaunchpadV1ProgramID.String(),
+		"-json", "-page-size", "2",
+		"-from", time.Unix(history.blockTimes[2], 0).UTC().Format(time.RFC3339),
+	}, &out, fetcher)
+	if err != nil {
+		t.Fatalf("backfill failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 JSON lines, got:\n%s", out.String())
+	}
+	var record struct {
+		Input     string
+		BlockTime time.Time
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil || record.Input != history.signatures[0].String() || record.BlockTime.Unix() != history.blockTimes[0] {
+		t.Errorf("Unexpected first record %s (%v)", lines[0], err)
+	}
+
+	if err := runBackfillCommand(context.Background(), []string{"-address", "nope"}, &out, fetcher); err == nil {
+		t.Errorf("Expected an invalid address error")
+	}
+	if err := runBackfillCommand(context.Background(), []string{"-address", RaydiumLaunchpadV1ProgramID.String(), "-from", "yesterday"}, &out, fetcher); err == nil {
+		t.Errorf("Expected an invalid time error")
+	}
+}
+
+func TestBackfillCommandStdout(t *testing.T) {
+	history := newBackfillHistory(t, 2)
+	_, url := history.endpoint(t)
+
+	stdout, err := runMain(t, []string{SOLANA_RPC_ENDPOINT_ENV + "=" + url}, "", "backfill", "-address", RaydiumLaunchpadV1ProgramID.String(), "-json")
+	if err != nil {
+		t.Fatalf("backfill failed: %v\n%s", err, stdout)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 JSON lines on stdout, got:\n%s", stdout)
+	}
+	for _, line := range lines {
+		if !json.Valid([]byte(line)) {
+			t.Errorf("Expected JSON Lines on stdout, got %q", line)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// DEFAULT_BATCH_WORKERS is how many signatures ParseBatch fetches at once by default
+const DEFAULT_BATCH_WORKERS = 4
+
+// explorerTxPath marks a transaction link such as https://solscan.io/tx/<signature>
+const explorerTxPath = "/tx/"
+
+// BatchResult is the outcome for one entry of a batch; exactly one of Transaction and Err is set
+type BatchResult struct {
+	Input       string
+	Signature   solana.Signature
+	Transaction *Transaction
+	Err         error
+}
+
+// ReadSignatures reads one signature per line. Blank lines and # comments are skipped,
+// except that explorer transaction links are taken from comments too, as the example
+// files list them that way. Entries are returned as written and validated by ParseBatch.
+func ReadSignatures(r io.Reader) ([]string, error) {
+	var entries []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if comment, ok := strings.CutPrefix(line, "#"); ok {
+			index := strings.Index(comment, explorerTxPath)
+			if index < 0 {
+				continue
+			}
+			line = comment[index:]
+		}
+		if fields := strings.Fields(line); len(fields) > 0 {
+			entries = append(entries, fields[0])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read signatures: %w", err)
+	}
+	return entries, nil
+}
+
+// parseSignatureEntry accepts a base58 signature or an explorer link to one
+func parseSignatureEntry(entry string) (solana.Signature, error) {
+	if index := strings.LastIndex(entry, explorerTxPath); index >= 0 {
+		entry = entry[index+len(explorerTxPath):]
+		if end := strings.IndexAny(entry, "?#/"); end >= 0 {
+			entry = entry[:end]
+		}
+	}
+	signature, err := solana.SignatureFromBase58(entry)
+	if err != nil {
+		return solana.Signature{}, fmt.Errorf("invalid signature %q: %w", entry, err)
+	}
+	return signature, nil
+}
+
+// ParseBatch fetches and parses every entry with at most workers requests in flight.
+// Results are in input order; a failed entry is reported in its result and does not stop
+// the others. Entries not started before ctx is done fail with the context's error.
+func ParseBatch(ctx context.Context, fetcher TransactionFetcher, entries []string, workers int, opts ...ParseOption) []BatchResult {
+	results := make([]BatchResult, len(entries))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < max(min(workers, len(entries)), 1); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = parseBatchEntry(ctx, fetcher, entries[i], opts)
+			}
+		}()
+	}
+
+	for i := range entries {
+		if ctx.Err() != nil {
+			results[i] = BatchResult{Input: entries[i], Err: ctx.Err()}
+			continue
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+func parseBatchEntry(ctx context.Context, fetcher TransactionFetcher, entry string, opts []ParseOption) BatchResult {
+	result := BatchResult{Input: entry}
+	signature, err := parseSignatureEntry(entry)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Signature = signature
+	result.Transaction, result.Err = FetchAndParseTransaction(ctx, fetcher, signature, opts...)
+	return result
+}
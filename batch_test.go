@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// stubFetcher serves getTransaction results from memory, slower for earlier entries so
+// batches complete out of order, and records the peak number of concurrent fetches
+type stubFetcher struct {
+	results map[solana.Signature]*rpc.GetTransactionResult
+	delay   map[solana.Signature]time.Duration
+
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (s *stubFetcher) FetchTransaction(ctx context.Context, signature solana.Signature) (*rpc.GetTransactionResult, error) {
+	s.mu.Lock()
+	s.inFlight++
+	s.peak = max(s.peak, s.inFlight)
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+	}()
+
+	if err := sleepContext(ctx, s.delay[signature]); err != nil {
+		return nil, err
+	}
+	result, ok := s.results[signature]
+	if !ok {
+		return nil, rpc.ErrNotFound
+	}
+	return result, nil
+}
+
+// newStubFetcher signs count buy transactions and serves them, returning their signatures
+func newStubFetcher(t *testing.T, count int) (*stubFetcher, []solana.Signature) {
+	t.Helper()
+	fetcher := &stubFetcher{
+		results: map[solana.Signature]*rpc.GetTransactionResult{},
+		delay:   map[solana.Signature]time.Duration{},
+	}
+	var signatures []solana.Signature
+	for i := 0; i < count; i++ {
+		tx, txBytes := signedBuyTransaction(t)
+		raw, _ := json.Marshal(getTransactionResult(txBytes, uint64(1000+i), nil))
+		var result rpc.GetTransactionResult
+		if err := json.Unmarshal(raw, &result); err != nil {
+			t.Fatalf("Failed to decode getTransaction result: %v", err)
+		}
+		fetcher.results[tx.Signatures[0]] = &result
+		fetcher.delay[tx.Signatures[0]] = time.Duration(count-i) * 5 * time.Millisecond
+		signatures = append(signatures, tx.Signatures[0])
+	}
+	return fetcher, signatures
+}
+
+func TestReadSignatures(t *testing.T) {
+	file, err := os.Open("test_signatures.txt")
+	if err != nil {
+		t.Fatalf("Failed to open test_signatures.txt: %v", err)
+	}
+	defer file.Close()
+	entries, err := ReadSignatures(file)
+	if err != nil || len(entries) != 2 || entries[1] != realTxSignature {
+		t.Errorf("Expected the two listed signatures, got %q (%v)", entries, err)
+	}
+
+	// The Launchpad examples are explorer links inside comments
+	file, err = os.Open("launchpad_test_transactions.txt")
+	if err != nil {
+		t.Fatalf("Failed to open launchpad_test_transactions.txt: %v", err)
+	}
+	defer file.Close()
+	entries, err = ReadSignatures(file)
+	if err != nil || len(entries) != 5 {
+		t.Fatalf("Expected five explorer links, got %q (%v)", entries, err)
+	}
+	if signature, err := parseSignatureEntry(entries[0]); err != nil || signature.String() != "5wefCTqi9ynrh8pvVHFzpgHCLFFzoBwGoTgWSd6iq2Qw4Y51U4cEc2xHYtsdVSFZmRXUp5DNMSkhzb1CaXomLpJM" {
+		t.Errorf("Failed to take the signature from %q: %v", entries[0], err)
+	}
+	if _, err := parseSignatureEntry(entries[1]); err == nil {
+		t.Errorf("Expected the placeholder link %q to be rejected", entries[1])
+	}
+}
+
+func TestParseBatch(t *testing.T) {
+	fetcher, signatures := newStubFetcher(t, 5)
+	entries := []string{
+		signatures[0].String(),
+		"not-a-signature",
+		signatures[1].String(),
+		solana.Signature{9}.String(),
+		"https://solscan.io/tx/" + signatures[2].String() + "?cluster=mainnet",
+		signatures[3].String(),
+		signatures[4].String(),
+	}
+
+	results := ParseBatch(context.Background(), fetcher, entries, 2, WithSignatureVerification())
+	if len(results) != len(entries) {
+		t.Fatalf("Expected %d results, got %d", len(entries), len(results))
+	}
+	if fetcher.peak > 2 {
+		t.Errorf("Expected at most 2 concurrent fetches, saw %d", fetcher.peak)
+	}
+
+	expected := []solana.Signature{signatures[0], {}, signatures[1], {}, signatures[2], signatures[3], signatures[4]}
+	for i, result := range results {
+		if result.Input != entries[i] {
+			t.Errorf("Result %d is for %q, expected %q", i, result.Input, entries[i])
+		}
+		if expected[i].IsZero() {
+			if result.Err == nil {
+				t.Errorf("Expected entry %d (%s) to fail", i, entries[i])
+			}
+			continue
+		}
+		if result.Err != nil || result.Transaction == nil || result.Transaction.Signature != expected[i] || len(result.Transaction.Trade) != 1 {
+			t.Errorf("Entry %d: expected a parsed buy %s, got %+v", i, expected[i], result)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, result := range ParseBatch(ctx, fetcher, entries, 2) {
+		if result.Err == nil {
+			t.Errorf("Expected %s to fail after cancellation", result.Input)
+		}
+	}
+}
+
+func TestBatchCommand(t *testing.T) {
+	fetcher, signatures := newStubFetcher(t, 2)
+	input := "# two buys and a typo\n" + signatures[0].String() + "\n\nbad\n" + signatures[1].String() + "\n"
+
+	var out bytes.Buffer
+	err := runBatchCommand(context.Background(), []string{"-workers", "3"}, strings.NewReader(input), &out, fetcher)
+	if err == nil || !strings.Contains(err.Error(), "1 of 3 signatures failed") {
+		t.Errorf("Expected the failure count as the error, got %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "1\t"+signatures[0].String()+"\tslot 1000") ||
+		!strings.Contains(lines[1], "FAILED") || !strings.HasPrefix(lines[2], "3\t"+signatures[1].String()) {
+		t.Errorf("Unexpected output:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := runBatchCommand(context.Background(), []string{"-json", "-"}, strings.NewReader(signatures[1].String()), &out, fetcher); err != nil {
+		t.Fatalf("batch -json failed: %v", err)
+	}
+	var record struct {
+		Input       string
+		Transaction struct {
+			Signature string
+			Slot      uint64
+		}
+	}
+	if err := json.Unmarshal(out.Bytes(), &record); err != nil || record.Transaction.Signature != signatures[1].String() || record.Transaction.Slot != 1001 {
+		t.Errorf("Unexpected JSON record %s (%v)", out.String(), err)
+	}
+
+	if err := runBatchCommand(context.Background(), nil, strings.NewReader("# nothing\n"), &out, fetcher); err == nil {
+		t.Errorf("Expected an error for an empty signature list")
+	}
+}
+
+// runMain runs the CLI in a child process with args, so the test sees exactly what the
+// command writes to the real stdout, including anything the parser prints on its own
+func runMain(t *testing.T, env []string, stdin string, args ...string) (string, error) {
+	t.Helper()
+	cmd := mainCommand(env, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	err := cmd.Run()
+	return stdout.String(), err
+}
+
+// mainCommand prepares a child process that runs the CLI with args
+func mainCommand(env []string, args ...string) *exec.Cmd {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestMainProcess$")
+	cmd.Env = append(os.Environ(), append(env, "RAYDIUM_PARSER_MAIN_ARGS="+strings.Join(args, "\x1f"))...)
+	return cmd
+}
+
+// TestMainProcess is the child process of runMain
+func TestMainProcess(t *testing.T) {
+	args, ok := os.LookupEnv("RAYDIUM_PARSER_MAIN_ARGS")
+	if !ok {
+		t.Skip("only runs as the child process of runMain")
+	}
+	os.Args = append([]string{"raydium-parser"}, strings.Split(args, "\x1f")...)
+	main()
+	// Exit before the test framework reports on stdout
+	os.Exit(0)
+}
+
+func TestBatchCommandStdout(t *testing.T) {
+	tx, txBytes := signedBuyTransaction(t)
+	_, url := newFakeEndpoint(t, map[string]func(json.RawMessage) (interface{}, error){
+		"getTransaction": func(json.RawMessage) (interface{}, error) {
+			return getTransactionResult(txBytes, 1000, nil), nil
+		},
+	})
+
+	stdout, err := runMain(t, []string{SOLANA_RPC_ENDPOINT_ENV + "=" + url}, tx.Signatures[0].String()+"\n", "batch", "-json")
+	if err != nil {
+		t.Fatalf("batch failed: %v\n%s", err, stdout)
+	}
+	// The whole of stdout is JSON Lines: parser diagnostics must not be mixed in
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	var record struct{ Transaction struct{ Signature string } }
+	if len(lines) != 1 || json.Unmarshal([]byte(lines[0]), &record) != nil || record.Transaction.Signature != tx.Signatures[0].String() {
+		t.Errorf("Expected a single JSON line on stdout, got:\n%s", stdout)
+	}
+}
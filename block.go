@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// raydiumProgramIDs are the programs whose transactions ParseBlock decodes
+var raydiumProgramIDs = []solana.PublicKey{
+	RaydiumV4ProgramID,
+	RaydiumV5ProgramID,
+	RaydiumStakingProgramID,
+	RaydiumLiquidityProgramID,
+	RaydiumLaunchpadV1ProgramID,
+	RaydiumCpSwapProgramID,
+	RaydiumClmmProgramID,
+}
+
+// BlockFetcher retrieves blocks by slot
+type BlockFetcher interface {
+	// FetchBlock returns the block with base64 encoded transactions and their metadata
+	FetchBlock(ctx context.Context, slot uint64) (*rpc.GetBlockResult, error)
+}
+
+// BlockTransaction is a parsed Raydium transaction with its place in the block
+type BlockTransaction struct {
+	*Transaction
+	// Position is the transaction's index in the block
+	Position int
+}
+
+// ParsedBlock holds the Raydium transactions of one block
+type ParsedBlock struct {
+	Slot       uint64
+	ParentSlot uint64
+	Blockhash  solana.Hash
+	// BlockTime is zero and BlockHeight is nil when the node does not report them
+	BlockTime   time.Time
+	BlockHeight *uint64
+
+	Transactions []BlockTransaction
+	// Skipped counts transactions that failed or do not mention a Raydium program
+	Skipped int
+	// Errors holds the transactions that could not be read or mention Raydium but could not
+	// be parsed, each prefixed with its position
+	Errors []error
+}
+
+// ParseBlock parses the Raydium transactions of a getBlock result for slot, which the
+// result does not carry itself. Transactions are kept in block order. Failed transactions
+// and transactions without a Raydium program among their accounts are skipped before
+// decoding; a transaction that cannot be parsed is reported in Errors and does not stop the
+// rest of the block.
+func ParseBlock(slot uint64, block *rpc.GetBlockResult, opts ...ParseOption) (*ParsedBlock, error) {
+	if block == nil {
+		return nil, fmt.Errorf("block %d is empty", slot)
+	}
+	parsed := &ParsedBlock{
+		Slot:        slot,
+		ParentSlot:  block.ParentSlot,
+		Blockhash:   block.Blockhash,
+		BlockHeight: block.BlockHeight,
+	}
+	if block.BlockTime != nil {
+		parsed.BlockTime = block.BlockTime.Time()
+	}
+
+	for position, entry := range block.Transactions {
+		if entry.Transaction == nil {
+			parsed.Errors = append(parsed.Errors, fmt.Errorf("transaction %d: no transaction data", position))
+			continue
+		}
+		txBytes := entry.Transaction.GetBinary()
+		if len(txBytes) == 0 {
+			parsed.Errors = append(parsed.Errors, fmt.Errorf("transaction %d: not binary encoded", position))
+			continue
+		}
+		if (entry.Meta != nil && entry.Meta.Err != nil) || !mentionsRaydiumProgram(txBytes, entry.Meta) {
+			parsed.Skipped++
+			continue
+		}
+
+		tx, err := parseRPCTransaction(txBytes, slot, entry.Meta, opts...)
+		if err != nil {
+			parsed.Errors = append(parsed.Errors, fmt.Errorf("transaction %d: %w", position, err))
+			continue
+		}
+		parsed.Transactions = append(parsed.Transactions, BlockTransaction{Transaction: tx, Position: position})
+	}
+	return parsed, nil
+}
+
+// ParseBlockJSON parses a getBlock result saved as JSON, either the bare result or the
+// whole JSON-RPC response. Transactions must be base64 encoded.
+func ParseBlockJSON(data []byte, slot uint64, opts ...ParseOption) (*ParsedBlock, error) {
+	var response struct {
+		Result *json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("invalid block JSON: %w", err)
+	}
+	if response.Result != nil {
+		data = *response.Result
+	}
+
+	var block rpc.GetBlockResult
+	if err := json.Unmarshal(data, &block); err != nil {
+		return nil, fmt.Errorf("invalid block JSON: %w", err)
+	}
+	return ParseBlock(slot, &block, opts...)
+}
+
+// FetchAndParseBlock fetches the block at slot and parses its Raydium transactions
+func FetchAndParseBlock(ctx context.Context, fetcher BlockFetcher, slot uint64, opts ...ParseOption) (*ParsedBlock, error) {
+	block, err := fetcher.FetchBlock(ctx, slot)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBlock(slot, block, opts...)
+}
+
+// mentionsRaydiumProgram reports whether a Raydium program ID appears in the serialized
+// transaction or among the addresses it loaded from lookup tables. Top-level programs are
+// always static account keys, so a byte search is enough to rule a transaction out
+// without decoding it.
+func mentionsRaydiumProgram(txBytes []byte, meta *rpc.TransactionMeta) bool {
+	for _, program := range raydiumProgramIDs {
+		if bytes.Contains(txBytes, program[:]) {
+			return true
+		}
+		if meta != nil && (meta.LoadedAddresses.Writable.Contains(program) || meta.LoadedAddresses.ReadOnly.Contains(program)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// signedTransferTransaction signs a plain SOL transfer, which mentions no Raydium program
+func signedTransferTransaction(t *testing.T) []byte {
+	t.Helper()
+	payer := solana.NewWallet().PrivateKey
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{system.NewTransferInstruction(1_000, payer.PublicKey(), solana.NewWallet().PublicKey()).Build()},
+		solana.Hash(solana.NewWallet().PublicKey()),
+		solana.TransactionPayer(payer.PublicKey()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to build transfer: %v", err)
+	}
+	if _, err := tx.Sign(func(solana.PublicKey) *solana.PrivateKey { return &payer }); err != nil {
+		t.Fatalf("Failed to sign transfer: %v", err)
+	}
+	txBytes, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to serialize transfer: %v", err)
+	}
+	return txBytes
+}
+
+// blockFixture is a getBlock result at slot 6000 holding, in order: a buy, a transfer,
+// a failed buy, a transaction that mentions Raydium but does not decode, and a buy
+type blockFixture struct {
+	buys   []solana.Signature
+	result map[string]interface{}
+}
+
+func newBlockFixture(t *testing.T) *blockFixture {
+	t.Helper()
+	first, firstBytes := signedBuyTransaction(t)
+	last, lastBytes := signedBuyTransaction(t)
+	_, failedBytes := signedBuyTransaction(t)
+	failure := map[string]interface{}{"InstructionError": []interface{}{0, "InvalidAccountData"}}
+
+	return &blockFixture{
+		buys: []solana.Signature{first.Signatures[0], last.Signatures[0]},
+		result: map[string]interface{}{
+			"blockhash":         solana.Hash{1}.String(),
+			"previousBlockhash": solana.Hash{2}.String(),
+			"parentSlot":        5999,
+			"blockTime":         1_700_000_000,
+			"blockHeight":       300,
+			"transactions": []interface{}{
+				getTransactionResult(firstBytes, 6000, nil),
+				getTransactionResult(signedTransferTransaction(t), 6000, nil),
+				getTransactionResult(failedBytes, 6000, failure),
+				getTransactionResult(RaydiumV4ProgramID.Bytes(), 6000, nil),
+				getTransactionResult(lastBytes, 6000, nil),
+			},
+		},
+	}
+}
+
+func (f *blockFixture) check(t *testing.T, block *ParsedBlock) {
+	t.Helper()
+	if block.Slot != 6000 || block.ParentSlot != 5999 || block.BlockTime.Unix() != 1_700_000_000 || block.BlockHeight == nil || *block.BlockHeight != 300 {
+		t.Errorf("Unexpected block header %+v", block)
+	}
+	if len(block.Transactions) != 2 || block.Skipped != 2 || len(block.Errors) != 1 {
+		t.Fatalf("Expected 2 transactions, 2 skipped and 1 error, got %d, %d and %v", len(block.Transactions), block.Skipped, block.Errors)
+	}
+	for i, position := range []int{0, 4} {
+		tx := block.Transactions[i]
+		if tx.Signature != f.buys[i] || tx.Position != position || tx.Slot != 6000 || len(tx.Trade) != 1 {
+			t.Errorf("Expected the buy %s at position %d, got %s at %d", f.buys[i], position, tx.Signature, tx.Position)
+		}
+	}
+	if !strings.HasPrefix(block.Errors[0].Error(), "transaction 3:") {
+		t.Errorf("Expected the error to name its position, got %v", block.Errors[0])
+	}
+}
+
+func TestParseBlockJSON(t *testing.T) {
+	fixture := newBlockFixture(t)
+	result, _ := json.Marshal(fixture.result)
+	block, err := ParseBlockJSON(result, 6000)
+	if err != nil {
+		t.Fatalf("ParseBlockJSON failed: %v", err)
+	}
+	fixture.check(t, block)
+
+	response, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": fixture.result})
+	block, err = ParseBlockJSON(response, 6000, WithSignatureVerification())
+	if err != nil {
+		t.Fatalf("ParseBlockJSON failed on a JSON-RPC response: %v", err)
+	}
+	fixture.check(t, block)
+
+	if _, err := ParseBlockJSON([]byte("[]"), 6000); err == nil {
+		t.Errorf("Expected an error for JSON that is not a block")
+	}
+}
+
+func TestParseBlockReportsUnreadableEntries(t *testing.T) {
+	buy, buyBytes := signedBuyTransaction(t)
+	result, _ := json.Marshal(map[string]interface{}{
+		"blockhash":  solana.Hash{1}.String(),
+		"parentSlot": 5999,
+		"transactions": []interface{}{
+			map[string]interface{}{"transaction": nil},
+			map[string]interface{}{"transaction": map[string]interface{}{"signatures": []string{}, "message": map[string]interface{}{}}},
+			getTransactionResult(buyBytes, 6000, nil),
+		},
+	})
+
+	block, err := ParseBlockJSON(result, 6000)
+	if err != nil {
+		t.Fatalf("Expected unreadable entries not to fail the block, got %v", err)
+	}
+	if len(block.Transactions) != 1 || block.Transactions[0].Signature != buy.Signatures[0] || block.Transactions[0].Position != 2 {
+		t.Fatalf("Expected the buy at position 2, got %+v", block.Transactions)
+	}
+	if len(block.Errors) != 2 {
+		t.Fatalf("Expected 2 errors, got %v", block.Errors)
+	}
+	for i, err := range block.Errors {
+		if prefix := fmt.Sprintf("transaction %d:", i); !strings.HasPrefix(err.Error(), prefix) {
+			t.Errorf("Expected %q to start with %q", err, prefix)
+		}
+	}
+}
+
+func TestMentionsRaydiumProgram(t *testing.T) {
+	transfer := signedTransferTransaction(t)
+	if mentionsRaydiumProgram(transfer, nil) {
+		t.Errorf("Expected a transfer not to mention Raydium")
+	}
+	// A program account loaded from a lookup table counts as well
+	meta := &rpc.TransactionMeta{LoadedAddresses: rpc.LoadedAddresses{ReadOnly: solana.PublicKeySlice{RaydiumClmmProgramID}}}
+	if !mentionsRaydiumProgram(transfer, meta) {
+		t.Errorf("Expected a loaded Raydium program to be found")
+	}
+}
+
+func TestFetchAndParseBlock(t *testing.T) {
+	fixture := newBlockFixture(t)
+	var params []interface{}
+	fake, url := newFakeEndpoint(t, map[string]func(json.RawMessage) (interface{}, error){
+		"getBlock": func(raw json.RawMessage) (interface{}, error) {
+			json.Unmarshal(raw, &params)
+			return fixture.result, nil
+		},
+	})
+	fetcher := NewRPCFetcher(url).SetRateLimit(0)
+
+	block, err := FetchAndParseBlock(context.Background(), fetcher, 6000)
+	if err != nil {
+		t.Fatalf("FetchAndParseBlock failed: %v", err)
+	}
+	fixture.check(t, block)
+	if fake.count("getBlock") != 1 || len(params) != 2 || params[0] != float64(6000) {
+		t.Fatalf("Unexpected getBlock request %v", params)
+	}
+	options, _ := params[1].(map[string]interface{})
+	if options["encoding"] != "base64" || options["transactionDetails"] != "full" || options["maxSupportedTransactionVersion"] != float64(0) {
+		t.Errorf("Unexpected getBlock options %v", options)
+	}
+}
+
+func TestBlockCommand(t *testing.T) {
+	fixture := newBlockFixture(t)
+	response, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": fixture.result})
+	path := filepath.Join(t.TempDir(), "block.json")
+	if err := os.WriteFile(path, response, 0o644); err != nil {
+		t.Fatalf("Failed to write block file: %v", err)
+	}
+
+	var out bytes.Buffer
+	err := runBlockCommand(context.Background(), []string{"-file", path, "-slot", "6000", "-json"}, &out, nil)
+	if err == nil || !strings.Contains(err.Error(), "1 transactions could not be parsed") {
+		t.Fatalf("Expected the undecodable transaction to be reported, got %v", err)
+	}
+	var record blockRecord
+	if err := json.Unmarshal(out.Bytes(), &record); err != nil {
+		t.Fatalf("Invalid JSON output %s: %v", out.String(), err)
+	}
+	if record.Slot != 6000 || len(record.Transactions) != 2 || record.Transactions[1].Position != 4 || record.Skipped != 2 || len(record.Errors) != 1 {
+		t.Errorf("Unexpected block record %s", out.String())
+	}
+
+	out.Reset()
+	runBlockCommand(context.Background(), []string{"-file", path, "-slot", "6000"}, &out, nil)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "Block 6000 (parent 5999") || !strings.Contains(lines[2], fixture.buys[1].String()) || !strings.HasPrefix(lines[3], "FAILED") {
+		t.Errorf("Unexpected summary output:\n%s", out.String())
+	}
+
+	if err := runBlockCommand(context.Background(), []string{"-file", path}, &out, nil); err == nil {
+		t.Errorf("Expected -slot to be required")
+	}
+}
+
+func TestBlockCommandStdout(t *testing.T) {
+	fixture := newBlockFixture(t)
+	response, _ := json.Marshal(fixture.result)
+	path := filepath.Join(t.TempDir(), "block.json")
+	if err := os.WriteFile(path, response, 0o644); err != nil {
+		t.Fatalf("Failed to write block file: %v", err)
+	}
+
+	// The undecodable transaction makes the command fail after writing the block
+	stdout, _ := runMain(t, nil, "", "block", "-file", path, "-slot", "6000", "-json")
+	var record blockRecord
+	if err := json.Unmarshal([]byte(stdout), &record); err != nil || len(record.Transactions) != 2 {
+		t.Errorf("Expected only the block JSON on stdout, got:\n%s", stdout)
+	}
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+// SOLANA_PACKET_DATA_SIZE is the largest serialized transaction the cluster accepts
+const SOLANA_PACKET_DATA_SIZE = 1232
+
+// Compute budget, associated token account, token and system instruction tags used by the composer
+const (
+	COMPUTE_BUDGET_INSTRUCTION_SET_COMPUTE_UNIT_LIMIT = 2
+	COMPUTE_BUDGET_INSTRUCTION_SET_COMPUTE_UNIT_PRICE = 3
+	ASSOCIATED_TOKEN_INSTRUCTION_CREATE_IDEMPOTENT    = 1
+	TOKEN_INSTRUCTION_SYNC_NATIVE                     = 17
+	SYSTEM_INSTRUCTION_TRANSFER                       = 2
+)
+
+// ErrTransactionTooLarge is returned when a composed transaction exceeds SOLANA_PACKET_DATA_SIZE
+var ErrTransactionTooLarge = errors.New("transaction too large")
+
+// InstructionBuilder is implemented by every instruction builder in this package
+type InstructionBuilder interface {
+	Build() (solana.Instruction, error)
+}
+
+// NewSetComputeUnitLimitInstruction creates a compute budget instruction capping the transaction's compute units
+func NewSetComputeUnitLimitInstruction(units uint32) solana.Instruction {
+	data := binary.LittleEndian.AppendUint32([]byte{COMPUTE_BUDGET_INSTRUCTION_SET_COMPUTE_UNIT_LIMIT}, units)
+	return solana.NewInstruction(ComputeBudgetProgramID, solana.AccountMetaSlice{}, data)
+}
+
+// NewSetComputeUnitPriceInstruction creates a compute budget instruction setting the priority
+// fee in micro-lamports per compute unit
+func NewSetComputeUnitPriceInstruction(microLamports uint64) solana.Instruction {
+	data := binary.LittleEndian.AppendUint64([]byte{COMPUTE_BUDGET_INSTRUCTION_SET_COMPUTE_UNIT_PRICE}, microLamports)
+	return solana.NewInstruction(ComputeBudgetProgramID, solana.AccountMetaSlice{}, data)
+}
+
+// NewCreateAssociatedTokenAccountIdempotentInstruction creates the owner's associated token
+// account for mint, succeeding without changes when it already exists
+func NewCreateAssociatedTokenAccountIdempotentInstruction(payer, owner, mint, tokenProgram solana.PublicKey) (solana.Instruction, error) {
+	account, err := DeriveAssociatedTokenAddress(owner, mint, tokenProgram)
+	if err != nil {
+		return nil, err
+	}
+	accounts := solana.AccountMetaSlice{
+		{PublicKey: payer, IsWritable: true, IsSigner: true},
+		{PublicKey: account, IsWritable: true, IsSigner: false},
+		{PublicKey: owner, IsWritable: false, IsSigner: false},
+		{PublicKey: mint, IsWritable: false, IsSigner: false},
+		{PublicKey: SystemProgramID, IsWritable: false, IsSigner: false},
+		{PublicKey: tokenProgram, IsWritable: false, IsSigner: false},
+	}
+	return solana.NewInstruction(AssociatedTokenProgramID, accounts, []byte{ASSOCIATED_TOKEN_INSTRUCTION_CREATE_IDEMPOTENT}), nil
+}
+
+// NewTransferInstruction creates a system program lamport transfer
+func NewTransferInstruction(from, to solana.PublicKey, lamports uint64) solana.Instruction {
+	data := binary.LittleEndian.AppendUint32(nil, SYSTEM_INSTRUCTION_TRANSFER)
+	data = binary.LittleEndian.AppendUint64(data, lamports)
+	accounts := solana.AccountMetaSlice{
+		{PublicKey: from, IsWritable: true, IsSigner: true},
+		{PublicKey: to, IsWritable: true, IsSigner: false},
+	}
+	return solana.NewInstruction(SystemProgramID, accounts, data)
+}
+
+// NewSyncNativeInstruction updates a wSOL account's token balance to its lamports
+func NewSyncNativeInstruction(account solana.PublicKey) solana.Instruction {
+	accounts := solana.AccountMetaSlice{
+		{PublicKey: account, IsWritable: true, IsSigner: false},
+	}
+	return solana.NewInstruction(TokenProgramID, accounts, []byte{TOKEN_INSTRUCTION_SYNC_NATIVE})
+}
+
+// NewCloseAccountInstruction closes a token account, sending its lamports to destination
+func NewCloseAccountInstruction(account, destination, owner, tokenProgram solana.PublicKey) solana.Instruction {
+	accounts := solana.AccountMetaSlice{
+		{PublicKey: account, IsWritable: true, IsSigner: false},
+		{PublicKey: destination, IsWritable: true, IsSigner: false},
+		{PublicKey: owner, IsWritable: false, IsSigner: true},
+	}
+	return solana.NewInstruction(tokenProgram, accounts, []byte{TOKEN_INSTRUCTION_CLOSE_ACCOUNT})
+}
+
+// TransactionComposer assembles a transaction around the instruction builders: compute
+// budget instructions first, then setup (token account creation and wSOL wrapping), the
+// added instructions in order, and cleanup (wSOL unwrapping) last.
+type TransactionComposer struct {
+	payer            solana.PublicKey
+	recentBlockhash  solana.Hash
+	version          solana.MessageVersion
+	lookupTables     map[solana.PublicKey]solana.PublicKeySlice
+	computeUnitLimit uint32
+	computeUnitPrice uint64
+
+	setup        []solana.Instruction
+	instructions []solana.Instruction
+	cleanup      []solana.Instruction
+	// errs collects builder and derivation failures so Build can report them together
+	errs []error
+}
+
+// NewTransactionComposer creates a composer for a legacy transaction paid by payer
+func NewTransactionComposer(payer solana.PublicKey) *TransactionComposer {
+	return &TransactionComposer{
+		payer:   payer,
+		version: solana.MessageVersionLegacy,
+	}
+}
+
+// SetRecentBlockhash sets the blockhash the transaction is valid for
+func (c *TransactionComposer) SetRecentBlockhash(recentBlockhash solana.Hash) *TransactionComposer {
+	c.recentBlockhash = recentBlockhash
+	return c
+}
+
+// SetVersion selects a legacy or v0 message; adding a lookup table implies v0, and a
+// legacy message cannot use lookup tables
+func (c *TransactionComposer) SetVersion(version solana.MessageVersion) *TransactionComposer {
+	c.version = version
+	return c
+}
+
+// AddLookupTable adds an address lookup table and its addresses, switching to a v0 message
+func (c *TransactionComposer) AddLookupTable(table solana.PublicKey, addresses solana.PublicKeySlice) *TransactionComposer {
+	if c.lookupTables == nil {
+		c.lookupTables = make(map[solana.PublicKey]solana.PublicKeySlice)
+	}
+	c.lookupTables[table] = addresses
+	c.version = solana.MessageVersionV0
+	return c
+}
+
+// SetComputeUnitLimit adds a compute unit limit instruction; zero leaves the default limit
+func (c *TransactionComposer) SetComputeUnitLimit(units uint32) *TransactionComposer {
+	c.computeUnitLimit = units
+	return c
+}
+
+// SetComputeUnitPrice adds a priority fee in micro-lamports per compute unit; zero adds none
+func (c *TransactionComposer) SetComputeUnitPrice(microLamports uint64) *TransactionComposer {
+	c.computeUnitPrice = microLamports
+	return c
+}
+
+// CreateTokenAccount adds an idempotent creation of owner's associated token account for mint
+func (c *TransactionComposer) CreateTokenAccount(owner, mint, tokenProgram solana.PublicKey) *TransactionComposer {
+	instruction, err := NewCreateAssociatedTokenAccountIdempotentInstruction(c.payer, owner, mint, tokenProgram)
+	if err != nil {
+		c.errs = append(c.errs, err)
+		return c
+	}
+	c.setup = append(c.setup, instruction)
+	return c
+}
+
+// WrapSOL creates the payer's wSOL account if needed and deposits lamports into it
+func (c *TransactionComposer) WrapSOL(lamports uint64) *TransactionComposer {
+	account, err := DeriveAssociatedTokenAddress(c.payer, solana.SolMint, TokenProgramID)
+	if err != nil {
+		c.errs = append(c.errs, err)
+		return c
+	}
+	c.CreateTokenAccount(c.payer, solana.SolMint, TokenProgramID)
+	c.setup = append(c.setup,
+		NewTransferInstruction(c.payer, account, lamports),
+		NewSyncNativeInstruction(account),
+	)
+	return c
+}
+
+// UnwrapSOL closes the payer's wSOL account after the other instructions, returning its lamports
+func (c *TransactionComposer) UnwrapSOL() *TransactionComposer {
+	account, err := DeriveAssociatedTokenAddress(c.payer, solana.SolMint, TokenProgramID)
+	if err != nil {
+		c.errs = append(c.errs, err)
+		return c
+	}
+	c.cleanup = append(c.cleanup, NewCloseAccountInstruction(account, c.payer, c.payer, TokenProgramID))
+	return c
+}
+
+// AddInstruction appends an already built instruction
+func (c *TransactionComposer) AddInstruction(instruction solana.Instruction) *TransactionComposer {
+	c.instructions = append(c.instructions, instruction)
+	return c
+}
+
+// AddBuilder builds and appends an instruction; build errors are returned by Build
+func (c *TransactionComposer) AddBuilder(builder InstructionBuilder) *TransactionComposer {
+	instruction, err := builder.Build()
+	if err != nil {
+		c.errs = append(c.errs, err)
+		return c
+	}
+	return c.AddInstruction(instruction)
+}
+
+// Instructions returns every instruction in transaction order
+func (c *TransactionComposer) Instructions() []solana.Instruction {
+	var instructions []solana.Instruction
+	if c.computeUnitLimit > 0 {
+		instructions = append(instructions, NewSetComputeUnitLimitInstruction(c.computeUnitLimit))
+	}
+	if c.computeUnitPrice > 0 {
+		instructions = append(instructions, NewSetComputeUnitPriceInstruction(c.computeUnitPrice))
+	}
+	instructions = append(instructions, c.setup...)
+	instructions = append(instructions, c.instructions...)
+	return append(instructions, c.cleanup...)
+}
+
+// Build assembles the unsigned transaction. It fails when an added builder failed, when
+// the payer, blockhash or instructions are missing, when lookup tables are set for a legacy
+// message, or when the signed transaction would exceed SOLANA_PACKET_DATA_SIZE.
+func (c *TransactionComposer) Build() (*solana.Transaction, error) {
+	if len(c.errs) > 0 {
+		return nil, fmt.Errorf("failed to compose transaction: %w", errors.Join(c.errs...))
+	}
+	if c.payer.IsZero() {
+		return nil, fmt.Errorf("transaction payer is required")
+	}
+	if c.recentBlockhash.IsZero() {
+		return nil, fmt.Errorf("recent blockhash is required")
+	}
+	if len(c.instructions) == 0 && len(c.setup) == 0 && len(c.cleanup) == 0 {
+		return nil, fmt.Errorf("transaction has no instructions")
+	}
+	if len(c.lookupTables) > 0 && c.version == solana.MessageVersionLegacy {
+		return nil, fmt.Errorf("address lookup tables require a v0 message, not legacy")
+	}
+
+	opts := []solana.TransactionOption{solana.TransactionPayer(c.payer)}
+	if len(c.lookupTables) > 0 {
+		opts = append(opts, solana.TransactionAddressTables(c.lookupTables))
+	}
+	tx, err := solana.NewTransaction(c.Instructions(), c.recentBlockhash, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compose transaction: %w", err)
+	}
+	tx.Message.SetVersion(c.version)
+
+	size, err := TransactionSize(tx)
+	if err != nil {
+		return nil, err
+	}
+	if size > SOLANA_PACKET_DATA_SIZE {
+		return nil, fmt.Errorf("%w: %d bytes signed, limit is %d", ErrTransactionTooLarge, size, SOLANA_PACKET_DATA_SIZE)
+	}
+	return tx, nil
+}
+
+// TransactionSize returns the serialized size of tx once every required signature is present
+func TransactionSize(tx *solana.Transaction) (int, error) {
+	message, err := tx.Message.MarshalBinary()
+	if err != nil {
+		return 0, fmt.Errorf("failed to serialize message: %w", err)
+	}
+	signatures := int(tx.Message.Header.NumRequiredSignatures)
+	var signatureCount []byte
+	bin.EncodeCompactU16Length(&signatureCount, signatures)
+	return len(signatureCount) + signatures*64 + len(message), nil
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+func TestTransactionComposerOrdersInstructions(t *testing.T) {
+	keys := uniqueAccounts(4)
+	payer, ammConfig, mint := keys[0], keys[1], keys[2]
+	blockhash := solana.Hash(keys[3])
+
+	tx, err := NewTransactionComposer(payer).
+		SetRecentBlockhash(blockhash).
+		SetComputeUnitLimit(200_000).
+		SetComputeUnitPrice(5_000).
+		WrapSOL(1_000_000).
+		CreateTokenAccount(payer, mint, TokenProgramID).
+		AddBuilder(NewCpSwapInstruction().
+			SetPayer(payer).SetAmmConfig(ammConfig).SetInputMint(solana.SolMint).SetOutputMint(mint).
+			SetAmountIn(1_000_000)).
+		UnwrapSOL().
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to compose transaction: %v", err)
+	}
+
+	if !tx.Message.AccountKeys[0].Equals(payer) {
+		t.Errorf("Expected payer %s as fee payer, got %s", payer, tx.Message.AccountKeys[0])
+	}
+	if tx.Message.RecentBlockhash != blockhash {
+		t.Errorf("Recent blockhash was not set")
+	}
+	if tx.Message.IsVersioned() {
+		t.Errorf("Expected a legacy message")
+	}
+
+	expected := []solana.PublicKey{
+		ComputeBudgetProgramID, ComputeBudgetProgramID,
+		AssociatedTokenProgramID, SystemProgramID, TokenProgramID,
+		AssociatedTokenProgramID,
+		RaydiumCpSwapProgramID,
+		TokenProgramID,
+	}
+	if len(tx.Message.Instructions) != len(expected) {
+		t.Fatalf("Expected %d instructions, got %d", len(expected), len(tx.Message.Instructions))
+	}
+	for i, instruction := range tx.Message.Instructions {
+		program, err := tx.Message.Program(instruction.ProgramIDIndex)
+		if err != nil {
+			t.Fatalf("Failed to resolve program of instruction %d: %v", i, err)
+		}
+		if !program.Equals(expected[i]) {
+			t.Errorf("Instruction %d: expected program %s, got %s", i, expected[i], program)
+		}
+	}
+
+	limit := tx.Message.Instructions[0].Data
+	if len(limit) != 5 || limit[0] != COMPUTE_BUDGET_INSTRUCTION_SET_COMPUTE_UNIT_LIMIT {
+		t.Errorf("Unexpected compute unit limit data %v", limit)
+	}
+	wsol, _ := DeriveAssociatedTokenAddress(payer, solana.SolMint, TokenProgramID)
+	closeAccounts, _ := tx.Message.Instructions[7].ResolveInstructionAccounts(&tx.Message)
+	if !closeAccounts[0].PublicKey.Equals(wsol) || !closeAccounts[1].PublicKey.Equals(payer) {
+		t.Errorf("UnwrapSOL should close the payer's wSOL account")
+	}
+
+	size, err := TransactionSize(tx)
+	if err != nil {
+		t.Fatalf("Failed to size transaction: %v", err)
+	}
+	if size > SOLANA_PACKET_DATA_SIZE {
+		t.Errorf("Composed transaction is %d bytes", size)
+	}
+}
+
+func TestTransactionSizeMatchesSignedTransaction(t *testing.T) {
+	wallet := solana.NewWallet()
+	tx, err := NewTransactionComposer(wallet.PublicKey()).
+		SetRecentBlockhash(solana.Hash(solana.NewWallet().PublicKey())).
+		AddInstruction(NewTransferInstruction(wallet.PublicKey(), solana.NewWallet().PublicKey(), 1_000)).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to compose transaction: %v", err)
+	}
+	size, err := TransactionSize(tx)
+	if err != nil {
+		t.Fatalf("Failed to size transaction: %v", err)
+	}
+
+	if _, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
+		if key.Equals(wallet.PublicKey()) {
+			return &wallet.PrivateKey
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("Failed to sign transaction: %v", err)
+	}
+	signed, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to serialize transaction: %v", err)
+	}
+	if size != len(signed) {
+		t.Errorf("Expected size %d, signed transaction is %d bytes", size, len(signed))
+	}
+}
+
+func TestTransactionComposerLookupTables(t *testing.T) {
+	keys := uniqueAccounts(5)
+	payer, table := keys[0], keys[1]
+	blockhash := solana.Hash(keys[2])
+	instruction := solana.NewInstruction(MemoProgramID, solana.AccountMetaSlice{
+		{PublicKey: payer, IsWritable: true, IsSigner: true},
+		{PublicKey: keys[3], IsWritable: true},
+		{PublicKey: keys[4], IsWritable: false},
+	}, []byte("memo"))
+
+	legacy, err := NewTransactionComposer(payer).SetRecentBlockhash(blockhash).AddInstruction(instruction).Build()
+	if err != nil {
+		t.Fatalf("Failed to compose legacy transaction: %v", err)
+	}
+	v0, err := NewTransactionComposer(payer).
+		SetRecentBlockhash(blockhash).
+		AddLookupTable(table, solana.PublicKeySlice{keys[3], keys[4]}).
+		AddInstruction(instruction).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to compose v0 transaction: %v", err)
+	}
+
+	if !v0.Message.IsVersioned() {
+		t.Fatalf("Expected a v0 message with lookup tables")
+	}
+	if len(v0.Message.AddressTableLookups) != 1 || !v0.Message.AddressTableLookups[0].AccountKey.Equals(table) {
+		t.Errorf("Expected one lookup of %s, got %+v", table, v0.Message.AddressTableLookups)
+	}
+	legacySize, _ := TransactionSize(legacy)
+	v0Size, _ := TransactionSize(v0)
+	if v0Size >= legacySize {
+		t.Errorf("Lookup table should shrink the transaction: legacy %d, v0 %d", legacySize, v0Size)
+	}
+
+	forced, err := NewTransactionComposer(payer).
+		SetRecentBlockhash(blockhash).
+		SetVersion(solana.MessageVersionV0).
+		AddInstruction(instruction).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to compose v0 transaction: %v", err)
+	}
+	if !forced.Message.IsVersioned() {
+		t.Errorf("SetVersion should produce a v0 message without lookup tables")
+	}
+}
+
+func TestTransactionComposerErrors(t *testing.T) {
+	keys := uniqueAccounts(2)
+	payer := keys[0]
+	blockhash := solana.Hash(keys[1])
+	transfer := NewTransferInstruction(payer, keys[1], 1)
+
+	tests := []struct {
+		name     string
+		composer *TransactionComposer
+		expected string
+	}{
+		{"missing payer", NewTransactionComposer(solana.PublicKey{}).SetRecentBlockhash(blockhash).AddInstruction(transfer), "payer is required"},
+		{"missing blockhash", NewTransactionComposer(payer).AddInstruction(transfer), "recent blockhash is required"},
+		{"no instructions", NewTransactionComposer(payer).SetRecentBlockhash(blockhash).SetComputeUnitPrice(1), "no instructions"},
+		{"builder error", NewTransactionComposer(payer).SetRecentBlockhash(blockhash).AddBuilder(NewSwapInstruction()), "missing"},
+		{"legacy with lookup tables", NewTransactionComposer(payer).SetRecentBlockhash(blockhash).AddInstruction(transfer).
+			AddLookupTable(keys[1], solana.PublicKeySlice{keys[1]}).SetVersion(solana.MessageVersionLegacy), "require a v0 message"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.composer.Build()
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	oversized := NewTransactionComposer(payer).SetRecentBlockhash(blockhash)
+	for _, account := range uniqueAccounts(40) {
+		oversized.AddInstruction(NewTransferInstruction(payer, account, 1))
+	}
+	if _, err := oversized.Build(); !errors.Is(err, ErrTransactionTooLarge) {
+		t.Errorf("Expected ErrTransactionTooLarge, got %v", err)
+	}
+}
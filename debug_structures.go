@@ -81,49 +81,21 @@ type EnhancedTokenInfo struct {
 
 // Function to get enhanced token information
 func getEnhancedTokenInfo(tokenMint solana.PublicKey) EnhancedTokenInfo {
-	knownTokens := map[string]EnhancedTokenInfo{
-		"So11111111111111111111111111111111111111112": {
-			Mint:        "So11111111111111111111111111111111111111112",
-			Symbol:      "SOL",
-			Name:        "Solana",
-			Decimals:    9,
-			Supply:      0, // Dynamic supply
-			IsKnown:     true,
-			Description: "Native Solana token",
-		},
-		"EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v": {
-			Mint:        "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v",
-			Symbol:      "USDC",
-			Name:        "USD Coin",
-			Decimals:    6,
-			Supply:      0, // Dynamic supply
-			IsKnown:     true,
-			Description: "USD Coin stablecoin",
-		},
-		"8pf71rxkus6HVhNa9ERdJ571wfPa1a8QKKMsxGkDbonk": {
-			Mint:        "8pf71rxkus6HVhNa9ERdJ571wfPa1a8QKKMsxGkDbonk",
-			Symbol:      "JAMAL", // Based on the transaction context
-			Name:        "Jamal Token",
-			Decimals:    6, // Common default, should be parsed from metadata
-			Supply:      0,
-			IsKnown:     true,
-			Description: "Raydium Launchpad token",
-		},
-	}
-
-	if info, exists := knownTokens[tokenMint.String()]; exists {
-		return info
+	description := "Known token"
+	info, exists := lookupToken(tokenMint)
+	if !exists {
+		info = GetTokenInfo(tokenMint)
+		description = "Unknown token"
 	}
 
-	// Return unknown token info
 	return EnhancedTokenInfo{
 		Mint:        tokenMint.String(),
-		Symbol:      "UNKNOWN",
-		Name:        "Unknown Token",
-		Decimals:    6, // Default decimals
-		Supply:      0,
-		IsKnown:     false,
-		Description: "Unknown token",
+		Symbol:      info.Symbol,
+		Name:        info.Name,
+		Decimals:    info.Decimals,
+		Supply:      0, // Dynamic supply
+		IsKnown:     exists,
+		Description: description,
 	}
 }
 
@@ -0,0 +1,401 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// SOLANA_RPC_ENDPOINT_ENV names a comma separated list of RPC endpoints that replaces the defaults
+const SOLANA_RPC_ENDPOINT_ENV = "SOLANA_RPC_ENDPOINT"
+
+// Default fetcher settings
+const (
+	DEFAULT_FETCH_ATTEMPTS            = 4
+	DEFAULT_FETCH_TIMEOUT             = 10 * time.Second
+	DEFAULT_FETCH_INITIAL_BACKOFF     = 250 * time.Millisecond
+	DEFAULT_FETCH_MAX_BACKOFF         = 5 * time.Second
+	DEFAULT_ENDPOINT_RATE_LIMIT       = 10 // requests per second
+	DEFAULT_CIRCUIT_FAILURE_THRESHOLD = 3
+	DEFAULT_CIRCUIT_COOLDOWN          = 30 * time.Second
+)
+
+// jsonrpcInvalidParams is the JSON-RPC error code for a request no endpoint will accept
+const jsonrpcInvalidParams = -32602
+
+// DefaultRPCEndpoints are the public mainnet endpoints used when none are configured
+var DefaultRPCEndpoints = []string{
+	rpc.MainNetBeta_RPC,
+	"https://solana-api.projectserum.com",
+	"https://solana-mainnet.g.alchemy.com/v2/demo",
+}
+
+// Fetcher errors
+var (
+	ErrNoEndpoints          = errors.New("no RPC endpoints configured")
+	ErrEndpointsUnavailable = errors.New("every RPC endpoint circuit is open")
+	// ErrParseFailed marks a transaction that was fetched but could not be parsed, which
+	// fetching it again will not change
+	ErrParseFailed = errors.New("failed to parse transaction")
+)
+
+// TransactionFetcher retrieves transactions by signature
+type TransactionFetcher interface {
+	// FetchTransaction returns the base64 encoded transaction with its metadata, or
+	// rpc.ErrNotFound when no endpoint knows the signature
+	FetchTransaction(ctx context.Context, signature solana.Signature) (*rpc.GetTransactionResult, error)
+}
+
+// RPCFetcher spreads requests over several RPC endpoints. Each endpoint has its own rate
+// limit and circuit breaker; failed rounds are retried with exponential backoff.
+type RPCFetcher struct {
+	endpoints        []*fetchEndpoint
+	commitment       rpc.CommitmentType
+	attempts         int
+	timeout          time.Duration
+	initialBackoff   time.Duration
+	maxBackoff       time.Duration
+	failureThreshold int
+	cooldown         time.Duration
+}
+
+// fetchEndpoint is one RPC endpoint with its rate limit and circuit breaker state
+type fetchEndpoint struct {
+	url    string
+	client *rpc.Client
+
+	mu        sync.Mutex
+	interval  time.Duration // minimum spacing between requests, zero for unlimited
+	next      time.Time     // earliest time the next request may start
+	failures  int
+	openUntil time.Time // zero while the circuit is closed
+	probing   bool      // a request is testing the endpoint after the cooldown
+}
+
+// NewRPCFetcher creates a fetcher over endpoints, tried in the given order
+func NewRPCFetcher(endpoints ...string) *RPCFetcher {
+	f := &RPCFetcher{
+		commitment:       rpc.CommitmentFinalized,
+		attempts:         DEFAULT_FETCH_ATTEMPTS,
+		timeout:          DEFAULT_FETCH_TIMEOUT,
+		initialBackoff:   DEFAULT_FETCH_INITIAL_BACKOFF,
+		maxBackoff:       DEFAULT_FETCH_MAX_BACKOFF,
+		failureThreshold: DEFAULT_CIRCUIT_FAILURE_THRESHOLD,
+		cooldown:         DEFAULT_CIRCUIT_COOLDOWN,
+	}
+	for _, url := range endpoints {
+		f.endpoints = append(f.endpoints, &fetchEndpoint{
+			url:      url,
+			client:   rpc.New(url),
+			interval: rateLimitInterval(DEFAULT_ENDPOINT_RATE_LIMIT),
+		})
+	}
+	return f
+}
+
+// NewRPCFetcherFromEnv creates a fetcher over the endpoints in SOLANA_RPC_ENDPOINT, falling
+// back to DefaultRPCEndpoints
+func NewRPCFetcherFromEnv() *RPCFetcher {
+	var endpoints []string
+	for _, endpoint := range strings.Split(os.Getenv(SOLANA_RPC_ENDPOINT_ENV), ",") {
+		if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	if len(endpoints) == 0 {
+		endpoints = DefaultRPCEndpoints
+	}
+	return NewRPCFetcher(endpoints...)
+}
+
+// SetCommitment sets the commitment transactions are fetched at
+func (f *RPCFetcher) SetCommitment(commitment rpc.CommitmentType) *RPCFetcher {
+	f.commitment = commitment
+	return f
+}
+
+// SetAttempts sets how many rounds over the endpoints are made before giving up
+func (f *RPCFetcher) SetAttempts(attempts int) *RPCFetcher {
+	f.attempts = max(attempts, 1)
+	return f
+}
+
+// SetTimeout sets the timeout of a single request
+func (f *RPCFetcher) SetTimeout(timeout time.Duration) *RPCFetcher {
+	f.timeout = timeout
+	return f
+}
+
+// SetBackoff sets the delay before the second round, doubled each round up to max
+func (f *RPCFetcher) SetBackoff(initial, max time.Duration) *RPCFetcher {
+	f.initialBackoff = initial
+	f.maxBackoff = max
+	return f
+}
+
+// SetCircuitBreaker opens an endpoint's circuit for cooldown after threshold consecutive
+// failures. Once the cooldown passes a single request probes the endpoint while others skip
+// it; a success closes the circuit and a failure reopens it.
+func (f *RPCFetcher) SetCircuitBreaker(threshold int, cooldown time.Duration) *RPCFetcher {
+	f.failureThreshold = max(threshold, 1)
+	f.cooldown = cooldown
+	return f
+}
+
+// SetRateLimit limits every endpoint to requestsPerSecond; zero removes the limit
+func (f *RPCFetcher) SetRateLimit(requestsPerSecond float64) *RPCFetcher {
+	for _, endpoint := range f.endpoints {
+		endpoint.setInterval(rateLimitInterval(requestsPerSecond))
+	}
+	return f
+}
+
+// SetEndpointRateLimit limits a single endpoint to requestsPerSecond
+func (f *RPCFetcher) SetEndpointRateLimit(url string, requestsPerSecond float64) *RPCFetcher {
+	for _, endpoint := range f.endpoints {
+		if endpoint.url == url {
+			endpoint.setInterval(rateLimitInterval(requestsPerSecond))
+		}
+	}
+	return f
+}
+
+// FetchTransaction implements TransactionFetcher
+func (f *RPCFetcher) FetchTransaction(ctx context.Context, signature solana.Signature) (*rpc.GetTransactionResult, error) {
+	var result *rpc.GetTransactionResult
+	err := f.Call(ctx, func(ctx context.Context, client *rpc.Client) error {
+		response, err := client.GetTransaction(ctx, signature, getTransactionOpts(f.commitment))
+		if err != nil {
+			return err
+		}
+		if response.Transaction == nil {
+			return rpc.ErrNotFound
+		}
+		result = response
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transaction %s: %w", signature, err)
+	}
+	return result, nil
+}
+
+// FetchBlock implements BlockFetcher
+func (f *RPCFetcher) FetchBlock(ctx context.Context, slot uint64) (*rpc.GetBlockResult, error) {
+	rewards := false
+	var block *rpc.GetBlockResult
+	err := f.Call(ctx, func(ctx context.Context, client *rpc.Client) error {
+		result, err := client.GetBlockWithOpts(ctx, slot, &rpc.GetBlockOpts{
+			Encoding:                       solana.EncodingBase64,
+			TransactionDetails:             rpc.TransactionDetailsFull,
+			Rewards:                        &rewards,
+			Commitment:                     f.commitment,
+			MaxSupportedTransactionVersion: &maxSupportedTransactionVersion,
+		})
+		block = result
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch block %d: %w", slot, err)
+	}
+	return block, nil
+}
+
+// ListSignatures implements SignatureLister
+func (f *RPCFetcher) ListSignatures(ctx context.Context, address solana.PublicKey, before, until solana.Signature, limit int) ([]*rpc.TransactionSignature, error) {
+	// getSignaturesForAddress does not support processed commitment
+	commitment := f.commitment
+	if commitment == rpc.CommitmentProcessed {
+		commitment = rpc.CommitmentConfirmed
+	}
+	var signatures []*rpc.TransactionSignature
+	err := f.Call(ctx, func(ctx context.Context, client *rpc.Client) error {
+		page, err := client.GetSignaturesForAddressWithOpts(ctx, address, &rpc.GetSignaturesForAddressOpts{
+			Limit:      &limit,
+			Before:     before,
+			Until:      until,
+			Commitment: commitment,
+		})
+		signatures = page
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list signatures for %s: %w", address, err)
+	}
+	return signatures, nil
+}
+
+// Call runs request against the endpoints until one succeeds, honouring the rate limits,
+// circuit breakers and backoff. rpc.ErrNotFound moves on to the next endpoint without
+// counting as a failure and is returned once every endpoint reports it. Invalid params
+// errors are returned immediately.
+func (f *RPCFetcher) Call(ctx context.Context, request func(ctx context.Context, client *rpc.Client) error) error {
+	if len(f.endpoints) == 0 {
+		return ErrNoEndpoints
+	}
+
+	backoff := f.initialBackoff
+	var lastErr error
+	for attempt := 0; attempt < f.attempts; attempt++ {
+		if attempt > 0 {
+			if err := sleepContext(ctx, backoff); err != nil {
+				return err
+			}
+			backoff = min(backoff*2, f.maxBackoff)
+		}
+
+		tried, notFound := 0, 0
+		for _, endpoint := range f.endpoints {
+			if !endpoint.available() {
+				continue
+			}
+			tried++
+			if err := endpoint.wait(ctx); err != nil {
+				endpoint.abandoned()
+				return err
+			}
+
+			requestCtx, cancel := context.WithTimeout(ctx, f.timeout)
+			err := request(requestCtx, endpoint.client)
+			cancel()
+
+			switch {
+			case err == nil:
+				endpoint.succeeded()
+				return nil
+			case ctx.Err() != nil:
+				endpoint.abandoned()
+				return ctx.Err()
+			case errors.Is(err, rpc.ErrNotFound):
+				endpoint.succeeded()
+				notFound++
+			case isInvalidParams(err):
+				endpoint.succeeded()
+				return fmt.Errorf("%s: %w", endpoint.url, err)
+			default:
+				endpoint.failed(f.failureThreshold, f.cooldown)
+				lastErr = fmt.Errorf("%s: %w", endpoint.url, err)
+				log.Printf("RPC endpoint %s failed: %v", endpoint.url, err)
+			}
+		}
+
+		if tried == 0 {
+			lastErr = ErrEndpointsUnavailable
+		} else if notFound == tried {
+			return rpc.ErrNotFound
+		}
+	}
+	return fmt.Errorf("all RPC endpoints failed after %d attempts: %w", f.attempts, lastErr)
+}
+
+// FetchAndParseTransaction fetches signature and parses it together with its metadata
+func FetchAndParseTransaction(ctx context.Context, fetcher TransactionFetcher, signature solana.Signature, opts ...ParseOption) (*Transaction, error) {
+	result, err := fetcher.FetchTransaction(ctx, signature)
+	if err != nil {
+		return nil, err
+	}
+	transaction, err := ParseTransactionResult(result, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrParseFailed, signature, err)
+	}
+	if transaction.Signature != signature {
+		return nil, fmt.Errorf("fetched %s for %s: %w", transaction.Signature, signature, ErrSignatureMismatch)
+	}
+	return transaction, nil
+}
+
+func (e *fetchEndpoint) setInterval(interval time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.interval = interval
+}
+
+// available reports whether a request may go to the endpoint: its circuit is closed, or
+// its cooldown has passed and no other request is probing it. A request let through must
+// report its outcome with succeeded, failed or abandoned.
+func (e *fetchEndpoint) available() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.openUntil.IsZero() {
+		return true
+	}
+	if e.probing || time.Now().Before(e.openUntil) {
+		return false
+	}
+	e.probing = true
+	return true
+}
+
+// wait reserves the endpoint's next request slot and sleeps until it starts
+func (e *fetchEndpoint) wait(ctx context.Context) error {
+	e.mu.Lock()
+	now := time.Now()
+	start := now
+	if e.next.After(now) {
+		start = e.next
+	}
+	e.next = start.Add(e.interval)
+	e.mu.Unlock()
+	return sleepContext(ctx, start.Sub(now))
+}
+
+func (e *fetchEndpoint) succeeded() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.failures = 0
+	e.openUntil = time.Time{}
+	e.probing = false
+}
+
+func (e *fetchEndpoint) failed(threshold int, cooldown time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.failures++
+	e.probing = false
+	if e.failures >= threshold {
+		e.openUntil = time.Now().Add(cooldown)
+	}
+}
+
+// abandoned releases a probe cut short by cancellation so the next request can probe
+func (e *fetchEndpoint) abandoned() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.probing = false
+}
+
+func rateLimitInterval(requestsPerSecond float64) time.Duration {
+	if requestsPerSecond <= 0 {
+		return 0
+	}
+	return time.Duration(float64(time.Second) / requestsPerSecond)
+}
+
+func isInvalidParams(err error) bool {
+	var rpcErr *jsonrpc.RPCError
+	return errors.As(err, &rpcErr) && rpcErr.Code == jsonrpcInvalidParams
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
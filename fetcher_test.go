@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+func failingEndpoint(t *testing.T) (*fakeRPC, string) {
+	return newFakeEndpoint(t, map[string]func(json.RawMessage) (interface{}, error){
+		"getTransaction": func(json.RawMessage) (interface{}, error) { return nil, errors.New("node is behind") },
+	})
+}
+
+// fastFetcher has no rate limit and millisecond backoff
+func fastFetcher(endpoints ...string) *RPCFetcher {
+	return NewRPCFetcher(endpoints...).
+		SetRateLimit(0).
+		SetBackoff(time.Millisecond, 4*time.Millisecond)
+}
+
+func TestRPCFetcherFailover(t *testing.T) {
+	tx, txBytes := signedBuyTransaction(t)
+	signature := tx.Signatures[0]
+
+	broken, brokenURL := failingEndpoint(t)
+	healthy, healthyURL := newFakeEndpoint(t, map[string]func(json.RawMessage) (interface{}, error){
+		"getTransaction": func(params json.RawMessage) (interface{}, error) {
+			var args []interface{}
+			json.Unmarshal(params, &args)
+			if options, _ := args[1].(map[string]interface{}); options["encoding"] != "base64" || options["maxSupportedTransactionVersion"] != 0.0 {
+				t.Errorf("Unexpected getTransaction options %v", args[1])
+			}
+			return getTransactionResult(txBytes, 777, nil), nil
+		},
+	})
+
+	fetcher := fastFetcher(brokenURL, healthyURL).SetCircuitBreaker(2, time.Hour)
+	for i := 0; i < 3; i++ {
+		transaction, err := FetchAndParseTransaction(context.Background(), fetcher, signature, WithSignatureVerification())
+		if err != nil {
+			t.Fatalf("Fetch %d failed: %v", i, err)
+		}
+		if transaction.Signature != signature || transaction.Slot != 777 || len(transaction.Trade) != 1 {
+			t.Errorf("Unexpected transaction %s in slot %d with %d trades", transaction.Signature, transaction.Slot, len(transaction.Trade))
+		}
+	}
+	// The broken endpoint's circuit opens after two failures and the third fetch skips it
+	if broken.count("getTransaction") != 2 || healthy.count("getTransaction") != 3 {
+		t.Errorf("Expected 2 broken and 3 healthy calls, got %d and %d", broken.count("getTransaction"), healthy.count("getTransaction"))
+	}
+
+	if _, err := FetchAndParseTransaction(context.Background(), fetcher, solana.Signature{1}); !errors.Is(err, ErrSignatureMismatch) {
+		t.Errorf("Expected a mismatch for a different signature, got %v", err)
+	}
+}
+
+func TestRPCFetcherRetries(t *testing.T) {
+	signature := solana.Signature{1}
+
+	t.Run("not found", func(t *testing.T) {
+		missing := func(json.RawMessage) (interface{}, error) { return nil, nil }
+		first, firstURL := newFakeEndpoint(t, map[string]func(json.RawMessage) (interface{}, error){"getTransaction": missing})
+		second, secondURL := newFakeEndpoint(t, map[string]func(json.RawMessage) (interface{}, error){"getTransaction": missing})
+		_, err := fastFetcher(firstURL, secondURL).FetchTransaction(context.Background(), signature)
+		if !errors.Is(err, rpc.ErrNotFound) {
+			t.Errorf("Expected rpc.ErrNotFound, got %v", err)
+		}
+		if first.count("getTransaction") != 1 || second.count("getTransaction") != 1 {
+			t.Errorf("Expected each endpoint to be asked once")
+		}
+	})
+
+	t.Run("backoff", func(t *testing.T) {
+		broken, url := failingEndpoint(t)
+		fetcher := NewRPCFetcher(url).SetRateLimit(0).SetAttempts(3).SetBackoff(20*time.Millisecond, 30*time.Millisecond)
+		start := time.Now()
+		if _, err := fetcher.FetchTransaction(context.Background(), signature); err == nil {
+			t.Fatalf("Expected the fetch to fail")
+		}
+		if broken.count("getTransaction") != 3 {
+			t.Errorf("Expected 3 attempts, got %d", broken.count("getTransaction"))
+		}
+		if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+			t.Errorf("Expected 20ms and 30ms backoffs, finished in %v", elapsed)
+		}
+	})
+
+	t.Run("circuits open", func(t *testing.T) {
+		broken, url := failingEndpoint(t)
+		_, err := fastFetcher(url).SetCircuitBreaker(1, time.Hour).FetchTransaction(context.Background(), signature)
+		if !errors.Is(err, ErrEndpointsUnavailable) || broken.count("getTransaction") != 1 {
+			t.Errorf("Expected ErrEndpointsUnavailable after one call, got %v after %d", err, broken.count("getTransaction"))
+		}
+	})
+
+	t.Run("invalid params", func(t *testing.T) {
+		rejecting, url := newFakeEndpoint(t, map[string]func(json.RawMessage) (interface{}, error){
+			"getTransaction": func(json.RawMessage) (interface{}, error) {
+				return nil, &jsonrpc.RPCError{Code: jsonrpcInvalidParams, Message: "Invalid param"}
+			},
+		})
+		if _, err := fastFetcher(url).FetchTransaction(context.Background(), signature); err == nil || rejecting.count("getTransaction") != 1 {
+			t.Errorf("Expected invalid params to fail without retrying, got %v", err)
+		}
+	})
+
+	t.Run("context cancelled", func(t *testing.T) {
+		_, url := failingEndpoint(t)
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, err := NewRPCFetcher(url).SetBackoff(time.Hour, time.Hour).FetchTransaction(ctx, signature)
+		if !errors.Is(err, context.DeadlineExceeded) || time.Since(start) > time.Second {
+			t.Errorf("Expected the backoff to stop at the deadline, got %v", err)
+		}
+	})
+}
+
+func TestRPCFetcherCircuitHalfOpen(t *testing.T) {
+	tx, txBytes := signedBuyTransaction(t)
+	var healthy atomic.Bool
+	fake, url := newFakeEndpoint(t, map[string]func(json.RawMessage) (interface{}, error){
+		"getTransaction": func(json.RawMessage) (interface{}, error) {
+			if healthy.Load() {
+				return getTransactionResult(txBytes, 777, nil), nil
+			}
+			time.Sleep(50 * time.Millisecond)
+			return nil, errors.New("node is behind")
+		},
+	})
+	fetcher := fastFetcher(url).SetAttempts(1).SetCircuitBreaker(1, 20*time.Millisecond)
+	fetchAll := func(n int) []error {
+		errs := make([]error, n)
+		var wg sync.WaitGroup
+		for i := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, errs[i] = fetcher.FetchTransaction(context.Background(), tx.Signatures[0])
+			}()
+		}
+		wg.Wait()
+		return errs
+	}
+
+	fetchAll(1)
+	time.Sleep(30 * time.Millisecond)
+	// After the cooldown only one of the concurrent callers probes the endpoint
+	unavailable := 0
+	for _, err := range fetchAll(8) {
+		if errors.Is(err, ErrEndpointsUnavailable) {
+			unavailable++
+		}
+	}
+	if calls := fake.count("getTransaction"); calls != 2 || unavailable != 7 {
+		t.Fatalf("Expected a single probe, got %d calls and %d callers turned away", calls, unavailable)
+	}
+
+	// The failed probe reopened the circuit; a successful one closes it for everyone
+	healthy.Store(true)
+	if errs := fetchAll(1); !errors.Is(errs[0], ErrEndpointsUnavailable) {
+		t.Errorf("Expected the circuit to be open again, got %v", errs[0])
+	}
+	time.Sleep(30 * time.Millisecond)
+	if errs := fetchAll(1); errs[0] != nil {
+		t.Fatalf("Expected the probe to succeed, got %v", errs[0])
+	}
+	for _, err := range fetchAll(4) {
+		if err != nil {
+			t.Errorf("Expected a closed circuit, got %v", err)
+		}
+	}
+	if calls := fake.count("getTransaction"); calls != 7 {
+		t.Errorf("Expected 7 calls in total, got %d", calls)
+	}
+}
+
+func TestRPCFetcherRateLimit(t *testing.T) {
+	fake, url := newFakeEndpoint(t, map[string]func(json.RawMessage) (interface{}, error){
+		"getSlot": func(json.RawMessage) (interface{}, error) { return 1, nil },
+	})
+	fetcher := NewRPCFetcher(url).SetEndpointRateLimit(url, 50)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		err := fetcher.Call(context.Background(), func(ctx context.Context, client *rpc.Client) error {
+			_, err := client.GetSlot(ctx, rpc.CommitmentProcessed)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("Call failed: %v", err)
+		}
+	}
+	// 50 requests per second spaces five requests at least 80ms apart end to end
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond || fake.count("getSlot") != 5 {
+		t.Errorf("Expected 5 rate limited calls over 80ms, got %d in %v", fake.count("getSlot"), elapsed)
+	}
+}
+
+func TestNewRPCFetcherFromEnv(t *testing.T) {
+	t.Setenv(SOLANA_RPC_ENDPOINT_ENV, " http://a.example , http://b.example,")
+	fetcher := NewRPCFetcherFromEnv()
+	if len(fetcher.endpoints) != 2 || fetcher.endpoints[0].url != "http://a.example" || fetcher.endpoints[1].url != "http://b.example" {
+		t.Errorf("Unexpected endpoints %+v", fetcher.endpoints)
+	}
+
+	t.Setenv(SOLANA_RPC_ENDPOINT_ENV, "")
+	if fetcher := NewRPCFetcherFromEnv(); len(fetcher.endpoints) != len(DefaultRPCEndpoints) {
+		t.Errorf("Expected the default endpoints, got %d", len(fetcher.endpoints))
+	}
+
+	if err := NewRPCFetcher().Call(context.Background(), nil); !errors.Is(err, ErrNoEndpoints) {
+		t.Errorf("Expected ErrNoEndpoints, got %v", err)
+	}
+}
@@ -2,11 +2,31 @@ package main
 
 import (
 	"encoding/binary"
+	"fmt"
 
 	"github.com/gagliardetto/solana-go"
 )
 
-// SwapInstruction represents a Raydium swap instruction
+// AmmV4AuthorityID is the AMM v4 pool authority, the program address of seed "amm authority"
+var AmmV4AuthorityID = solana.MustPublicKeyFromBase58("5Q544fKrFoe6tsEbD7S8EmxGTJYAKtTVhAW5Q5pge4j1")
+
+// AMM v4 swap instruction tags. The V2 variants take the shorter account list without
+// the OpenBook market accounts.
+const (
+	AMM_V4_INSTRUCTION_SWAP_BASE_IN     = 9
+	AMM_V4_INSTRUCTION_SWAP_BASE_OUT    = 11
+	AMM_V4_INSTRUCTION_SWAP_BASE_IN_V2  = 16
+	AMM_V4_INSTRUCTION_SWAP_BASE_OUT_V2 = 17
+)
+
+// AMM v4 swap account counts with and without the OpenBook market accounts
+const (
+	AMM_V4_SWAP_ACCOUNTS_LENGTH                  = 18
+	AMM_V4_SWAP_WITHOUT_OPENBOOK_ACCOUNTS_LENGTH = 8
+)
+
+// SwapInstruction represents a Raydium AMM v4 swap instruction. It builds swapBaseIn by
+// default; SetBaseOut switches to swapBaseOut and SetWithoutOpenBook to the V2 layout.
 type SwapInstruction struct {
 	programID        solana.PublicKey
 	userSourceToken  solana.PublicKey
@@ -26,8 +46,20 @@ type SwapInstruction struct {
 	serumCoinVault   solana.PublicKey
 	serumPcVault     solana.PublicKey
 	serumVaultSigner solana.PublicKey
-	amountIn         uint64
-	minimumAmountOut uint64
+	baseOut          bool
+	withoutOpenBook  bool
+
+	// swapBaseIn amounts; expectedAmountOut is the quoted output and, when set,
+	// minimumAmountOut may not exceed it
+	amountIn          uint64
+	minimumAmountOut  uint64
+	expectedAmountOut uint64
+
+	// swapBaseOut amounts; expectedAmountIn is the quoted input and, when set,
+	// maxAmountIn must cover it
+	maxAmountIn      uint64
+	amountOut        uint64
+	expectedAmountIn uint64
 }
 
 // NewSwapInstruction creates a new swap instruction builder
@@ -37,6 +69,81 @@ func NewSwapInstruction() *SwapInstruction {
 	}
 }
 
+// NewSwapBaseInInstruction creates a swap builder that sells exactly amountIn
+func NewSwapBaseInInstruction(amountIn, minimumAmountOut uint64) *SwapInstruction {
+	return NewSwapInstruction().SetAmountIn(amountIn).SetMinimumAmountOut(minimumAmountOut)
+}
+
+// NewSwapBaseOutInstruction creates a swap builder that buys exactly amountOut
+func NewSwapBaseOutInstruction(maxAmountIn, amountOut uint64) *SwapInstruction {
+	return NewSwapInstruction().SetBaseOut(true).SetMaxAmountIn(maxAmountIn).SetAmountOut(amountOut)
+}
+
+// NewSwapInstructionFromPool creates a swap instruction with every account filled from a
+// decoded AMM v4 pool and its OpenBook market. The user's source and destination accounts
+// are the wallet's associated token accounts for inputMint and the other pool mint.
+func NewSwapInstructionFromPool(pool *PoolInfo, market *OpenBookMarket, wallet, inputMint solana.PublicKey, amountIn, minimumAmountOut uint64) (*SwapInstruction, error) {
+	if pool == nil || market == nil {
+		return nil, fmt.Errorf("pool and market are required")
+	}
+	if !pool.IsAmmV4() {
+		return nil, fmt.Errorf("pool %s is not an AMM v4 pool", pool.Address)
+	}
+	if !market.Address.Equals(pool.MarketID) {
+		return nil, fmt.Errorf("market %s does not match pool market %s", market.Address, pool.MarketID)
+	}
+
+	var outputMint solana.PublicKey
+	switch {
+	case inputMint.Equals(pool.TokenA):
+		outputMint = pool.TokenB
+	case inputMint.Equals(pool.TokenB):
+		outputMint = pool.TokenA
+	default:
+		return nil, fmt.Errorf("mint %s is not traded by pool %s", inputMint, pool.Address)
+	}
+
+	// Fill in the program on a copy so the caller's market is left as it was
+	marketCopy := *market
+	market = &marketCopy
+	if market.ProgramID.IsZero() {
+		market.ProgramID = pool.MarketProgramID
+	}
+	vaultSigner, err := market.VaultSigner()
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive market vault signer: %w", err)
+	}
+	source, err := DeriveAssociatedTokenAddress(wallet, inputMint, TokenProgramID)
+	if err != nil {
+		return nil, err
+	}
+	destination, err := DeriveAssociatedTokenAddress(wallet, outputMint, TokenProgramID)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSwapInstruction().
+		SetProgramID(pool.ProgramID).
+		SetUserSourceToken(source).
+		SetUserDestToken(destination).
+		SetUserOwner(wallet).
+		SetAmmID(pool.Address).
+		SetAmmOpenOrders(pool.OpenOrders).
+		SetAmmTargetOrders(pool.TargetOrders).
+		SetPoolCoinToken(pool.TokenAVault).
+		SetPoolPcToken(pool.TokenBVault).
+		SetSerumProgram(market.ProgramID).
+		SetSerumMarket(market.Address).
+		SetSerumBids(market.Bids).
+		SetSerumAsks(market.Asks).
+		SetSerumEventQueue(market.EventQueue).
+		SetSerumCoinVault(market.BaseVault).
+		SetSerumPcVault(market.QuoteVault).
+		SetSerumVaultSigner(vaultSigner).
+		SetAmountIn(amountIn).
+		SetMinimumAmountOut(minimumAmountOut), nil
+}
+
 // SetProgramID sets the program ID for the swap instruction
 func (s *SwapInstruction) SetProgramID(programID solana.PublicKey) *SwapInstruction {
 	s.programID = programID
@@ -67,7 +174,7 @@ func (s *SwapInstruction) SetAmmID(ammID solana.PublicKey) *SwapInstruction {
 	return s
 }
 
-// SetAmmAuthority sets the AMM authority
+// SetAmmAuthority sets the AMM authority; Build derives it from the program ID when unset
 func (s *SwapInstruction) SetAmmAuthority(ammAuthority solana.PublicKey) *SwapInstruction {
 	s.ammAuthority = ammAuthority
 	return s
@@ -157,494 +264,149 @@ func (s *SwapInstruction) SetMinimumAmountOut(minimumAmountOut uint64) *SwapInst
 	return s
 }
 
-// Build creates the Solana instruction
-func (s *SwapInstruction) Build() (solana.Instruction, error) {
-	// Build instruction data
-	data := make([]byte, 17) // 1 byte discriminator + 8 bytes amountIn + 8 bytes minimumAmountOut
-	data[0] = INSTRUCTION_SWAP
-	binary.LittleEndian.PutUint64(data[1:9], s.amountIn)
-	binary.LittleEndian.PutUint64(data[9:17], s.minimumAmountOut)
-
-	// Build accounts slice
-	accounts := solana.AccountMetaSlice{
-		{PublicKey: s.userSourceToken, IsWritable: true, IsSigner: false},
-		{PublicKey: s.userDestToken, IsWritable: true, IsSigner: false},
-		{PublicKey: s.userOwner, IsWritable: false, IsSigner: true},
-		{PublicKey: s.ammID, IsWritable: true, IsSigner: false},
-		{PublicKey: s.ammAuthority, IsWritable: false, IsSigner: false},
-		{PublicKey: s.ammOpenOrders, IsWritable: true, IsSigner: false},
-		{PublicKey: s.ammTargetOrders, IsWritable: true, IsSigner: false},
-		{PublicKey: s.poolCoinToken, IsWritable: true, IsSigner: false},
-		{PublicKey: s.poolPcToken, IsWritable: true, IsSigner: false},
-		{PublicKey: s.serumProgram, IsWritable: false, IsSigner: false},
-		{PublicKey: s.serumMarket, IsWritable: true, IsSigner: false},
-		{PublicKey: s.serumBids, IsWritable: true, IsSigner: false},
-		{PublicKey: s.serumAsks, IsWritable: true, IsSigner: false},
-		{PublicKey: s.serumEventQueue, IsWritable: true, IsSigner: false},
-		{PublicKey: s.serumCoinVault, IsWritable: true, IsSigner: false},
-		{PublicKey: s.serumPcVault, IsWritable: true, IsSigner: false},
-		{PublicKey: s.serumVaultSigner, IsWritable: false, IsSigner: false},
-		{PublicKey: TokenProgramID, IsWritable: false, IsSigner: false},
-	}
-
-	return solana.NewInstruction(
-		s.programID,
-		accounts,
-		data,
-	), nil
-}
-
-// BuyInstruction represents a Raydium buy instruction
-type BuyInstruction struct {
-	programID        solana.PublicKey
-	userAuthority    solana.PublicKey
-	userTokenAccount solana.PublicKey
-	userSolAccount   solana.PublicKey
-	ammID            solana.PublicKey
-	ammAuthority     solana.PublicKey
-	tokenVault       solana.PublicKey
-	solVault         solana.PublicKey
-	tokenMint        solana.PublicKey
-	amount           uint64
-	maxSolCost       uint64
-}
-
-// NewBuyInstruction creates a new buy instruction builder
-func NewBuyInstruction() *BuyInstruction {
-	return &BuyInstruction{
-		programID: RaydiumLaunchpadV1ProgramID,
-	}
-}
-
-// SetProgramID sets the program ID for the buy instruction
-func (b *BuyInstruction) SetProgramID(programID solana.PublicKey) *BuyInstruction {
-	b.programID = programID
-	return b
-}
-
-// SetUserAuthority sets the user authority
-func (b *BuyInstruction) SetUserAuthority(userAuthority solana.PublicKey) *BuyInstruction {
-	b.userAuthority = userAuthority
-	return b
-}
-
-// SetUserTokenAccount sets the user token account
-func (b *BuyInstruction) SetUserTokenAccount(userTokenAccount solana.PublicKey) *BuyInstruction {
-	b.userTokenAccount = userTokenAccount
-	return b
-}
-
-// SetUserSolAccount sets the user SOL account
-func (b *BuyInstruction) SetUserSolAccount(userSolAccount solana.PublicKey) *BuyInstruction {
-	b.userSolAccount = userSolAccount
-	return b
-}
-
-// SetAmmID sets the AMM ID
-func (b *BuyInstruction) SetAmmID(ammID solana.PublicKey) *BuyInstruction {
-	b.ammID = ammID
-	return b
-}
-
-// SetAmmAuthority sets the AMM authority
-func (b *BuyInstruction) SetAmmAuthority(ammAuthority solana.PublicKey) *BuyInstruction {
-	b.ammAuthority = ammAuthority
-	return b
-}
-
-// SetTokenVault sets the token vault
-func (b *BuyInstruction) SetTokenVault(tokenVault solana.PublicKey) *BuyInstruction {
-	b.tokenVault = tokenVault
-	return b
-}
-
-// SetSolVault sets the SOL vault
-func (b *BuyInstruction) SetSolVault(solVault solana.PublicKey) *BuyInstruction {
-	b.solVault = solVault
-	return b
-}
-
-// SetTokenMint sets the token mint
-func (b *BuyInstruction) SetTokenMint(tokenMint solana.PublicKey) *BuyInstruction {
-	b.tokenMint = tokenMint
-	return b
-}
-
-// SetAmount sets the amount to buy
-func (b *BuyInstruction) SetAmount(amount uint64) *BuyInstruction {
-	b.amount = amount
-	return b
-}
-
-// SetMaxSolCost sets the maximum SOL cost
-func (b *BuyInstruction) SetMaxSolCost(maxSolCost uint64) *BuyInstruction {
-	b.maxSolCost = maxSolCost
-	return b
-}
-
-// Build creates the Solana instruction
-func (b *BuyInstruction) Build() (solana.Instruction, error) {
-	// Build instruction data
-	data := make([]byte, 17) // 1 byte discriminator + 8 bytes amount + 8 bytes maxSolCost
-	data[0] = INSTRUCTION_BUY
-	binary.LittleEndian.PutUint64(data[1:9], b.amount)
-	binary.LittleEndian.PutUint64(data[9:17], b.maxSolCost)
-
-	// Build accounts slice
-	accounts := solana.AccountMetaSlice{
-		{PublicKey: b.userAuthority, IsWritable: false, IsSigner: true},
-		{PublicKey: b.userTokenAccount, IsWritable: true, IsSigner: false},
-		{PublicKey: b.userSolAccount, IsWritable: true, IsSigner: false},
-		{PublicKey: b.ammID, IsWritable: true, IsSigner: false},
-		{PublicKey: b.ammAuthority, IsWritable: false, IsSigner: false},
-		{PublicKey: b.tokenVault, IsWritable: true, IsSigner: false},
-		{PublicKey: b.solVault, IsWritable: true, IsSigner: false},
-		{PublicKey: b.tokenMint, IsWritable: false, IsSigner: false},
-		{PublicKey: TokenProgramID, IsWritable: false, IsSigner: false},
-		{PublicKey: SystemProgramID, IsWritable: false, IsSigner: false},
-	}
-
-	return solana.NewInstruction(
-		b.programID,
-		accounts,
-		data,
-	), nil
-}
-
-// SellInstruction represents a Raydium sell instruction
-type SellInstruction struct {
-	programID        solana.PublicKey
-	userAuthority    solana.PublicKey
-	userTokenAccount solana.PublicKey
-	userSolAccount   solana.PublicKey
-	ammID            solana.PublicKey
-	ammAuthority     solana.PublicKey
-	tokenVault       solana.PublicKey
-	solVault         solana.PublicKey
-	tokenMint        solana.PublicKey
-	amount           uint64
-	minSolReceived   uint64
-}
-
-// NewSellInstruction creates a new sell instruction builder
-func NewSellInstruction() *SellInstruction {
-	return &SellInstruction{
-		programID: RaydiumLaunchpadV1ProgramID,
-	}
-}
-
-// SetProgramID sets the program ID for the sell instruction
-func (s *SellInstruction) SetProgramID(programID solana.PublicKey) *SellInstruction {
-	s.programID = programID
-	return s
-}
-
-// SetUserAuthority sets the user authority
-func (s *SellInstruction) SetUserAuthority(userAuthority solana.PublicKey) *SellInstruction {
-	s.userAuthority = userAuthority
-	return s
-}
-
-// SetUserTokenAccount sets the user token account
-func (s *SellInstruction) SetUserTokenAccount(userTokenAccount solana.PublicKey) *SellInstruction {
-	s.userTokenAccount = userTokenAccount
+// SetExpectedAmountOut sets the quoted amount out used to sanity-check the minimum
+func (s *SwapInstruction) SetExpectedAmountOut(expectedAmountOut uint64) *SwapInstruction {
+	s.expectedAmountOut = expectedAmountOut
 	return s
 }
 
-// SetUserSolAccount sets the user SOL account
-func (s *SellInstruction) SetUserSolAccount(userSolAccount solana.PublicKey) *SellInstruction {
-	s.userSolAccount = userSolAccount
+// SetBaseOut selects swapBaseOut (exact output) instead of swapBaseIn
+func (s *SwapInstruction) SetBaseOut(baseOut bool) *SwapInstruction {
+	s.baseOut = baseOut
 	return s
 }
 
-// SetAmmID sets the AMM ID
-func (s *SellInstruction) SetAmmID(ammID solana.PublicKey) *SellInstruction {
-	s.ammID = ammID
-	return s
-}
-
-// SetAmmAuthority sets the AMM authority
-func (s *SellInstruction) SetAmmAuthority(ammAuthority solana.PublicKey) *SellInstruction {
-	s.ammAuthority = ammAuthority
+// SetMaxAmountIn sets the maximum amount in for swapBaseOut
+func (s *SwapInstruction) SetMaxAmountIn(maxAmountIn uint64) *SwapInstruction {
+	s.maxAmountIn = maxAmountIn
 	return s
 }
 
-// SetTokenVault sets the token vault
-func (s *SellInstruction) SetTokenVault(tokenVault solana.PublicKey) *SellInstruction {
-	s.tokenVault = tokenVault
+// SetAmountOut sets the exact amount out for swapBaseOut
+func (s *SwapInstruction) SetAmountOut(amountOut uint64) *SwapInstruction {
+	s.amountOut = amountOut
 	return s
 }
 
-// SetSolVault sets the SOL vault
-func (s *SellInstruction) SetSolVault(solVault solana.PublicKey) *SellInstruction {
-	s.solVault = solVault
+// SetExpectedAmountIn sets the quoted amount in used to sanity-check the maximum
+func (s *SwapInstruction) SetExpectedAmountIn(expectedAmountIn uint64) *SwapInstruction {
+	s.expectedAmountIn = expectedAmountIn
 	return s
 }
 
-// SetTokenMint sets the token mint
-func (s *SellInstruction) SetTokenMint(tokenMint solana.PublicKey) *SellInstruction {
-	s.tokenMint = tokenMint
+// SetWithoutOpenBook selects the V2 account layout that omits the OpenBook market accounts
+func (s *SwapInstruction) SetWithoutOpenBook(withoutOpenBook bool) *SwapInstruction {
+	s.withoutOpenBook = withoutOpenBook
 	return s
 }
 
-// SetAmount sets the amount to sell
-func (s *SellInstruction) SetAmount(amount uint64) *SellInstruction {
-	s.amount = amount
-	return s
-}
-
-// SetMinSolReceived sets the minimum SOL received
-func (s *SellInstruction) SetMinSolReceived(minSolReceived uint64) *SellInstruction {
-	s.minSolReceived = minSolReceived
-	return s
-}
-
-// Build creates the Solana instruction
-func (s *SellInstruction) Build() (solana.Instruction, error) {
-	// Build instruction data
-	data := make([]byte, 17) // 1 byte discriminator + 8 bytes amount + 8 bytes minSolReceived
-	data[0] = INSTRUCTION_SELL
-	binary.LittleEndian.PutUint64(data[1:9], s.amount)
-	binary.LittleEndian.PutUint64(data[9:17], s.minSolReceived)
-
-	// Build accounts slice
-	accounts := solana.AccountMetaSlice{
-		{PublicKey: s.userAuthority, IsWritable: false, IsSigner: true},
-		{PublicKey: s.userTokenAccount, IsWritable: true, IsSigner: false},
-		{PublicKey: s.userSolAccount, IsWritable: true, IsSigner: false},
-		{PublicKey: s.ammID, IsWritable: true, IsSigner: false},
-		{PublicKey: s.ammAuthority, IsWritable: false, IsSigner: false},
-		{PublicKey: s.tokenVault, IsWritable: true, IsSigner: false},
-		{PublicKey: s.solVault, IsWritable: true, IsSigner: false},
-		{PublicKey: s.tokenMint, IsWritable: false, IsSigner: false},
-		{PublicKey: TokenProgramID, IsWritable: false, IsSigner: false},
-		{PublicKey: SystemProgramID, IsWritable: false, IsSigner: false},
+// tag returns the instruction tag for the selected direction and account layout
+func (s *SwapInstruction) tag() byte {
+	switch {
+	case s.baseOut && s.withoutOpenBook:
+		return AMM_V4_INSTRUCTION_SWAP_BASE_OUT_V2
+	case s.baseOut:
+		return AMM_V4_INSTRUCTION_SWAP_BASE_OUT
+	case s.withoutOpenBook:
+		return AMM_V4_INSTRUCTION_SWAP_BASE_IN_V2
+	default:
+		return AMM_V4_INSTRUCTION_SWAP_BASE_IN
 	}
-
-	return solana.NewInstruction(
-		s.programID,
-		accounts,
-		data,
-	), nil
 }
 
-// CreateTokenInstruction represents a token creation instruction
-type CreateTokenInstruction struct {
-	programID       solana.PublicKey
-	payer           solana.PublicKey
-	mint            solana.PublicKey
-	mintAuthority   solana.PublicKey
-	freezeAuthority solana.PublicKey
-	decimals        uint8
-	name            string
-	symbol          string
-	uri             string
-	initialSupply   uint64
-}
-
-// NewCreateTokenInstruction creates a new token creation instruction builder
-func NewCreateTokenInstruction() *CreateTokenInstruction {
-	return &CreateTokenInstruction{
-		programID: RaydiumLaunchpadV1ProgramID,
-		decimals:  9, // Default to 9 decimals
+// withDerivedAccounts returns a copy of the swap with the AMM authority derived from the
+// program ID when it was not set
+func (s *SwapInstruction) withDerivedAccounts() *SwapInstruction {
+	d := *s
+	deriveIfZero(&d.ammAuthority, func() (solana.PublicKey, error) { return DeriveAmmV4Authority(d.programID) }, d.programID)
+	return &d
+}
+
+// Validate reports every missing account and invalid amount in the swap
+func (s *SwapInstruction) Validate() error {
+	s = s.withDerivedAccounts()
+	v := newInstructionValidator("swap")
+	v.account("program ID", s.programID)
+	v.account("user source token account", s.userSourceToken)
+	v.account("user destination token account", s.userDestToken)
+	v.account("user owner", s.userOwner)
+	v.account("AMM ID", s.ammID)
+	v.account("AMM authority", s.ammAuthority)
+	v.account("pool coin token account", s.poolCoinToken)
+	v.account("pool pc token account", s.poolPcToken)
+	if !s.withoutOpenBook {
+		v.account("AMM open orders", s.ammOpenOrders)
+		v.account("AMM target orders", s.ammTargetOrders)
+		v.account("serum program", s.serumProgram)
+		v.account("serum market", s.serumMarket)
+		v.account("serum bids", s.serumBids)
+		v.account("serum asks", s.serumAsks)
+		v.account("serum event queue", s.serumEventQueue)
+		v.account("serum coin vault", s.serumCoinVault)
+		v.account("serum pc vault", s.serumPcVault)
+		v.account("serum vault signer", s.serumVaultSigner)
 	}
-}
-
-// SetProgramID sets the program ID for the create token instruction
-func (c *CreateTokenInstruction) SetProgramID(programID solana.PublicKey) *CreateTokenInstruction {
-	c.programID = programID
-	return c
-}
-
-// SetPayer sets the payer account
-func (c *CreateTokenInstruction) SetPayer(payer solana.PublicKey) *CreateTokenInstruction {
-	c.payer = payer
-	return c
-}
-
-// SetMint sets the mint account
-func (c *CreateTokenInstruction) SetMint(mint solana.PublicKey) *CreateTokenInstruction {
-	c.mint = mint
-	return c
-}
-
-// SetMintAuthority sets the mint authority
-func (c *CreateTokenInstruction) SetMintAuthority(mintAuthority solana.PublicKey) *CreateTokenInstruction {
-	c.mintAuthority = mintAuthority
-	return c
-}
-
-// SetFreezeAuthority sets the freeze authority
-func (c *CreateTokenInstruction) SetFreezeAuthority(freezeAuthority solana.PublicKey) *CreateTokenInstruction {
-	c.freezeAuthority = freezeAuthority
-	return c
-}
-
-// SetDecimals sets the token decimals
-func (c *CreateTokenInstruction) SetDecimals(decimals uint8) *CreateTokenInstruction {
-	c.decimals = decimals
-	return c
-}
-
-// SetName sets the token name
-func (c *CreateTokenInstruction) SetName(name string) *CreateTokenInstruction {
-	c.name = name
-	return c
-}
-
-// SetSymbol sets the token symbol
-func (c *CreateTokenInstruction) SetSymbol(symbol string) *CreateTokenInstruction {
-	c.symbol = symbol
-	return c
-}
-
-// SetURI sets the token URI
-func (c *CreateTokenInstruction) SetURI(uri string) *CreateTokenInstruction {
-	c.uri = uri
-	return c
-}
-
-// SetInitialSupply sets the initial supply
-func (c *CreateTokenInstruction) SetInitialSupply(initialSupply uint64) *CreateTokenInstruction {
-	c.initialSupply = initialSupply
-	return c
+	v.check(!s.userSourceToken.Equals(s.userDestToken) || s.userSourceToken.IsZero(),
+		"source and destination token accounts are the same")
+	validateSwapAmounts(v, s.baseOut, s.amountIn, s.minimumAmountOut, s.expectedAmountOut, s.maxAmountIn, s.amountOut, s.expectedAmountIn)
+	return v.err()
 }
 
 // Build creates the Solana instruction
-func (c *CreateTokenInstruction) Build() (solana.Instruction, error) {
-	// Build instruction data
-	nameBytes := []byte(c.name)
-	symbolBytes := []byte(c.symbol)
-	uriBytes := []byte(c.uri)
-
-	// Calculate total data size
-	dataSize := 1 + // discriminator
-		1 + // decimals
-		4 + len(nameBytes) + // name length + name
-		4 + len(symbolBytes) + // symbol length + symbol
-		4 + len(uriBytes) + // uri length + uri
-		8 // initial supply
-
-	data := make([]byte, dataSize)
-	offset := 0
-
-	// Discriminator
-	data[offset] = INSTRUCTION_CREATE_POOL
-	offset++
-
-	// Decimals
-	data[offset] = c.decimals
-	offset++
-
-	// Name
-	binary.LittleEndian.PutUint32(data[offset:offset+4], uint32(len(nameBytes)))
-	offset += 4
-	copy(data[offset:offset+len(nameBytes)], nameBytes)
-	offset += len(nameBytes)
-
-	// Symbol
-	binary.LittleEndian.PutUint32(data[offset:offset+4], uint32(len(symbolBytes)))
-	offset += 4
-	copy(data[offset:offset+len(symbolBytes)], symbolBytes)
-	offset += len(symbolBytes)
-
-	// URI
-	binary.LittleEndian.PutUint32(data[offset:offset+4], uint32(len(uriBytes)))
-	offset += 4
-	copy(data[offset:offset+len(uriBytes)], uriBytes)
-	offset += len(uriBytes)
-
-	// Initial supply
-	binary.LittleEndian.PutUint64(data[offset:offset+8], c.initialSupply)
-
-	// Build accounts slice
-	accounts := solana.AccountMetaSlice{
-		{PublicKey: c.payer, IsWritable: true, IsSigner: true},
-		{PublicKey: c.mint, IsWritable: true, IsSigner: false},
-		{PublicKey: c.mintAuthority, IsWritable: false, IsSigner: false},
-		{PublicKey: c.freezeAuthority, IsWritable: false, IsSigner: false},
-		{PublicKey: TokenProgramID, IsWritable: false, IsSigner: false},
-		{PublicKey: SystemProgramID, IsWritable: false, IsSigner: false},
+func (s *SwapInstruction) Build() (solana.Instruction, error) {
+	s = s.withDerivedAccounts()
+	if err := s.Validate(); err != nil {
+		return nil, err
 	}
 
-	return solana.NewInstruction(
-		c.programID,
-		accounts,
-		data,
-	), nil
-}
-
-// MigrateInstruction represents a migration instruction
-type MigrateInstruction struct {
-	programID     solana.PublicKey
-	userAuthority solana.PublicKey
-	fromPool      solana.PublicKey
-	toPool        solana.PublicKey
-	tokenAccount  solana.PublicKey
-	amount        uint64
-}
-
-// NewMigrateInstruction creates a new migrate instruction builder
-func NewMigrateInstruction() *MigrateInstruction {
-	return &MigrateInstruction{
-		programID: RaydiumV4ProgramID,
+	// Build instruction data: tag, then amountIn/minimumAmountOut for swapBaseIn or
+	// maxAmountIn/amountOut for swapBaseOut
+	data := make([]byte, 17)
+	data[0] = s.tag()
+	if s.baseOut {
+		binary.LittleEndian.PutUint64(data[1:9], s.maxAmountIn)
+		binary.LittleEndian.PutUint64(data[9:17], s.amountOut)
+	} else {
+		binary.LittleEndian.PutUint64(data[1:9], s.amountIn)
+		binary.LittleEndian.PutUint64(data[9:17], s.minimumAmountOut)
 	}
-}
-
-// SetProgramID sets the program ID for the migrate instruction
-func (m *MigrateInstruction) SetProgramID(programID solana.PublicKey) *MigrateInstruction {
-	m.programID = programID
-	return m
-}
-
-// SetUserAuthority sets the user authority
-func (m *MigrateInstruction) SetUserAuthority(userAuthority solana.PublicKey) *MigrateInstruction {
-	m.userAuthority = userAuthority
-	return m
-}
 
-// SetFromPool sets the from pool
-func (m *MigrateInstruction) SetFromPool(fromPool solana.PublicKey) *MigrateInstruction {
-	m.fromPool = fromPool
-	return m
-}
-
-// SetToPool sets the to pool
-func (m *MigrateInstruction) SetToPool(toPool solana.PublicKey) *MigrateInstruction {
-	m.toPool = toPool
-	return m
-}
-
-// SetTokenAccount sets the token account
-func (m *MigrateInstruction) SetTokenAccount(tokenAccount solana.PublicKey) *MigrateInstruction {
-	m.tokenAccount = tokenAccount
-	return m
-}
-
-// SetAmount sets the amount to migrate
-func (m *MigrateInstruction) SetAmount(amount uint64) *MigrateInstruction {
-	m.amount = amount
-	return m
-}
-
-// Build creates the Solana instruction
-func (m *MigrateInstruction) Build() (solana.Instruction, error) {
-	// Build instruction data
-	data := make([]byte, 9) // 1 byte discriminator + 8 bytes amount
-	data[0] = INSTRUCTION_MIGRATE
-	binary.LittleEndian.PutUint64(data[1:9], m.amount)
-
-	// Build accounts slice
+	// Build accounts slice in program order; the V2 layout skips the order book accounts
 	accounts := solana.AccountMetaSlice{
-		{PublicKey: m.userAuthority, IsWritable: false, IsSigner: true},
-		{PublicKey: m.fromPool, IsWritable: true, IsSigner: false},
-		{PublicKey: m.toPool, IsWritable: true, IsSigner: false},
-		{PublicKey: m.tokenAccount, IsWritable: true, IsSigner: false},
 		{PublicKey: TokenProgramID, IsWritable: false, IsSigner: false},
+		{PublicKey: s.ammID, IsWritable: true, IsSigner: false},
+		{PublicKey: s.ammAuthority, IsWritable: false, IsSigner: false},
+	}
+	if !s.withoutOpenBook {
+		accounts = append(accounts,
+			&solana.AccountMeta{PublicKey: s.ammOpenOrders, IsWritable: true, IsSigner: false},
+			&solana.AccountMeta{PublicKey: s.ammTargetOrders, IsWritable: true, IsSigner: false},
+		)
 	}
+	accounts = append(accounts,
+		&solana.AccountMeta{PublicKey: s.poolCoinToken, IsWritable: true, IsSigner: false},
+		&solana.AccountMeta{PublicKey: s.poolPcToken, IsWritable: true, IsSigner: false},
+	)
+	if !s.withoutOpenBook {
+		accounts = append(accounts,
+			&solana.AccountMeta{PublicKey: s.serumProgram, IsWritable: false, IsSigner: false},
+			&solana.AccountMeta{PublicKey: s.serumMarket, IsWritable: true, IsSigner: false},
+			&solana.AccountMeta{PublicKey: s.serumBids, IsWritable: true, IsSigner: false},
+			&solana.AccountMeta{PublicKey: s.serumAsks, IsWritable: true, IsSigner: false},
+			&solana.AccountMeta{PublicKey: s.serumEventQueue, IsWritable: true, IsSigner: false},
+			&solana.AccountMeta{PublicKey: s.serumCoinVault, IsWritable: true, IsSigner: false},
+			&solana.AccountMeta{PublicKey: s.serumPcVault, IsWritable: true, IsSigner: false},
+			&solana.AccountMeta{PublicKey: s.serumVaultSigner, IsWritable: false, IsSigner: false},
+		)
+	}
+	accounts = append(accounts,
+		&solana.AccountMeta{PublicKey: s.userSourceToken, IsWritable: true, IsSigner: false},
+		&solana.AccountMeta{PublicKey: s.userDestToken, IsWritable: true, IsSigner: false},
+		&solana.AccountMeta{PublicKey: s.userOwner, IsWritable: false, IsSigner: true},
+	)
 
 	return solana.NewInstruction(
-		m.programID,
+		s.programID,
 		accounts,
 		data,
 	), nil
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+var clmmSwapV2Discriminator = anchorInstructionDiscriminator("swap_v2")
+
+// CLMM swap_v2 fixed account count, before the bitmap extension and tick arrays
+const CLMM_SWAP_V2_ACCOUNTS_LENGTH = 13
+
+// clmmMaxTickArrays limits the tick arrays one swap may cross, as in the Raydium SDK
+const clmmMaxTickArrays = 10
+
+var clmmMaxSqrtPriceLimit = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+// ClmmSwapInstruction represents a Raydium CLMM swap_v2 instruction. The caller supplies
+// the tick arrays the swap will cross, in traversal order, and the pool's tick array
+// bitmap extension when the price may leave the default bitmap range. Unset pool, vault,
+// observation and user token accounts are derived like CpSwapInstruction's.
+type ClmmSwapInstruction struct {
+	programID                solana.PublicKey
+	payer                    solana.PublicKey
+	ammConfig                solana.PublicKey
+	poolState                solana.PublicKey
+	inputTokenAccount        solana.PublicKey
+	outputTokenAccount       solana.PublicKey
+	inputVault               solana.PublicKey
+	outputVault              solana.PublicKey
+	observationState         solana.PublicKey
+	inputVaultMint           solana.PublicKey
+	outputVaultMint          solana.PublicKey
+	tickArrayBitmapExtension solana.PublicKey
+	tickArrays               []solana.PublicKey
+	// token programs of the input and output mints, only used to derive the user's token accounts
+	inputTokenProgram  solana.PublicKey
+	outputTokenProgram solana.PublicKey
+	baseOut            bool
+	// sqrtPriceLimitX64 is a Q64.64 price bound; nil or zero lets the program pick the limit
+	sqrtPriceLimitX64 *big.Int
+
+	// exact-in amounts
+	amountIn          uint64
+	minimumAmountOut  uint64
+	expectedAmountOut uint64
+
+	// exact-out amounts
+	maxAmountIn      uint64
+	amountOut        uint64
+	expectedAmountIn uint64
+}
+
+// NewClmmSwapInstruction creates a new CLMM swap_v2 instruction builder
+func NewClmmSwapInstruction() *ClmmSwapInstruction {
+	return &ClmmSwapInstruction{
+		programID:          RaydiumClmmProgramID,
+		inputTokenProgram:  TokenProgramID,
+		outputTokenProgram: TokenProgramID,
+	}
+}
+
+// SetProgramID sets the program ID for the CLMM instruction
+func (c *ClmmSwapInstruction) SetProgramID(programID solana.PublicKey) *ClmmSwapInstruction {
+	c.programID = programID
+	return c
+}
+
+// SetPayer sets the signing user
+func (c *ClmmSwapInstruction) SetPayer(payer solana.PublicKey) *ClmmSwapInstruction {
+	c.payer = payer
+	return c
+}
+
+// SetAmmConfig sets the pool's AmmConfig account
+func (c *ClmmSwapInstruction) SetAmmConfig(ammConfig solana.PublicKey) *ClmmSwapInstruction {
+	c.ammConfig = ammConfig
+	return c
+}
+
+// SetPoolState sets the pool state account
+func (c *ClmmSwapInstruction) SetPoolState(poolState solana.PublicKey) *ClmmSwapInstruction {
+	c.poolState = poolState
+	return c
+}
+
+// SetInputTokenAccount sets the user's input token account
+func (c *ClmmSwapInstruction) SetInputTokenAccount(inputTokenAccount solana.PublicKey) *ClmmSwapInstruction {
+	c.inputTokenAccount = inputTokenAccount
+	return c
+}
+
+// SetOutputTokenAccount sets the user's output token account
+func (c *ClmmSwapInstruction) SetOutputTokenAccount(outputTokenAccount solana.PublicKey) *ClmmSwapInstruction {
+	c.outputTokenAccount = outputTokenAccount
+	return c
+}
+
+// SetInputVault sets the pool vault receiving the input token
+func (c *ClmmSwapInstruction) SetInputVault(inputVault solana.PublicKey) *ClmmSwapInstruction {
+	c.inputVault = inputVault
+	return c
+}
+
+// SetOutputVault sets the pool vault paying out the output token
+func (c *ClmmSwapInstruction) SetOutputVault(outputVault solana.PublicKey) *ClmmSwapInstruction {
+	c.outputVault = outputVault
+	return c
+}
+
+// SetObservationState sets the pool's observation account
+func (c *ClmmSwapInstruction) SetObservationState(observationState solana.PublicKey) *ClmmSwapInstruction {
+	c.observationState = observationState
+	return c
+}
+
+// SetInputVaultMint sets the input token mint
+func (c *ClmmSwapInstruction) SetInputVaultMint(inputVaultMint solana.PublicKey) *ClmmSwapInstruction {
+	c.inputVaultMint = inputVaultMint
+	return c
+}
+
+// SetOutputVaultMint sets the output token mint
+func (c *ClmmSwapInstruction) SetOutputVaultMint(outputVaultMint solana.PublicKey) *ClmmSwapInstruction {
+	c.outputVaultMint = outputVaultMint
+	return c
+}
+
+// SetInputTokenProgram sets the token program owning the input mint, used when deriving
+// the input token account
+func (c *ClmmSwapInstruction) SetInputTokenProgram(inputTokenProgram solana.PublicKey) *ClmmSwapInstruction {
+	c.inputTokenProgram = inputTokenProgram
+	return c
+}
+
+// SetOutputTokenProgram sets the token program owning the output mint, used when deriving
+// the output token account
+func (c *ClmmSwapInstruction) SetOutputTokenProgram(outputTokenProgram solana.PublicKey) *ClmmSwapInstruction {
+	c.outputTokenProgram = outputTokenProgram
+	return c
+}
+
+// SetTickArrayBitmapExtension sets the pool's tick array bitmap extension account
+func (c *ClmmSwapInstruction) SetTickArrayBitmapExtension(tickArrayBitmapExtension solana.PublicKey) *ClmmSwapInstruction {
+	c.tickArrayBitmapExtension = tickArrayBitmapExtension
+	return c
+}
+
+// SetTickArrays sets the tick arrays the swap crosses, starting with the current one
+func (c *ClmmSwapInstruction) SetTickArrays(tickArrays ...solana.PublicKey) *ClmmSwapInstruction {
+	c.tickArrays = append([]solana.PublicKey(nil), tickArrays...)
+	return c
+}
+
+// SetSqrtPriceLimitX64 sets the Q64.64 square-root price the swap may not cross
+func (c *ClmmSwapInstruction) SetSqrtPriceLimitX64(sqrtPriceLimitX64 *big.Int) *ClmmSwapInstruction {
+	c.sqrtPriceLimitX64 = sqrtPriceLimitX64
+	return c
+}
+
+// SetBaseOut selects an exact-output swap instead of exact-input
+func (c *ClmmSwapInstruction) SetBaseOut(baseOut bool) *ClmmSwapInstruction {
+	c.baseOut = baseOut
+	return c
+}
+
+// SetAmountIn sets the exact amount in
+func (c *ClmmSwapInstruction) SetAmountIn(amountIn uint64) *ClmmSwapInstruction {
+	c.amountIn = amountIn
+	return c
+}
+
+// SetMinimumAmountOut sets the minimum amount out for exact-in swaps
+func (c *ClmmSwapInstruction) SetMinimumAmountOut(minimumAmountOut uint64) *ClmmSwapInstruction {
+	c.minimumAmountOut = minimumAmountOut
+	return c
+}
+
+// SetExpectedAmountOut sets the quoted amount out used to sanity-check the minimum
+func (c *ClmmSwapInstruction) SetExpectedAmountOut(expectedAmountOut uint64) *ClmmSwapInstruction {
+	c.expectedAmountOut = expectedAmountOut
+	return c
+}
+
+// SetMaxAmountIn sets the maximum amount in for exact-out swaps
+func (c *ClmmSwapInstruction) SetMaxAmountIn(maxAmountIn uint64) *ClmmSwapInstruction {
+	c.maxAmountIn = maxAmountIn
+	return c
+}
+
+// SetAmountOut sets the exact amount out
+func (c *ClmmSwapInstruction) SetAmountOut(amountOut uint64) *ClmmSwapInstruction {
+	c.amountOut = amountOut
+	return c
+}
+
+// SetExpectedAmountIn sets the quoted amount in used to sanity-check the maximum
+func (c *ClmmSwapInstruction) SetExpectedAmountIn(expectedAmountIn uint64) *ClmmSwapInstruction {
+	c.expectedAmountIn = expectedAmountIn
+	return c
+}
+
+// withDerivedAccounts returns a copy of the swap with every unset derivable account filled
+func (c *ClmmSwapInstruction) withDerivedAccounts() *ClmmSwapInstruction {
+	d := *c
+	deriveIfZero(&d.poolState, func() (solana.PublicKey, error) {
+		return DeriveClmmPoolState(d.programID, d.ammConfig, d.inputVaultMint, d.outputVaultMint)
+	}, d.programID, d.ammConfig, d.inputVaultMint, d.outputVaultMint)
+	deriveIfZero(&d.inputVault, func() (solana.PublicKey, error) {
+		return DeriveClmmVault(d.programID, d.poolState, d.inputVaultMint)
+	}, d.programID, d.poolState, d.inputVaultMint)
+	deriveIfZero(&d.outputVault, func() (solana.PublicKey, error) {
+		return DeriveClmmVault(d.programID, d.poolState, d.outputVaultMint)
+	}, d.programID, d.poolState, d.outputVaultMint)
+	deriveIfZero(&d.observationState, func() (solana.PublicKey, error) {
+		return DeriveClmmObservationState(d.programID, d.poolState)
+	}, d.programID, d.poolState)
+	deriveIfZero(&d.inputTokenAccount, func() (solana.PublicKey, error) {
+		return DeriveAssociatedTokenAddress(d.payer, d.inputVaultMint, d.inputTokenProgram)
+	}, d.payer, d.inputVaultMint, d.inputTokenProgram)
+	deriveIfZero(&d.outputTokenAccount, func() (solana.PublicKey, error) {
+		return DeriveAssociatedTokenAddress(d.payer, d.outputVaultMint, d.outputTokenProgram)
+	}, d.payer, d.outputVaultMint, d.outputTokenProgram)
+	return &d
+}
+
+// Validate reports every missing account and invalid amount in the swap
+func (c *ClmmSwapInstruction) Validate() error {
+	c = c.withDerivedAccounts()
+	v := newInstructionValidator("clmm swap")
+	v.account("program ID", c.programID)
+	v.account("payer", c.payer)
+	v.account("amm config", c.ammConfig)
+	v.account("pool state", c.poolState)
+	v.account("input token account", c.inputTokenAccount)
+	v.account("output token account", c.outputTokenAccount)
+	v.account("input vault", c.inputVault)
+	v.account("output vault", c.outputVault)
+	v.account("observation state", c.observationState)
+	v.account("input vault mint", c.inputVaultMint)
+	v.account("output vault mint", c.outputVaultMint)
+	v.check(len(c.tickArrays) > 0, "missing tick arrays")
+	v.check(len(c.tickArrays) <= clmmMaxTickArrays, "%d tick arrays exceeds limit of %d", len(c.tickArrays), clmmMaxTickArrays)
+	for i, tickArray := range c.tickArrays {
+		v.account(fmt.Sprintf("tick array %d", i), tickArray)
+	}
+	v.check(c.sqrtPriceLimitX64 == nil || (c.sqrtPriceLimitX64.Sign() >= 0 && c.sqrtPriceLimitX64.Cmp(clmmMaxSqrtPriceLimit) <= 0),
+		"sqrt price limit does not fit in u128")
+	v.check(!c.inputVaultMint.Equals(c.outputVaultMint) || c.inputVaultMint.IsZero(), "input and output mints are the same")
+	validateSwapAmounts(v, c.baseOut, c.amountIn, c.minimumAmountOut, c.expectedAmountOut, c.maxAmountIn, c.amountOut, c.expectedAmountIn)
+	return v.err()
+}
+
+// Build creates the Solana instruction
+func (c *ClmmSwapInstruction) Build() (solana.Instruction, error) {
+	c = c.withDerivedAccounts()
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Build instruction data: discriminator, amount, other_amount_threshold,
+	// sqrt_price_limit_x64 (u128) and is_base_input
+	data := make([]byte, 8+8+8+16+1)
+	copy(data[:8], clmmSwapV2Discriminator[:])
+	if c.baseOut {
+		binary.LittleEndian.PutUint64(data[8:16], c.amountOut)
+		binary.LittleEndian.PutUint64(data[16:24], c.maxAmountIn)
+	} else {
+		binary.LittleEndian.PutUint64(data[8:16], c.amountIn)
+		binary.LittleEndian.PutUint64(data[16:24], c.minimumAmountOut)
+		data[40] = 1
+	}
+	if c.sqrtPriceLimitX64 != nil {
+		limit := c.sqrtPriceLimitX64.FillBytes(make([]byte, 16))
+		for i := range limit {
+			data[24+i] = limit[15-i] // big-endian to little-endian
+		}
+	}
+
+	// Build accounts slice; remaining accounts are the bitmap extension then the tick arrays
+	accounts := solana.AccountMetaSlice{
+		{PublicKey: c.payer, IsWritable: false, IsSigner: true},
+		{PublicKey: c.ammConfig, IsWritable: false, IsSigner: false},
+		{PublicKey: c.poolState, IsWritable: true, IsSigner: false},
+		{PublicKey: c.inputTokenAccount, IsWritable: true, IsSigner: false},
+		{PublicKey: c.outputTokenAccount, IsWritable: true, IsSigner: false},
+		{PublicKey: c.inputVault, IsWritable: true, IsSigner: false},
+		{PublicKey: c.outputVault, IsWritable: true, IsSigner: false},
+		{PublicKey: c.observationState, IsWritable: true, IsSigner: false},
+		{PublicKey: TokenProgramID, IsWritable: false, IsSigner: false},
+		{PublicKey: Token2022ProgramID, IsWritable: false, IsSigner: false},
+		{PublicKey: MemoProgramID, IsWritable: false, IsSigner: false},
+		{PublicKey: c.inputVaultMint, IsWritable: false, IsSigner: false},
+		{PublicKey: c.outputVaultMint, IsWritable: false, IsSigner: false},
+	}
+	if !c.tickArrayBitmapExtension.IsZero() {
+		accounts = append(accounts, &solana.AccountMeta{PublicKey: c.tickArrayBitmapExtension, IsWritable: true, IsSigner: false})
+	}
+	for _, tickArray := range c.tickArrays {
+		accounts = append(accounts, &solana.AccountMeta{PublicKey: tickArray, IsWritable: true, IsSigner: false})
+	}
+
+	return solana.NewInstruction(
+		c.programID,
+		accounts,
+		data,
+	), nil
+}
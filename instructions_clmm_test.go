@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/binary"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestClmmSwapInstructionBuilder(t *testing.T) {
+	keys := uniqueAccounts(14)
+	newSwap := func() *ClmmSwapInstruction {
+		return NewClmmSwapInstruction().
+			SetPayer(keys[0]).SetAmmConfig(keys[1]).SetPoolState(keys[2]).
+			SetInputTokenAccount(keys[3]).SetOutputTokenAccount(keys[4]).
+			SetInputVault(keys[5]).SetOutputVault(keys[6]).SetObservationState(keys[7]).
+			SetInputVaultMint(keys[8]).SetOutputVaultMint(keys[9]).
+			SetTickArrays(keys[10], keys[11])
+	}
+
+	limit := new(big.Int).Lsh(big.NewInt(1), 64) // price 1.0
+	instruction, err := newSwap().
+		SetTickArrayBitmapExtension(keys[12]).
+		SetSqrtPriceLimitX64(limit).
+		SetAmountIn(5_000).SetMinimumAmountOut(4_900).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build swap_v2: %v", err)
+	}
+	if !instruction.ProgramID().Equals(RaydiumClmmProgramID) {
+		t.Errorf("Expected program ID %s, got %s", RaydiumClmmProgramID, instruction.ProgramID())
+	}
+
+	data, _ := instruction.Data()
+	if len(data) != 41 || [8]byte(data[:8]) != clmmSwapV2Discriminator {
+		t.Fatalf("Unexpected swap_v2 data %x", data)
+	}
+	if binary.LittleEndian.Uint64(data[8:16]) != 5_000 || binary.LittleEndian.Uint64(data[16:24]) != 4_900 {
+		t.Errorf("Unexpected amount/threshold in data %x", data)
+	}
+	if binary.LittleEndian.Uint64(data[24:32]) != 0 || binary.LittleEndian.Uint64(data[32:40]) != 1 || data[40] != 1 {
+		t.Errorf("Unexpected sqrt price limit or is_base_input in data %x", data)
+	}
+
+	accounts := instruction.Accounts()
+	if len(accounts) != CLMM_SWAP_V2_ACCOUNTS_LENGTH+3 {
+		t.Fatalf("Expected %d accounts, got %d", CLMM_SWAP_V2_ACCOUNTS_LENGTH+3, len(accounts))
+	}
+	if !accounts[10].PublicKey.Equals(MemoProgramID) {
+		t.Errorf("Expected memo program at index 10, got %s", accounts[10].PublicKey)
+	}
+	remaining := accounts[CLMM_SWAP_V2_ACCOUNTS_LENGTH:]
+	if !remaining[0].PublicKey.Equals(keys[12]) || !remaining[1].PublicKey.Equals(keys[10]) || !remaining[2].IsWritable {
+		t.Errorf("Expected bitmap extension followed by writable tick arrays")
+	}
+
+	// Exact-out without a bitmap extension
+	instruction, err = newSwap().SetBaseOut(true).SetMaxAmountIn(5_100).SetAmountOut(5_000).Build()
+	if err != nil {
+		t.Fatalf("Failed to build exact-out swap_v2: %v", err)
+	}
+	data, _ = instruction.Data()
+	if binary.LittleEndian.Uint64(data[8:16]) != 5_000 || binary.LittleEndian.Uint64(data[16:24]) != 5_100 || data[40] != 0 {
+		t.Errorf("Unexpected exact-out data %x", data)
+	}
+	if len(instruction.Accounts()) != CLMM_SWAP_V2_ACCOUNTS_LENGTH+2 {
+		t.Errorf("Expected only tick arrays as remaining accounts")
+	}
+
+	err = newSwap().SetTickArrays().SetSqrtPriceLimitX64(new(big.Int).Lsh(big.NewInt(1), 128)).SetAmountIn(1).Validate()
+	if err == nil || !strings.Contains(err.Error(), "missing tick arrays") || !strings.Contains(err.Error(), "does not fit in u128") {
+		t.Errorf("Unexpected validation error: %v", err)
+	}
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"encoding/binary"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// CpSwapAuthorityID is the CP-Swap vault and LP mint authority, the program address of
+// seed "vault_and_lp_mint_auth_seed"
+var CpSwapAuthorityID = solana.MustPublicKeyFromBase58("GpMZbSM2GgvTKHJirzeGfMFoaZ8UR2X7F4v8vHTvxFbL")
+
+var (
+	cpSwapSwapBaseInputDiscriminator  = anchorInstructionDiscriminator("swap_base_input")
+	cpSwapSwapBaseOutputDiscriminator = anchorInstructionDiscriminator("swap_base_output")
+)
+
+// CP-Swap swap account count
+const CPSWAP_SWAP_ACCOUNTS_LENGTH = 13
+
+// CpSwapInstruction represents a Raydium CP-Swap swap instruction. It builds
+// swap_base_input by default; SetBaseOut switches to swap_base_output. Accounts left
+// unset are derived: the authority from the program, the pool from the AmmConfig and
+// mints, the vaults and observation state from the pool, and the user's token accounts
+// as the payer's associated token accounts.
+type CpSwapInstruction struct {
+	programID          solana.PublicKey
+	payer              solana.PublicKey
+	authority          solana.PublicKey
+	ammConfig          solana.PublicKey
+	poolState          solana.PublicKey
+	inputTokenAccount  solana.PublicKey
+	outputTokenAccount solana.PublicKey
+	inputVault         solana.PublicKey
+	outputVault        solana.PublicKey
+	inputTokenProgram  solana.PublicKey
+	outputTokenProgram solana.PublicKey
+	inputMint          solana.PublicKey
+	outputMint         solana.PublicKey
+	observationState   solana.PublicKey
+	baseOut            bool
+
+	// swap_base_input amounts
+	amountIn          uint64
+	minimumAmountOut  uint64
+	expectedAmountOut uint64
+
+	// swap_base_output amounts
+	maxAmountIn      uint64
+	amountOut        uint64
+	expectedAmountIn uint64
+}
+
+// NewCpSwapInstruction creates a new CP-Swap swap instruction builder
+func NewCpSwapInstruction() *CpSwapInstruction {
+	return &CpSwapInstruction{
+		programID:          RaydiumCpSwapProgramID,
+		inputTokenProgram:  TokenProgramID,
+		outputTokenProgram: TokenProgramID,
+	}
+}
+
+// SetProgramID sets the program ID for the CP-Swap instruction
+func (c *CpSwapInstruction) SetProgramID(programID solana.PublicKey) *CpSwapInstruction {
+	c.programID = programID
+	return c
+}
+
+// SetPayer sets the signing user
+func (c *CpSwapInstruction) SetPayer(payer solana.PublicKey) *CpSwapInstruction {
+	c.payer = payer
+	return c
+}
+
+// SetAuthority sets the pool vault authority
+func (c *CpSwapInstruction) SetAuthority(authority solana.PublicKey) *CpSwapInstruction {
+	c.authority = authority
+	return c
+}
+
+// SetAmmConfig sets the pool's AmmConfig account
+func (c *CpSwapInstruction) SetAmmConfig(ammConfig solana.PublicKey) *CpSwapInstruction {
+	c.ammConfig = ammConfig
+	return c
+}
+
+// SetPoolState sets the pool state account
+func (c *CpSwapInstruction) SetPoolState(poolState solana.PublicKey) *CpSwapInstruction {
+	c.poolState = poolState
+	return c
+}
+
+// SetInputTokenAccount sets the user's input token account
+func (c *CpSwapInstruction) SetInputTokenAccount(inputTokenAccount solana.PublicKey) *CpSwapInstruction {
+	c.inputTokenAccount = inputTokenAccount
+	return c
+}
+
+// SetOutputTokenAccount sets the user's output token account
+func (c *CpSwapInstruction) SetOutputTokenAccount(outputTokenAccount solana.PublicKey) *CpSwapInstruction {
+	c.outputTokenAccount = outputTokenAccount
+	return c
+}
+
+// SetInputVault sets the pool vault receiving the input token
+func (c *CpSwapInstruction) SetInputVault(inputVault solana.PublicKey) *CpSwapInstruction {
+	c.inputVault = inputVault
+	return c
+}
+
+// SetOutputVault sets the pool vault paying out the output token
+func (c *CpSwapInstruction) SetOutputVault(outputVault solana.PublicKey) *CpSwapInstruction {
+	c.outputVault = outputVault
+	return c
+}
+
+// SetInputTokenProgram sets the token program owning the input mint
+func (c *CpSwapInstruction) SetInputTokenProgram(inputTokenProgram solana.PublicKey) *CpSwapInstruction {
+	c.inputTokenProgram = inputTokenProgram
+	return c
+}
+
+// SetOutputTokenProgram sets the token program owning the output mint
+func (c *CpSwapInstruction) SetOutputTokenProgram(outputTokenProgram solana.PublicKey) *CpSwapInstruction {
+	c.outputTokenProgram = outputTokenProgram
+	return c
+}
+
+// SetInputMint sets the input token mint
+func (c *CpSwapInstruction) SetInputMint(inputMint solana.PublicKey) *CpSwapInstruction {
+	c.inputMint = inputMint
+	return c
+}
+
+// SetOutputMint sets the output token mint
+func (c *CpSwapInstruction) SetOutputMint(outputMint solana.PublicKey) *CpSwapInstruction {
+	c.outputMint = outputMint
+	return c
+}
+
+// SetObservationState sets the pool's observation account
+func (c *CpSwapInstruction) SetObservationState(observationState solana.PublicKey) *CpSwapInstruction {
+	c.observationState = observationState
+	return c
+}
+
+// SetBaseOut selects swap_base_output (exact output) instead of swap_base_input
+func (c *CpSwapInstruction) SetBaseOut(baseOut bool) *CpSwapInstruction {
+	c.baseOut = baseOut
+	return c
+}
+
+// SetAmountIn sets the exact amount in for swap_base_input
+func (c *CpSwapInstruction) SetAmountIn(amountIn uint64) *CpSwapInstruction {
+	c.amountIn = amountIn
+	return c
+}
+
+// SetMinimumAmountOut sets the minimum amount out for swap_base_input
+func (c *CpSwapInstruction) SetMinimumAmountOut(minimumAmountOut uint64) *CpSwapInstruction {
+	c.minimumAmountOut = minimumAmountOut
+	return c
+}
+
+// SetExpectedAmountOut sets the quoted amount out used to sanity-check the minimum
+func (c *CpSwapInstruction) SetExpectedAmountOut(expectedAmountOut uint64) *CpSwapInstruction {
+	c.expectedAmountOut = expectedAmountOut
+	return c
+}
+
+// SetMaxAmountIn sets the maximum amount in for swap_base_output
+func (c *CpSwapInstruction) SetMaxAmountIn(maxAmountIn uint64) *CpSwapInstruction {
+	c.maxAmountIn = maxAmountIn
+	return c
+}
+
+// SetAmountOut sets the exact amount out for swap_base_output
+func (c *CpSwapInstruction) SetAmountOut(amountOut uint64) *CpSwapInstruction {
+	c.amountOut = amountOut
+	return c
+}
+
+// SetExpectedAmountIn sets the quoted amount in used to sanity-check the maximum
+func (c *CpSwapInstruction) SetExpectedAmountIn(expectedAmountIn uint64) *CpSwapInstruction {
+	c.expectedAmountIn = expectedAmountIn
+	return c
+}
+
+// withDerivedAccounts returns a copy of the swap with every unset derivable account filled
+func (c *CpSwapInstruction) withDerivedAccounts() *CpSwapInstruction {
+	d := *c
+	deriveIfZero(&d.authority, func() (solana.PublicKey, error) { return DeriveCpSwapAuthority(d.programID) }, d.programID)
+	deriveIfZero(&d.poolState, func() (solana.PublicKey, error) {
+		return DeriveCpSwapPoolState(d.programID, d.ammConfig, d.inputMint, d.outputMint)
+	}, d.programID, d.ammConfig, d.inputMint, d.outputMint)
+	deriveIfZero(&d.inputVault, func() (solana.PublicKey, error) {
+		return DeriveCpSwapVault(d.programID, d.poolState, d.inputMint)
+	}, d.programID, d.poolState, d.inputMint)
+	deriveIfZero(&d.outputVault, func() (solana.PublicKey, error) {
+		return DeriveCpSwapVault(d.programID, d.poolState, d.outputMint)
+	}, d.programID, d.poolState, d.outputMint)
+	deriveIfZero(&d.observationState, func() (solana.PublicKey, error) {
+		return DeriveCpSwapObservationState(d.programID, d.poolState)
+	}, d.programID, d.poolState)
+	deriveIfZero(&d.inputTokenAccount, func() (solana.PublicKey, error) {
+		return DeriveAssociatedTokenAddress(d.payer, d.inputMint, d.inputTokenProgram)
+	}, d.payer, d.inputMint, d.inputTokenProgram)
+	deriveIfZero(&d.outputTokenAccount, func() (solana.PublicKey, error) {
+		return DeriveAssociatedTokenAddress(d.payer, d.outputMint, d.outputTokenProgram)
+	}, d.payer, d.outputMint, d.outputTokenProgram)
+	return &d
+}
+
+// Validate reports every missing account and invalid amount in the swap
+func (c *CpSwapInstruction) Validate() error {
+	c = c.withDerivedAccounts()
+	v := newInstructionValidator("cp-swap swap")
+	v.account("program ID", c.programID)
+	v.account("payer", c.payer)
+	v.account("authority", c.authority)
+	v.account("amm config", c.ammConfig)
+	v.account("pool state", c.poolState)
+	v.account("input token account", c.inputTokenAccount)
+	v.account("output token account", c.outputTokenAccount)
+	v.account("input vault", c.inputVault)
+	v.account("output vault", c.outputVault)
+	v.account("input token program", c.inputTokenProgram)
+	v.account("output token program", c.outputTokenProgram)
+	v.account("input mint", c.inputMint)
+	v.account("output mint", c.outputMint)
+	v.account("observation state", c.observationState)
+	v.check(!c.inputMint.Equals(c.outputMint) || c.inputMint.IsZero(), "input and output mints are the same")
+	validateSwapAmounts(v, c.baseOut, c.amountIn, c.minimumAmountOut, c.expectedAmountOut, c.maxAmountIn, c.amountOut, c.expectedAmountIn)
+	return v.err()
+}
+
+// Build creates the Solana instruction
+func (c *CpSwapInstruction) Build() (solana.Instruction, error) {
+	c = c.withDerivedAccounts()
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Build instruction data: Anchor discriminator followed by two u64 arguments
+	data := make([]byte, 24)
+	if c.baseOut {
+		copy(data[:8], cpSwapSwapBaseOutputDiscriminator[:])
+		binary.LittleEndian.PutUint64(data[8:16], c.maxAmountIn)
+		binary.LittleEndian.PutUint64(data[16:24], c.amountOut)
+	} else {
+		copy(data[:8], cpSwapSwapBaseInputDiscriminator[:])
+		binary.LittleEndian.PutUint64(data[8:16], c.amountIn)
+		binary.LittleEndian.PutUint64(data[16:24], c.minimumAmountOut)
+	}
+
+	// Build accounts slice
+	accounts := solana.AccountMetaSlice{
+		{PublicKey: c.payer, IsWritable: false, IsSigner: true},
+		{PublicKey: c.authority, IsWritable: false, IsSigner: false},
+		{PublicKey: c.ammConfig, IsWritable: false, IsSigner: false},
+		{PublicKey: c.poolState, IsWritable: true, IsSigner: false},
+		{PublicKey: c.inputTokenAccount, IsWritable: true, IsSigner: false},
+		{PublicKey: c.outputTokenAccount, IsWritable: true, IsSigner: false},
+		{PublicKey: c.inputVault, IsWritable: true, IsSigner: false},
+		{PublicKey: c.outputVault, IsWritable: true, IsSigner: false},
+		{PublicKey: c.inputTokenProgram, IsWritable: false, IsSigner: false},
+		{PublicKey: c.outputTokenProgram, IsWritable: false, IsSigner: false},
+		{PublicKey: c.inputMint, IsWritable: false, IsSigner: false},
+		{PublicKey: c.outputMint, IsWritable: false, IsSigner: false},
+		{PublicKey: c.observationState, IsWritable: true, IsSigner: false},
+	}
+
+	return solana.NewInstruction(
+		c.programID,
+		accounts,
+		data,
+	), nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestCpSwapInstructionBuilder(t *testing.T) {
+	keys := uniqueAccounts(10)
+	newSwap := func() *CpSwapInstruction {
+		return NewCpSwapInstruction().
+			SetPayer(keys[0]).SetAmmConfig(keys[1]).SetPoolState(keys[2]).
+			SetInputTokenAccount(keys[3]).SetOutputTokenAccount(keys[4]).
+			SetInputVault(keys[5]).SetOutputVault(keys[6]).
+			SetInputMint(keys[7]).SetOutputMint(keys[8]).SetOutputTokenProgram(Token2022ProgramID).
+			SetObservationState(keys[9])
+	}
+
+	instruction, err := newSwap().SetAmountIn(1_000).SetMinimumAmountOut(900).Build()
+	if err != nil {
+		t.Fatalf("Failed to build swap_base_input: %v", err)
+	}
+	if !instruction.ProgramID().Equals(RaydiumCpSwapProgramID) {
+		t.Errorf("Expected program ID %s, got %s", RaydiumCpSwapProgramID, instruction.ProgramID())
+	}
+	data, _ := instruction.Data()
+	if len(data) != 24 || [8]byte(data[:8]) != cpSwapSwapBaseInputDiscriminator {
+		t.Fatalf("Unexpected swap_base_input data %x", data)
+	}
+	if binary.LittleEndian.Uint64(data[8:16]) != 1_000 || binary.LittleEndian.Uint64(data[16:24]) != 900 {
+		t.Errorf("Unexpected amounts in data %x", data)
+	}
+
+	accounts := instruction.Accounts()
+	if len(accounts) != CPSWAP_SWAP_ACCOUNTS_LENGTH {
+		t.Fatalf("Expected %d accounts, got %d", CPSWAP_SWAP_ACCOUNTS_LENGTH, len(accounts))
+	}
+	if !accounts[0].IsSigner || !accounts[1].PublicKey.Equals(CpSwapAuthorityID) {
+		t.Errorf("Expected signing payer followed by the CP-Swap authority")
+	}
+	if !accounts[8].PublicKey.Equals(TokenProgramID) || !accounts[9].PublicKey.Equals(Token2022ProgramID) {
+		t.Errorf("Expected per-side token programs")
+	}
+
+	instruction, err = newSwap().SetBaseOut(true).SetMaxAmountIn(1_100).SetAmountOut(1_000).Build()
+	if err != nil {
+		t.Fatalf("Failed to build swap_base_output: %v", err)
+	}
+	data, _ = instruction.Data()
+	if [8]byte(data[:8]) != cpSwapSwapBaseOutputDiscriminator || binary.LittleEndian.Uint64(data[8:16]) != 1_100 {
+		t.Errorf("Unexpected swap_base_output data %x", data)
+	}
+
+	err = NewCpSwapInstruction().SetInputMint(keys[7]).SetOutputMint(keys[7]).Validate()
+	if err == nil || !strings.Contains(err.Error(), "missing payer") || !strings.Contains(err.Error(), "input and output mints are the same") {
+		t.Errorf("Unexpected validation error: %v", err)
+	}
+}
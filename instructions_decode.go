@@ -0,0 +1,261 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// decodedInstruction holds the program, account keys and data of an instruction being
+// decoded back into its builder
+type decodedInstruction struct {
+	programID solana.PublicKey
+	keys      []solana.PublicKey
+	data      []byte
+}
+
+// newDecodedInstruction reads an instruction, requiring at least minAccounts accounts
+// and exactly dataLength bytes of data (any length when dataLength is negative)
+func newDecodedInstruction(instruction solana.Instruction, name string, minAccounts, dataLength int) (*decodedInstruction, error) {
+	if instruction == nil {
+		return nil, fmt.Errorf("%s instruction is nil", name)
+	}
+	data, err := instruction.Data()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s instruction data: %w", name, err)
+	}
+	if dataLength >= 0 && len(data) != dataLength {
+		return nil, fmt.Errorf("%s instruction data is %d bytes, want %d", name, len(data), dataLength)
+	}
+
+	metas := instruction.Accounts()
+	if len(metas) < minAccounts {
+		return nil, fmt.Errorf("insufficient accounts for %s: got %d, need %d", name, len(metas), minAccounts)
+	}
+	keys := make([]solana.PublicKey, len(metas))
+	for i, meta := range metas {
+		keys[i] = meta.PublicKey
+	}
+	return &decodedInstruction{programID: instruction.ProgramID(), keys: keys, data: data}, nil
+}
+
+func (d *decodedInstruction) u64(offset int) uint64 {
+	return binary.LittleEndian.Uint64(d.data[offset : offset+8])
+}
+
+// DecodeSwapInstruction decodes an AMM v4 swapBaseIn / swapBaseOut instruction, with or
+// without the OpenBook accounts, back into its builder
+func DecodeSwapInstruction(instruction solana.Instruction) (*SwapInstruction, error) {
+	d, err := newDecodedInstruction(instruction, "swap", AMM_V4_SWAP_WITHOUT_OPENBOOK_ACCOUNTS_LENGTH, 17)
+	if err != nil {
+		return nil, err
+	}
+
+	s := NewSwapInstruction().SetProgramID(d.programID)
+	switch d.data[0] {
+	case AMM_V4_INSTRUCTION_SWAP_BASE_IN:
+	case AMM_V4_INSTRUCTION_SWAP_BASE_OUT:
+		s.SetBaseOut(true)
+	case AMM_V4_INSTRUCTION_SWAP_BASE_IN_V2:
+		s.SetWithoutOpenBook(true)
+	case AMM_V4_INSTRUCTION_SWAP_BASE_OUT_V2:
+		s.SetBaseOut(true).SetWithoutOpenBook(true)
+	default:
+		return nil, fmt.Errorf("not an AMM v4 swap instruction: tag %d", d.data[0])
+	}
+	if s.baseOut {
+		s.SetMaxAmountIn(d.u64(1)).SetAmountOut(d.u64(9))
+	} else {
+		s.SetAmountIn(d.u64(1)).SetMinimumAmountOut(d.u64(9))
+	}
+
+	k := d.keys
+	if s.withoutOpenBook {
+		if len(k) != AMM_V4_SWAP_WITHOUT_OPENBOOK_ACCOUNTS_LENGTH {
+			return nil, fmt.Errorf("swap without OpenBook has %d accounts, want %d", len(k), AMM_V4_SWAP_WITHOUT_OPENBOOK_ACCOUNTS_LENGTH)
+		}
+		return s.SetAmmID(k[1]).SetAmmAuthority(k[2]).SetPoolCoinToken(k[3]).SetPoolPcToken(k[4]).
+			SetUserSourceToken(k[5]).SetUserDestToken(k[6]).SetUserOwner(k[7]), nil
+	}
+	if len(k) != AMM_V4_SWAP_ACCOUNTS_LENGTH {
+		return nil, fmt.Errorf("swap has %d accounts, want %d", len(k), AMM_V4_SWAP_ACCOUNTS_LENGTH)
+	}
+	return s.SetAmmID(k[1]).SetAmmAuthority(k[2]).SetAmmOpenOrders(k[3]).SetAmmTargetOrders(k[4]).
+		SetPoolCoinToken(k[5]).SetPoolPcToken(k[6]).
+		SetSerumProgram(k[7]).SetSerumMarket(k[8]).SetSerumBids(k[9]).SetSerumAsks(k[10]).
+		SetSerumEventQueue(k[11]).SetSerumCoinVault(k[12]).SetSerumPcVault(k[13]).SetSerumVaultSigner(k[14]).
+		SetUserSourceToken(k[15]).SetUserDestToken(k[16]).SetUserOwner(k[17]), nil
+}
+
+// DecodeCpSwapInstruction decodes a CP-Swap swap_base_input / swap_base_output instruction
+func DecodeCpSwapInstruction(instruction solana.Instruction) (*CpSwapInstruction, error) {
+	d, err := newDecodedInstruction(instruction, "cp-swap swap", CPSWAP_SWAP_ACCOUNTS_LENGTH, 24)
+	if err != nil {
+		return nil, err
+	}
+
+	k := d.keys
+	c := NewCpSwapInstruction().
+		SetProgramID(d.programID).
+		SetPayer(k[0]).SetAuthority(k[1]).SetAmmConfig(k[2]).SetPoolState(k[3]).
+		SetInputTokenAccount(k[4]).SetOutputTokenAccount(k[5]).
+		SetInputVault(k[6]).SetOutputVault(k[7]).
+		SetInputTokenProgram(k[8]).SetOutputTokenProgram(k[9]).
+		SetInputMint(k[10]).SetOutputMint(k[11]).
+		SetObservationState(k[12])
+
+	switch [8]byte(d.data[:8]) {
+	case cpSwapSwapBaseInputDiscriminator:
+		c.SetAmountIn(d.u64(8)).SetMinimumAmountOut(d.u64(16))
+	case cpSwapSwapBaseOutputDiscriminator:
+		c.SetBaseOut(true).SetMaxAmountIn(d.u64(8)).SetAmountOut(d.u64(16))
+	default:
+		return nil, fmt.Errorf("not a CP-Swap swap instruction: %x", d.data[:8])
+	}
+	return c, nil
+}
+
+// DecodeClmmSwapInstruction decodes a CLMM swap_v2 instruction. The first remaining
+// account is taken as the tick array bitmap extension when it is the pool's derived
+// extension address; every other remaining account is a tick array.
+func DecodeClmmSwapInstruction(instruction solana.Instruction) (*ClmmSwapInstruction, error) {
+	d, err := newDecodedInstruction(instruction, "clmm swap", CLMM_SWAP_V2_ACCOUNTS_LENGTH, 41)
+	if err != nil {
+		return nil, err
+	}
+	if [8]byte(d.data[:8]) != clmmSwapV2Discriminator {
+		return nil, fmt.Errorf("not a CLMM swap_v2 instruction: %x", d.data[:8])
+	}
+
+	k := d.keys
+	c := NewClmmSwapInstruction().
+		SetProgramID(d.programID).
+		SetPayer(k[0]).SetAmmConfig(k[1]).SetPoolState(k[2]).
+		SetInputTokenAccount(k[3]).SetOutputTokenAccount(k[4]).
+		SetInputVault(k[5]).SetOutputVault(k[6]).SetObservationState(k[7]).
+		SetInputVaultMint(k[11]).SetOutputVaultMint(k[12])
+
+	remaining := k[CLMM_SWAP_V2_ACCOUNTS_LENGTH:]
+	if len(remaining) > 0 {
+		extension, err := DeriveClmmTickArrayBitmapExtension(d.programID, c.poolState)
+		if err == nil && remaining[0].Equals(extension) {
+			c.SetTickArrayBitmapExtension(extension)
+			remaining = remaining[1:]
+		}
+	}
+	c.SetTickArrays(remaining...)
+
+	// sqrt_price_limit_x64 is a little-endian u128; zero means no limit
+	limit := make([]byte, 16)
+	for i := range limit {
+		limit[i] = d.data[39-i]
+	}
+	if sqrtPriceLimitX64 := new(big.Int).SetBytes(limit); sqrtPriceLimitX64.Sign() != 0 {
+		c.SetSqrtPriceLimitX64(sqrtPriceLimitX64)
+	}
+
+	if d.data[40] == 1 {
+		c.SetAmountIn(d.u64(8)).SetMinimumAmountOut(d.u64(16))
+	} else {
+		c.SetBaseOut(true).SetAmountOut(d.u64(8)).SetMaxAmountIn(d.u64(16))
+	}
+	return c, nil
+}
+
+// decodeLaunchpadTrade reads the accounts and arguments shared by Launchpad buys and sells
+func decodeLaunchpadTrade(instruction solana.Instruction, name string, exactInDiscriminator, exactOutDiscriminator [8]byte) (launchpadTradeAccounts, launchpadTradeAmounts, error) {
+	d, err := newDecodedInstruction(instruction, name, LAUNCHPAD_TRADE_ACCOUNTS_LENGTH, 32)
+	if err != nil {
+		return launchpadTradeAccounts{}, launchpadTradeAmounts{}, err
+	}
+
+	var amounts launchpadTradeAmounts
+	switch [8]byte(d.data[:8]) {
+	case exactInDiscriminator:
+		amounts.amountIn = d.u64(8)
+		amounts.minimumAmountOut = d.u64(16)
+	case exactOutDiscriminator:
+		amounts.exactOut = true
+		amounts.amountOut = d.u64(8)
+		amounts.maxAmountIn = d.u64(16)
+	default:
+		return launchpadTradeAccounts{}, launchpadTradeAmounts{}, fmt.Errorf("not a %s instruction: %x", name, d.data[:8])
+	}
+	amounts.shareFeeRate = d.u64(24)
+
+	k := d.keys
+	accounts := launchpadTradeAccounts{
+		programID:         d.programID,
+		payer:             k[0],
+		authority:         k[1],
+		globalConfig:      k[2],
+		platformConfig:    k[3],
+		poolState:         k[4],
+		userBaseToken:     k[5],
+		userQuoteToken:    k[6],
+		baseVault:         k[7],
+		quoteVault:        k[8],
+		baseMint:          k[9],
+		quoteMint:         k[10],
+		baseTokenProgram:  k[11],
+		quoteTokenProgram: k[12],
+		eventAuthority:    k[13],
+	}
+	return accounts, amounts, nil
+}
+
+// DecodeBuyInstruction decodes a Launchpad buy_exact_in / buy_exact_out instruction
+func DecodeBuyInstruction(instruction solana.Instruction) (*BuyInstruction, error) {
+	accounts, amounts, err := decodeLaunchpadTrade(instruction, "launchpad buy", launchpadBuyExactInDiscriminator, launchpadBuyExactOutDiscriminator)
+	if err != nil {
+		return nil, err
+	}
+	return &BuyInstruction{launchpadTradeAccounts: accounts, launchpadTradeAmounts: amounts}, nil
+}
+
+// DecodeSellInstruction decodes a Launchpad sell_exact_in / sell_exact_out instruction
+func DecodeSellInstruction(instruction solana.Instruction) (*SellInstruction, error) {
+	accounts, amounts, err := decodeLaunchpadTrade(instruction, "launchpad sell", launchpadSellExactInDiscriminator, launchpadSellExactOutDiscriminator)
+	if err != nil {
+		return nil, err
+	}
+	return &SellInstruction{launchpadTradeAccounts: accounts, launchpadTradeAmounts: amounts}, nil
+}
+
+// DecodeCreateTokenInstruction decodes a Launchpad initialize instruction. initialize_v2
+// is rejected because CreateTokenInstruction cannot rebuild its extra arguments.
+func DecodeCreateTokenInstruction(instruction solana.Instruction) (*CreateTokenInstruction, error) {
+	d, err := newDecodedInstruction(instruction, "create token", initializeAccountsLength, -1)
+	if err != nil {
+		return nil, err
+	}
+	if len(d.data) < 8 || [8]byte(d.data[:8]) != launchpadInitializeDiscriminator {
+		return nil, fmt.Errorf("not a launchpad initialize instruction")
+	}
+	params, err := DecodeLaunchpadInitializeParams(d.data)
+	if err != nil {
+		return nil, err
+	}
+
+	k := d.keys
+	c := NewCreateTokenInstruction().
+		SetProgramID(d.programID).
+		SetPayer(k[initializePayer]).
+		SetCreator(k[initializeCreator]).
+		SetGlobalConfig(k[initializeGlobalConfig]).
+		SetPlatformConfig(k[initializePlatformConfig]).
+		SetAuthority(k[initializeAuthority]).
+		SetPoolState(k[initializePoolState]).
+		SetBaseMint(k[initializeBaseMint]).
+		SetQuoteMint(k[initializeQuoteMint]).
+		SetBaseVault(k[initializeBaseVault]).
+		SetQuoteVault(k[initializeQuoteVault]).
+		SetMetadata(k[initializeMetadata]).
+		SetBaseTokenProgram(k[initializeBaseTokenProgram]).
+		SetQuoteTokenProgram(k[initializeQuoteTokenProgram]).
+		SetEventAuthority(k[initializeEventAuthority])
+	c.params = *params
+	return c, nil
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+const roundTripIterations = 50
+
+// randomKey returns a deterministic non-zero public key
+func randomKey(rng *rand.Rand) solana.PublicKey {
+	var key solana.PublicKey
+	rng.Read(key[:])
+	key[0] |= 1
+	return key
+}
+
+// randomAmount returns a non-zero amount, sometimes at the u64 extremes
+func randomAmount(rng *rand.Rand) uint64 {
+	switch rng.Intn(4) {
+	case 0:
+		return 1
+	case 1:
+		return ^uint64(0)
+	default:
+		return rng.Uint64()>>uint(rng.Intn(64)) | 1
+	}
+}
+
+// assertSameInstruction fails unless both instructions have the same program, accounts and data
+func assertSameInstruction(t *testing.T, want, got solana.Instruction) {
+	t.Helper()
+	if !want.ProgramID().Equals(got.ProgramID()) {
+		t.Fatalf("Program ID changed: %s -> %s", want.ProgramID(), got.ProgramID())
+	}
+	wantAccounts, gotAccounts := want.Accounts(), got.Accounts()
+	if len(wantAccounts) != len(gotAccounts) {
+		t.Fatalf("Account count changed: %d -> %d", len(wantAccounts), len(gotAccounts))
+	}
+	for i := range wantAccounts {
+		if *wantAccounts[i] != *gotAccounts[i] {
+			t.Fatalf("Account %d changed: %+v -> %+v", i, *wantAccounts[i], *gotAccounts[i])
+		}
+	}
+	wantData, _ := want.Data()
+	gotData, _ := got.Data()
+	if !bytes.Equal(wantData, gotData) {
+		t.Fatalf("Data changed: %x -> %x", wantData, gotData)
+	}
+}
+
+// roundTrip builds, decodes and rebuilds an instruction, requiring the two builds to match
+func roundTrip[B interface {
+	Build() (solana.Instruction, error)
+}](t *testing.T, builder B, decode func(solana.Instruction) (B, error)) solana.Instruction {
+	t.Helper()
+	built, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Failed to build: %v", err)
+	}
+	decoded, err := decode(built)
+	if err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	rebuilt, err := decoded.Build()
+	if err != nil {
+		t.Fatalf("Failed to rebuild decoded instruction: %v", err)
+	}
+	assertSameInstruction(t, built, rebuilt)
+	return built
+}
+
+func TestSwapInstructionRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < roundTripIterations; i++ {
+		s := NewSwapInstruction().
+			SetUserSourceToken(randomKey(rng)).SetUserDestToken(randomKey(rng)).SetUserOwner(randomKey(rng)).
+			SetAmmID(randomKey(rng)).SetPoolCoinToken(randomKey(rng)).SetPoolPcToken(randomKey(rng)).
+			SetAmmOpenOrders(randomKey(rng)).SetAmmTargetOrders(randomKey(rng)).
+			SetSerumProgram(randomKey(rng)).SetSerumMarket(randomKey(rng)).SetSerumBids(randomKey(rng)).
+			SetSerumAsks(randomKey(rng)).SetSerumEventQueue(randomKey(rng)).SetSerumCoinVault(randomKey(rng)).
+			SetSerumPcVault(randomKey(rng)).SetSerumVaultSigner(randomKey(rng)).
+			SetWithoutOpenBook(rng.Intn(2) == 0)
+		if rng.Intn(2) == 0 {
+			s.SetBaseOut(true).SetMaxAmountIn(randomAmount(rng)).SetAmountOut(randomAmount(rng))
+		} else {
+			s.SetAmountIn(randomAmount(rng)).SetMinimumAmountOut(rng.Uint64())
+		}
+		roundTrip(t, s, DecodeSwapInstruction)
+	}
+}
+
+func TestCpSwapInstructionRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	tokenPrograms := []solana.PublicKey{TokenProgramID, Token2022ProgramID}
+	for i := 0; i < roundTripIterations; i++ {
+		c := NewCpSwapInstruction().
+			SetPayer(randomKey(rng)).SetAmmConfig(randomKey(rng)).SetPoolState(randomKey(rng)).
+			SetInputTokenAccount(randomKey(rng)).SetOutputTokenAccount(randomKey(rng)).
+			SetInputVault(randomKey(rng)).SetOutputVault(randomKey(rng)).
+			SetInputTokenProgram(tokenPrograms[rng.Intn(2)]).SetOutputTokenProgram(tokenPrograms[rng.Intn(2)]).
+			SetInputMint(randomKey(rng)).SetOutputMint(randomKey(rng)).SetObservationState(randomKey(rng))
+		if rng.Intn(2) == 0 {
+			c.SetBaseOut(true).SetMaxAmountIn(randomAmount(rng)).SetAmountOut(randomAmount(rng))
+		} else {
+			c.SetAmountIn(randomAmount(rng)).SetMinimumAmountOut(rng.Uint64())
+		}
+		roundTrip(t, c, DecodeCpSwapInstruction)
+	}
+}
+
+func TestClmmSwapInstructionRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	for i := 0; i < roundTripIterations; i++ {
+		pool := randomKey(rng)
+		tickArrays := make([]solana.PublicKey, 1+rng.Intn(clmmMaxTickArrays))
+		for j := range tickArrays {
+			tickArrays[j] = randomKey(rng)
+		}
+		c := NewClmmSwapInstruction().
+			SetPayer(randomKey(rng)).SetAmmConfig(randomKey(rng)).SetPoolState(pool).
+			SetInputTokenAccount(randomKey(rng)).SetOutputTokenAccount(randomKey(rng)).
+			SetInputVault(randomKey(rng)).SetOutputVault(randomKey(rng)).SetObservationState(randomKey(rng)).
+			SetInputVaultMint(randomKey(rng)).SetOutputVaultMint(randomKey(rng)).
+			SetTickArrays(tickArrays...)
+		if rng.Intn(2) == 0 {
+			extension, _ := DeriveClmmTickArrayBitmapExtension(RaydiumClmmProgramID, pool)
+			c.SetTickArrayBitmapExtension(extension)
+		}
+		if rng.Intn(2) == 0 {
+			c.SetSqrtPriceLimitX64(new(big.Int).Rand(rng, clmmMaxSqrtPriceLimit))
+		}
+		if rng.Intn(2) == 0 {
+			c.SetBaseOut(true).SetMaxAmountIn(randomAmount(rng)).SetAmountOut(randomAmount(rng))
+		} else {
+			c.SetAmountIn(randomAmount(rng)).SetMinimumAmountOut(rng.Uint64())
+		}
+
+		built := roundTrip(t, c, DecodeClmmSwapInstruction)
+		decoded, _ := DecodeClmmSwapInstruction(built)
+		if !decoded.tickArrayBitmapExtension.Equals(c.tickArrayBitmapExtension) || len(decoded.tickArrays) != len(tickArrays) {
+			t.Fatalf("Bitmap extension and tick arrays were not separated")
+		}
+	}
+}
+
+func TestLaunchpadInstructionRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	for i := 0; i < roundTripIterations; i++ {
+		exactOut := rng.Intn(2) == 0
+		amountIn, minimumAmountOut := randomAmount(rng), rng.Uint64()
+		amountOut, maxAmountIn := randomAmount(rng), randomAmount(rng)
+		shareFeeRate := uint64(rng.Intn(LAUNCHPAD_FEE_RATE_DENOMINATOR))
+		keys := make([]solana.PublicKey, 8)
+		for j := range keys {
+			keys[j] = randomKey(rng)
+		}
+
+		buy := NewBuyInstruction().
+			SetPayer(keys[0]).SetPlatformConfig(keys[1]).SetPoolState(keys[2]).
+			SetUserBaseToken(keys[3]).SetUserQuoteToken(keys[4]).SetBaseVault(keys[5]).SetQuoteVault(keys[6]).
+			SetBaseMint(keys[7]).SetBaseTokenProgram(Token2022ProgramID).
+			SetExactOut(exactOut).SetAmountIn(amountIn).SetMinimumAmountOut(minimumAmountOut).
+			SetAmountOut(amountOut).SetMaxAmountIn(maxAmountIn).SetShareFeeRate(shareFeeRate)
+		roundTrip(t, buy, DecodeBuyInstruction)
+
+		sell := NewSellInstruction().
+			SetPayer(keys[0]).SetPlatformConfig(keys[1]).SetPoolState(keys[2]).
+			SetUserBaseToken(keys[3]).SetUserQuoteToken(keys[4]).SetBaseVault(keys[5]).SetQuoteVault(keys[6]).
+			SetBaseMint(keys[7]).
+			SetExactOut(exactOut).SetAmountIn(amountIn).SetMinimumAmountOut(minimumAmountOut).
+			SetAmountOut(amountOut).SetMaxAmountIn(maxAmountIn).SetShareFeeRate(shareFeeRate)
+		roundTrip(t, sell, DecodeSellInstruction)
+	}
+}
+
+func TestCreateTokenInstructionRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(6))
+	for i := 0; i < roundTripIterations; i++ {
+		supply := randomAmount(rng)
+		curveType := uint8(rng.Intn(LAUNCHPAD_CURVE_LINEAR_PRICE + 1))
+		var totalBaseSell uint64
+		if curveType == LAUNCHPAD_CURVE_CONSTANT_PRODUCT {
+			totalBaseSell = rng.Uint64()%supply + 1
+		}
+		c := NewCreateTokenInstruction().
+			SetPayer(randomKey(rng)).SetCreator(randomKey(rng)).SetPlatformConfig(randomKey(rng)).
+			SetBaseMint(randomKey(rng)).
+			SetDecimals(uint8(rng.Intn(10))).
+			SetName(strings.Repeat("n", 1+rng.Intn(METADATA_MAX_NAME_LENGTH))).
+			SetSymbol(strings.Repeat("S", 1+rng.Intn(METADATA_MAX_SYMBOL_LENGTH))).
+			SetURI(strings.Repeat("u", rng.Intn(METADATA_MAX_URI_LENGTH+1))).
+			SetCurveType(curveType).
+			SetSupply(supply).SetTotalBaseSell(totalBaseSell).SetTotalQuoteFundRaising(randomAmount(rng)).
+			SetMigrateType(uint8(rng.Intn(LAUNCHPAD_MIGRATE_TYPE_CPSWAP+1))).
+			SetVesting((supply-totalBaseSell)/2, rng.Uint64(), rng.Uint64())
+		roundTrip(t, c, DecodeCreateTokenInstruction)
+	}
+}
+
+func TestDecodeRejectsOtherInstructions(t *testing.T) {
+	keys := uniqueAccounts(8)
+	buy, err := NewBuyInstruction().SetPayer(keys[0]).SetPlatformConfig(keys[1]).SetBaseMint(keys[2]).SetAmountIn(1).Build()
+	if err != nil {
+		t.Fatalf("Failed to build buy: %v", err)
+	}
+
+	if _, err := DecodeSwapInstruction(buy); err == nil {
+		t.Errorf("Expected swap decode of a buy instruction to fail")
+	}
+	if _, err := DecodeSellInstruction(buy); err == nil || !strings.Contains(err.Error(), "not a launchpad sell") {
+		t.Errorf("Expected sell decode of a buy instruction to fail, got %v", err)
+	}
+	if _, err := DecodeCpSwapInstruction(buy); err == nil {
+		t.Errorf("Expected CP-Swap decode of a buy instruction to fail")
+	}
+	if _, err := DecodeCreateTokenInstruction(buy); err == nil {
+		t.Errorf("Expected create token decode of a buy instruction to fail")
+	}
+	if _, err := DecodeSwapInstruction(nil); err == nil {
+		t.Errorf("Expected decode of a nil instruction to fail")
+	}
+}
+
+// compileInstruction places a built instruction in a message the parser can read
+func compileInstruction(t *testing.T, instruction solana.Instruction) (solana.CompiledInstruction, *solana.Message) {
+	t.Helper()
+	var payer solana.PublicKey
+	for _, account := range instruction.Accounts() {
+		if account.IsSigner {
+			payer = account.PublicKey
+			break
+		}
+	}
+	tx, err := solana.NewTransaction([]solana.Instruction{instruction}, solana.Hash{}, solana.TransactionPayer(payer))
+	if err != nil {
+		t.Fatalf("Failed to compile transaction: %v", err)
+	}
+	return tx.Message.Instructions[0], &tx.Message
+}
+
+func TestParserAgreesWithBuilders(t *testing.T) {
+	keys := uniqueAccounts(8)
+
+	// Launchpad initialize
+	create, err := NewCreateTokenInstruction().
+		SetPayer(keys[0]).SetCreator(keys[1]).SetPlatformConfig(LetsBonkPlatformConfigID).SetBaseMint(keys[2]).
+		SetName(sampleInitializeParams.Name).SetSymbol(sampleInitializeParams.Symbol).SetURI(sampleInitializeParams.URI).
+		SetSupply(sampleInitializeParams.Supply).SetTotalBaseSell(sampleInitializeParams.TotalBaseSell).
+		SetTotalQuoteFundRaising(sampleInitializeParams.TotalQuoteFundRaising).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build create token: %v", err)
+	}
+	compiled, message := compileInstruction(t, create)
+	result := &Transaction{}
+	if err := parseLaunchpadInitializeInstruction(compiled, message, 0, result); err != nil {
+		t.Fatalf("Parser rejected built initialize: %v", err)
+	}
+	pool, _ := DeriveLaunchpadPoolState(RaydiumLaunchpadV1ProgramID, keys[2], solana.SolMint)
+	c := result.Create[0]
+	if !c.TokenMint.Equals(keys[2]) || !c.Creator.Equals(keys[1]) || !c.PoolAddress.Equals(pool) ||
+		!c.MintAuthority.Equals(LaunchpadAuthorityID) || c.TokenName != sampleInitializeParams.Name ||
+		c.Supply != sampleInitializeParams.Supply {
+		t.Errorf("Parsed create %+v does not match the builder", c)
+	}
+
+	// AMM v4 swap amounts
+	swap, err := NewSwapInstruction().SetWithoutOpenBook(true).
+		SetAmmID(keys[1]).SetPoolCoinToken(keys[2]).SetPoolPcToken(keys[3]).
+		SetUserSourceToken(keys[4]).SetUserDestToken(keys[5]).SetUserOwner(keys[0]).
+		SetAmountIn(1_000).SetMinimumAmountOut(900).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build swap: %v", err)
+	}
+	compiled, message = compileInstruction(t, swap)
+	result = &Transaction{}
+	if err := parseSwapInstruction(compiled, message, 0, result); err != nil {
+		t.Fatalf("Parser rejected built swap: %v", err)
+	}
+	if result.Trade[0].AmountIn != 1_000 || !result.Trade[0].Trader.Equals(keys[0]) {
+		t.Errorf("Parsed trade %+v does not match the builder", result.Trade[0])
+	}
+}
+
+// TestParseMigrateLayout pins the account order the parser reads migrations in: from pool,
+// to pool, token account, then the signing authority
+func TestParseMigrateLayout(t *testing.T) {
+	keys := uniqueAccounts(4)
+	fromPool, toPool, token, owner := keys[0], keys[1], keys[2], keys[3]
+	data := binary.LittleEndian.AppendUint64([]byte{INSTRUCTION_MIGRATE}, 42)
+
+	message := &solana.Message{AccountKeys: []solana.PublicKey{fromPool, toPool, token, owner, RaydiumV4ProgramID}}
+	compiled := solana.CompiledInstruction{ProgramIDIndex: 4, Accounts: []uint16{0, 1, 2, 3}, Data: data}
+	result := &Transaction{}
+	if err := parseMigrateInstruction(compiled, message, 0, result); err != nil {
+		t.Fatalf("Failed to parse migrate: %v", err)
+	}
+	geyser := &Transaction{}
+	instruction := GeyserInstruction{ProgramID: RaydiumV4ProgramID, Accounts: []solana.PublicKey{fromPool, toPool, token, owner}, Data: data}
+	if err := parseGeyserMigrateInstruction(instruction, 0, geyser, nil); err != nil {
+		t.Fatalf("Failed to parse Geyser migrate: %v", err)
+	}
+
+	for _, m := range []Migration{result.Migrate[0], geyser.Migrate[0]} {
+		if !m.FromPool.Equals(fromPool) || !m.ToPool.Equals(toPool) || !m.Token.Equals(token) || !m.Owner.Equals(owner) || m.Amount != 42 {
+			t.Errorf("Unexpected migration %+v", m)
+		}
+	}
+}
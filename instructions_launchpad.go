@@ -0,0 +1,791 @@
+package main
+
+import (
+	"encoding/binary"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Well-known Launchpad accounts
+var (
+	// LaunchpadAuthorityID is the vault authority, the program address of seed "vault_auth_seed"
+	LaunchpadAuthorityID = solana.MustPublicKeyFromBase58("WLHv2UAZm6z4KyaaELi5pjdbJh6RESMva1Rnn8pJVVh")
+	// LaunchpadGlobalConfigID is the global config for SOL-quoted constant-product launches
+	LaunchpadGlobalConfigID = solana.MustPublicKeyFromBase58("6s1xP3hpbAfFoNtUNF8mfHsjr2Bd97JxFJRWLbL6aHuX")
+	// LaunchpadEventAuthorityID is the Anchor event authority, the program address of seed "__event_authority"
+	LaunchpadEventAuthorityID = solana.MustPublicKeyFromBase58("2DPAtwB8L12vrMRExbLuyGnC7n2J5LNoZQSejeQGpwkr")
+	// LetsBonkPlatformConfigID is the platform config used by LetsBonk launches
+	LetsBonkPlatformConfigID = solana.MustPublicKeyFromBase58("FfYek5vEz23cMkWsdJwG2oa6EphsvXSHrGpdALN4g6W1")
+)
+
+var (
+	launchpadBuyExactInDiscriminator   = anchorInstructionDiscriminator("buy_exact_in")
+	launchpadBuyExactOutDiscriminator  = anchorInstructionDiscriminator("buy_exact_out")
+	launchpadSellExactInDiscriminator  = anchorInstructionDiscriminator("sell_exact_in")
+	launchpadSellExactOutDiscriminator = anchorInstructionDiscriminator("sell_exact_out")
+)
+
+// Launchpad trade account count
+const LAUNCHPAD_TRADE_ACCOUNTS_LENGTH = 15
+
+// launchpadTradeAccounts holds the accounts shared by every Launchpad buy and sell
+type launchpadTradeAccounts struct {
+	programID         solana.PublicKey
+	payer             solana.PublicKey
+	authority         solana.PublicKey
+	globalConfig      solana.PublicKey
+	platformConfig    solana.PublicKey
+	poolState         solana.PublicKey
+	userBaseToken     solana.PublicKey
+	userQuoteToken    solana.PublicKey
+	baseVault         solana.PublicKey
+	quoteVault        solana.PublicKey
+	baseMint          solana.PublicKey
+	quoteMint         solana.PublicKey
+	baseTokenProgram  solana.PublicKey
+	quoteTokenProgram solana.PublicKey
+	eventAuthority    solana.PublicKey
+}
+
+// defaultLaunchpadTradeAccounts fills the accounts that are the same for SOL-quoted pools
+func defaultLaunchpadTradeAccounts() launchpadTradeAccounts {
+	return launchpadTradeAccounts{
+		programID:         RaydiumLaunchpadV1ProgramID,
+		globalConfig:      LaunchpadGlobalConfigID,
+		quoteMint:         solana.SolMint,
+		baseTokenProgram:  TokenProgramID,
+		quoteTokenProgram: TokenProgramID,
+	}
+}
+
+// withDerivedAccounts returns the accounts with the authority, event authority, pool,
+// vaults and the payer's associated token accounts derived where unset
+func (a launchpadTradeAccounts) withDerivedAccounts() launchpadTradeAccounts {
+	deriveIfZero(&a.authority, func() (solana.PublicKey, error) { return DeriveLaunchpadAuthority(a.programID) }, a.programID)
+	deriveIfZero(&a.eventAuthority, func() (solana.PublicKey, error) { return DeriveEventAuthority(a.programID) }, a.programID)
+	deriveIfZero(&a.poolState, func() (solana.PublicKey, error) {
+		return DeriveLaunchpadPoolState(a.programID, a.baseMint, a.quoteMint)
+	}, a.programID, a.baseMint, a.quoteMint)
+	deriveIfZero(&a.baseVault, func() (solana.PublicKey, error) {
+		return DeriveLaunchpadVault(a.programID, a.poolState, a.baseMint)
+	}, a.programID, a.poolState, a.baseMint)
+	deriveIfZero(&a.quoteVault, func() (solana.PublicKey, error) {
+		return DeriveLaunchpadVault(a.programID, a.poolState, a.quoteMint)
+	}, a.programID, a.poolState, a.quoteMint)
+	deriveIfZero(&a.userBaseToken, func() (solana.PublicKey, error) {
+		return DeriveAssociatedTokenAddress(a.payer, a.baseMint, a.baseTokenProgram)
+	}, a.payer, a.baseMint, a.baseTokenProgram)
+	deriveIfZero(&a.userQuoteToken, func() (solana.PublicKey, error) {
+		return DeriveAssociatedTokenAddress(a.payer, a.quoteMint, a.quoteTokenProgram)
+	}, a.payer, a.quoteMint, a.quoteTokenProgram)
+	return a
+}
+
+func (a *launchpadTradeAccounts) validate(v *instructionValidator) {
+	v.account("program ID", a.programID)
+	v.account("payer", a.payer)
+	v.account("authority", a.authority)
+	v.account("global config", a.globalConfig)
+	v.account("platform config", a.platformConfig)
+	v.account("pool state", a.poolState)
+	v.account("user base token account", a.userBaseToken)
+	v.account("user quote token account", a.userQuoteToken)
+	v.account("base vault", a.baseVault)
+	v.account("quote vault", a.quoteVault)
+	v.account("base mint", a.baseMint)
+	v.account("quote mint", a.quoteMint)
+	v.account("base token program", a.baseTokenProgram)
+	v.account("quote token program", a.quoteTokenProgram)
+	v.account("event authority", a.eventAuthority)
+}
+
+func (a *launchpadTradeAccounts) accountMetas() solana.AccountMetaSlice {
+	return solana.AccountMetaSlice{
+		{PublicKey: a.payer, IsWritable: false, IsSigner: true},
+		{PublicKey: a.authority, IsWritable: false, IsSigner: false},
+		{PublicKey: a.globalConfig, IsWritable: false, IsSigner: false},
+		{PublicKey: a.platformConfig, IsWritable: false, IsSigner: false},
+		{PublicKey: a.poolState, IsWritable: true, IsSigner: false},
+		{PublicKey: a.userBaseToken, IsWritable: true, IsSigner: false},
+		{PublicKey: a.userQuoteToken, IsWritable: true, IsSigner: false},
+		{PublicKey: a.baseVault, IsWritable: true, IsSigner: false},
+		{PublicKey: a.quoteVault, IsWritable: true, IsSigner: false},
+		{PublicKey: a.baseMint, IsWritable: false, IsSigner: false},
+		{PublicKey: a.quoteMint, IsWritable: false, IsSigner: false},
+		{PublicKey: a.baseTokenProgram, IsWritable: false, IsSigner: false},
+		{PublicKey: a.quoteTokenProgram, IsWritable: false, IsSigner: false},
+		{PublicKey: a.eventAuthority, IsWritable: false, IsSigner: false},
+		{PublicKey: a.programID, IsWritable: false, IsSigner: false},
+	}
+}
+
+// launchpadTradeAmounts holds the arguments of a Launchpad buy or sell
+type launchpadTradeAmounts struct {
+	exactOut bool
+
+	// exact-in amounts
+	amountIn          uint64
+	minimumAmountOut  uint64
+	expectedAmountOut uint64
+
+	// exact-out amounts
+	amountOut        uint64
+	maxAmountIn      uint64
+	expectedAmountIn uint64
+
+	shareFeeRate uint64
+}
+
+func (a *launchpadTradeAmounts) validate(v *instructionValidator) {
+	validateSwapAmounts(v, a.exactOut, a.amountIn, a.minimumAmountOut, a.expectedAmountOut, a.maxAmountIn, a.amountOut, a.expectedAmountIn)
+	v.check(a.shareFeeRate < LAUNCHPAD_FEE_RATE_DENOMINATOR,
+		"share fee rate %d must be below %d", a.shareFeeRate, LAUNCHPAD_FEE_RATE_DENOMINATOR)
+}
+
+// data encodes the discriminator followed by the two amounts and share_fee_rate.
+// Exact-in takes (amount_in, minimum_amount_out), exact-out (amount_out, maximum_amount_in).
+func (a *launchpadTradeAmounts) data(exactInDiscriminator, exactOutDiscriminator [8]byte) []byte {
+	data := make([]byte, 32)
+	if a.exactOut {
+		copy(data[:8], exactOutDiscriminator[:])
+		binary.LittleEndian.PutUint64(data[8:16], a.amountOut)
+		binary.LittleEndian.PutUint64(data[16:24], a.maxAmountIn)
+	} else {
+		copy(data[:8], exactInDiscriminator[:])
+		binary.LittleEndian.PutUint64(data[8:16], a.amountIn)
+		binary.LittleEndian.PutUint64(data[16:24], a.minimumAmountOut)
+	}
+	binary.LittleEndian.PutUint64(data[24:32], a.shareFeeRate)
+	return data
+}
+
+// BuyInstruction represents a Raydium Launchpad buy instruction, paying the quote token
+// for the base token
+type BuyInstruction struct {
+	launchpadTradeAccounts
+	launchpadTradeAmounts
+}
+
+// NewBuyInstruction creates a new Launchpad buy instruction builder. It builds
+// buy_exact_in by default; SetExactOut switches to buy_exact_out.
+func NewBuyInstruction() *BuyInstruction {
+	return &BuyInstruction{launchpadTradeAccounts: defaultLaunchpadTradeAccounts()}
+}
+
+// SetProgramID sets the program ID for the buy instruction
+func (b *BuyInstruction) SetProgramID(programID solana.PublicKey) *BuyInstruction {
+	b.programID = programID
+	return b
+}
+
+// SetPayer sets the signing trader
+func (b *BuyInstruction) SetPayer(payer solana.PublicKey) *BuyInstruction {
+	b.payer = payer
+	return b
+}
+
+// SetAuthority sets the Launchpad vault authority
+func (b *BuyInstruction) SetAuthority(authority solana.PublicKey) *BuyInstruction {
+	b.authority = authority
+	return b
+}
+
+// SetGlobalConfig sets the Launchpad global config
+func (b *BuyInstruction) SetGlobalConfig(globalConfig solana.PublicKey) *BuyInstruction {
+	b.globalConfig = globalConfig
+	return b
+}
+
+// SetPlatformConfig sets the launch platform config
+func (b *BuyInstruction) SetPlatformConfig(platformConfig solana.PublicKey) *BuyInstruction {
+	b.platformConfig = platformConfig
+	return b
+}
+
+// SetPoolState sets the Launchpad pool state account
+func (b *BuyInstruction) SetPoolState(poolState solana.PublicKey) *BuyInstruction {
+	b.poolState = poolState
+	return b
+}
+
+// SetUserBaseToken sets the user's base token account
+func (b *BuyInstruction) SetUserBaseToken(userBaseToken solana.PublicKey) *BuyInstruction {
+	b.userBaseToken = userBaseToken
+	return b
+}
+
+// SetUserQuoteToken sets the user's quote token account
+func (b *BuyInstruction) SetUserQuoteToken(userQuoteToken solana.PublicKey) *BuyInstruction {
+	b.userQuoteToken = userQuoteToken
+	return b
+}
+
+// SetBaseVault sets the pool base vault
+func (b *BuyInstruction) SetBaseVault(baseVault solana.PublicKey) *BuyInstruction {
+	b.baseVault = baseVault
+	return b
+}
+
+// SetQuoteVault sets the pool quote vault
+func (b *BuyInstruction) SetQuoteVault(quoteVault solana.PublicKey) *BuyInstruction {
+	b.quoteVault = quoteVault
+	return b
+}
+
+// SetBaseMint sets the base token mint
+func (b *BuyInstruction) SetBaseMint(baseMint solana.PublicKey) *BuyInstruction {
+	b.baseMint = baseMint
+	return b
+}
+
+// SetQuoteMint sets the quote token mint
+func (b *BuyInstruction) SetQuoteMint(quoteMint solana.PublicKey) *BuyInstruction {
+	b.quoteMint = quoteMint
+	return b
+}
+
+// SetBaseTokenProgram sets the token program owning the base mint
+func (b *BuyInstruction) SetBaseTokenProgram(baseTokenProgram solana.PublicKey) *BuyInstruction {
+	b.baseTokenProgram = baseTokenProgram
+	return b
+}
+
+// SetQuoteTokenProgram sets the token program owning the quote mint
+func (b *BuyInstruction) SetQuoteTokenProgram(quoteTokenProgram solana.PublicKey) *BuyInstruction {
+	b.quoteTokenProgram = quoteTokenProgram
+	return b
+}
+
+// SetEventAuthority sets the Anchor event authority
+func (b *BuyInstruction) SetEventAuthority(eventAuthority solana.PublicKey) *BuyInstruction {
+	b.eventAuthority = eventAuthority
+	return b
+}
+
+// SetExactOut selects buy_exact_out instead of buy_exact_in
+func (b *BuyInstruction) SetExactOut(exactOut bool) *BuyInstruction {
+	b.exactOut = exactOut
+	return b
+}
+
+// SetAmountIn sets the exact amount in for buy_exact_in
+func (b *BuyInstruction) SetAmountIn(amountIn uint64) *BuyInstruction {
+	b.amountIn = amountIn
+	return b
+}
+
+// SetMinimumAmountOut sets the minimum amount out for buy_exact_in
+func (b *BuyInstruction) SetMinimumAmountOut(minimumAmountOut uint64) *BuyInstruction {
+	b.minimumAmountOut = minimumAmountOut
+	return b
+}
+
+// SetExpectedAmountOut sets the quoted amount out used to sanity-check the minimum
+func (b *BuyInstruction) SetExpectedAmountOut(expectedAmountOut uint64) *BuyInstruction {
+	b.expectedAmountOut = expectedAmountOut
+	return b
+}
+
+// SetAmountOut sets the exact amount out for buy_exact_out
+func (b *BuyInstruction) SetAmountOut(amountOut uint64) *BuyInstruction {
+	b.amountOut = amountOut
+	return b
+}
+
+// SetMaxAmountIn sets the maximum amount in for buy_exact_out
+func (b *BuyInstruction) SetMaxAmountIn(maxAmountIn uint64) *BuyInstruction {
+	b.maxAmountIn = maxAmountIn
+	return b
+}
+
+// SetExpectedAmountIn sets the quoted amount in used to sanity-check the maximum
+func (b *BuyInstruction) SetExpectedAmountIn(expectedAmountIn uint64) *BuyInstruction {
+	b.expectedAmountIn = expectedAmountIn
+	return b
+}
+
+// SetShareFeeRate sets the referral share fee rate, out of LAUNCHPAD_FEE_RATE_DENOMINATOR
+func (b *BuyInstruction) SetShareFeeRate(shareFeeRate uint64) *BuyInstruction {
+	b.shareFeeRate = shareFeeRate
+	return b
+}
+
+// Validate reports every missing account and invalid amount in the buy
+func (b *BuyInstruction) Validate() error {
+	v := newInstructionValidator("launchpad buy")
+	accounts := b.launchpadTradeAccounts.withDerivedAccounts()
+	accounts.validate(v)
+	b.launchpadTradeAmounts.validate(v)
+	return v.err()
+}
+
+// Build creates the Solana instruction
+func (b *BuyInstruction) Build() (solana.Instruction, error) {
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+
+	accounts := b.launchpadTradeAccounts.withDerivedAccounts()
+	return solana.NewInstruction(
+		accounts.programID,
+		accounts.accountMetas(),
+		b.data(launchpadBuyExactInDiscriminator, launchpadBuyExactOutDiscriminator),
+	), nil
+}
+
+// SellInstruction represents a Raydium Launchpad sell instruction, selling the base token
+// for the quote token
+type SellInstruction struct {
+	launchpadTradeAccounts
+	launchpadTradeAmounts
+}
+
+// NewSellInstruction creates a new Launchpad sell instruction builder. It builds
+// sell_exact_in by default; SetExactOut switches to sell_exact_out.
+func NewSellInstruction() *SellInstruction {
+	return &SellInstruction{launchpadTradeAccounts: defaultLaunchpadTradeAccounts()}
+}
+
+// SetProgramID sets the program ID for the sell instruction
+func (s *SellInstruction) SetProgramID(programID solana.PublicKey) *SellInstruction {
+	s.programID = programID
+	return s
+}
+
+// SetPayer sets the signing trader
+func (s *SellInstruction) SetPayer(payer solana.PublicKey) *SellInstruction {
+	s.payer = payer
+	return s
+}
+
+// SetAuthority sets the Launchpad vault authority
+func (s *SellInstruction) SetAuthority(authority solana.PublicKey) *SellInstruction {
+	s.authority = authority
+	return s
+}
+
+// SetGlobalConfig sets the Launchpad global config
+func (s *SellInstruction) SetGlobalConfig(globalConfig solana.PublicKey) *SellInstruction {
+	s.globalConfig = globalConfig
+	return s
+}
+
+// SetPlatformConfig sets the launch platform config
+func (s *SellInstruction) SetPlatformConfig(platformConfig solana.PublicKey) *SellInstruction {
+	s.platformConfig = platformConfig
+	return s
+}
+
+// SetPoolState sets the Launchpad pool state account
+func (s *SellInstruction) SetPoolState(poolState solana.PublicKey) *SellInstruction {
+	s.poolState = poolState
+	return s
+}
+
+// SetUserBaseToken sets the user's base token account
+func (s *SellInstruction) SetUserBaseToken(userBaseToken solana.PublicKey) *SellInstruction {
+	s.userBaseToken = userBaseToken
+	return s
+}
+
+// SetUserQuoteToken sets the user's quote token account
+func (s *SellInstruction) SetUserQuoteToken(userQuoteToken solana.PublicKey) *SellInstruction {
+	s.userQuoteToken = userQuoteToken
+	return s
+}
+
+// SetBaseVault sets the pool base vault
+func (s *SellInstruction) SetBaseVault(baseVault solana.PublicKey) *SellInstruction {
+	s.baseVault = baseVault
+	return s
+}
+
+// SetQuoteVault sets the pool quote vault
+func (s *SellInstruction) SetQuoteVault(quoteVault solana.PublicKey) *SellInstruction {
+	s.quoteVault = quoteVault
+	return s
+}
+
+// SetBaseMint sets the base token mint
+func (s *SellInstruction) SetBaseMint(baseMint solana.PublicKey) *SellInstruction {
+	s.baseMint = baseMint
+	return s
+}
+
+// SetQuoteMint sets the quote token mint
+func (s *SellInstruction) SetQuoteMint(quoteMint solana.PublicKey) *SellInstruction {
+	s.quoteMint = quoteMint
+	return s
+}
+
+// SetBaseTokenProgram sets the token program owning the base mint
+func (s *SellInstruction) SetBaseTokenProgram(baseTokenProgram solana.PublicKey) *SellInstruction {
+	s.baseTokenProgram = baseTokenProgram
+	return s
+}
+
+// SetQuoteTokenProgram sets the token program owning the quote mint
+func (s *SellInstruction) SetQuoteTokenProgram(quoteTokenProgram solana.PublicKey) *SellInstruction {
+	s.quoteTokenProgram = quoteTokenProgram
+	return s
+}
+
+// SetEventAuthority sets the Anchor event authority
+func (s *SellInstruction) SetEventAuthority(eventAuthority solana.PublicKey) *SellInstruction {
+	s.eventAuthority = eventAuthority
+	return s
+}
+
+// SetExactOut selects sell_exact_out instead of sell_exact_in
+func (s *SellInstruction) SetExactOut(exactOut bool) *SellInstruction {
+	s.exactOut = exactOut
+	return s
+}
+
+// SetAmountIn sets the exact amount in for sell_exact_in
+func (s *SellInstruction) SetAmountIn(amountIn uint64) *SellInstruction {
+	s.amountIn = amountIn
+	return s
+}
+
+// SetMinimumAmountOut sets the minimum amount out for sell_exact_in
+func (s *SellInstruction) SetMinimumAmountOut(minimumAmountOut uint64) *SellInstruction {
+	s.minimumAmountOut = minimumAmountOut
+	return s
+}
+
+// SetExpectedAmountOut sets the quoted amount out used to sanity-check the minimum
+func (s *SellInstruction) SetExpectedAmountOut(expectedAmountOut uint64) *SellInstruction {
+	s.expectedAmountOut = expectedAmountOut
+	return s
+}
+
+// SetAmountOut sets the exact amount out for sell_exact_out
+func (s *SellInstruction) SetAmountOut(amountOut uint64) *SellInstruction {
+	s.amountOut = amountOut
+	return s
+}
+
+// SetMaxAmountIn sets the maximum amount in for sell_exact_out
+func (s *SellInstruction) SetMaxAmountIn(maxAmountIn uint64) *SellInstruction {
+	s.maxAmountIn = maxAmountIn
+	return s
+}
+
+// SetExpectedAmountIn sets the quoted amount in used to sanity-check the maximum
+func (s *SellInstruction) SetExpectedAmountIn(expectedAmountIn uint64) *SellInstruction {
+	s.expectedAmountIn = expectedAmountIn
+	return s
+}
+
+// SetShareFeeRate sets the referral share fee rate, out of LAUNCHPAD_FEE_RATE_DENOMINATOR
+func (s *SellInstruction) SetShareFeeRate(shareFeeRate uint64) *SellInstruction {
+	s.shareFeeRate = shareFeeRate
+	return s
+}
+
+// Validate reports every missing account and invalid amount in the sell
+func (s *SellInstruction) Validate() error {
+	v := newInstructionValidator("launchpad sell")
+	accounts := s.launchpadTradeAccounts.withDerivedAccounts()
+	accounts.validate(v)
+	s.launchpadTradeAmounts.validate(v)
+	return v.err()
+}
+
+// Build creates the Solana instruction
+func (s *SellInstruction) Build() (solana.Instruction, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	accounts := s.launchpadTradeAccounts.withDerivedAccounts()
+	return solana.NewInstruction(
+		accounts.programID,
+		accounts.accountMetas(),
+		s.data(launchpadSellExactInDiscriminator, launchpadSellExactOutDiscriminator),
+	), nil
+}
+
+// CreateTokenInstruction represents a Raydium Launchpad initialize instruction, which
+// creates the base mint, its metadata and the bonding curve pool in one step
+type CreateTokenInstruction struct {
+	programID         solana.PublicKey
+	payer             solana.PublicKey
+	creator           solana.PublicKey
+	globalConfig      solana.PublicKey
+	platformConfig    solana.PublicKey
+	authority         solana.PublicKey
+	poolState         solana.PublicKey
+	baseMint          solana.PublicKey
+	quoteMint         solana.PublicKey
+	baseVault         solana.PublicKey
+	quoteVault        solana.PublicKey
+	metadata          solana.PublicKey
+	baseTokenProgram  solana.PublicKey
+	quoteTokenProgram solana.PublicKey
+	eventAuthority    solana.PublicKey
+	params            LaunchpadInitializeParams
+}
+
+// NewCreateTokenInstruction creates a new Launchpad initialize instruction builder
+func NewCreateTokenInstruction() *CreateTokenInstruction {
+	return &CreateTokenInstruction{
+		programID:         RaydiumLaunchpadV1ProgramID,
+		globalConfig:      LaunchpadGlobalConfigID,
+		quoteMint:         solana.SolMint,
+		baseTokenProgram:  TokenProgramID,
+		quoteTokenProgram: TokenProgramID,
+		params: LaunchpadInitializeParams{
+			Decimals:  6, // Launchpad tokens use 6 decimals
+			CurveType: LAUNCHPAD_CURVE_CONSTANT_PRODUCT,
+		},
+	}
+}
+
+// SetProgramID sets the program ID for the create token instruction
+func (c *CreateTokenInstruction) SetProgramID(programID solana.PublicKey) *CreateTokenInstruction {
+	c.programID = programID
+	return c
+}
+
+// SetPayer sets the signing account paying for the new accounts
+func (c *CreateTokenInstruction) SetPayer(payer solana.PublicKey) *CreateTokenInstruction {
+	c.payer = payer
+	return c
+}
+
+// SetCreator sets the signing token creator
+func (c *CreateTokenInstruction) SetCreator(creator solana.PublicKey) *CreateTokenInstruction {
+	c.creator = creator
+	return c
+}
+
+// SetGlobalConfig sets the Launchpad global config
+func (c *CreateTokenInstruction) SetGlobalConfig(globalConfig solana.PublicKey) *CreateTokenInstruction {
+	c.globalConfig = globalConfig
+	return c
+}
+
+// SetPlatformConfig sets the launch platform config
+func (c *CreateTokenInstruction) SetPlatformConfig(platformConfig solana.PublicKey) *CreateTokenInstruction {
+	c.platformConfig = platformConfig
+	return c
+}
+
+// SetAuthority sets the Launchpad vault authority, which becomes the mint authority
+func (c *CreateTokenInstruction) SetAuthority(authority solana.PublicKey) *CreateTokenInstruction {
+	c.authority = authority
+	return c
+}
+
+// SetPoolState sets the pool state account to create
+func (c *CreateTokenInstruction) SetPoolState(poolState solana.PublicKey) *CreateTokenInstruction {
+	c.poolState = poolState
+	return c
+}
+
+// SetBaseMint sets the new token's mint, which must sign the transaction
+func (c *CreateTokenInstruction) SetBaseMint(baseMint solana.PublicKey) *CreateTokenInstruction {
+	c.baseMint = baseMint
+	return c
+}
+
+// SetQuoteMint sets the quote token mint
+func (c *CreateTokenInstruction) SetQuoteMint(quoteMint solana.PublicKey) *CreateTokenInstruction {
+	c.quoteMint = quoteMint
+	return c
+}
+
+// SetBaseVault sets the pool base vault to create
+func (c *CreateTokenInstruction) SetBaseVault(baseVault solana.PublicKey) *CreateTokenInstruction {
+	c.baseVault = baseVault
+	return c
+}
+
+// SetQuoteVault sets the pool quote vault to create
+func (c *CreateTokenInstruction) SetQuoteVault(quoteVault solana.PublicKey) *CreateTokenInstruction {
+	c.quoteVault = quoteVault
+	return c
+}
+
+// SetMetadata sets the Metaplex metadata account to create
+func (c *CreateTokenInstruction) SetMetadata(metadata solana.PublicKey) *CreateTokenInstruction {
+	c.metadata = metadata
+	return c
+}
+
+// SetBaseTokenProgram sets the token program owning the base mint
+func (c *CreateTokenInstruction) SetBaseTokenProgram(baseTokenProgram solana.PublicKey) *CreateTokenInstruction {
+	c.baseTokenProgram = baseTokenProgram
+	return c
+}
+
+// SetQuoteTokenProgram sets the token program owning the quote mint
+func (c *CreateTokenInstruction) SetQuoteTokenProgram(quoteTokenProgram solana.PublicKey) *CreateTokenInstruction {
+	c.quoteTokenProgram = quoteTokenProgram
+	return c
+}
+
+// SetEventAuthority sets the Anchor event authority
+func (c *CreateTokenInstruction) SetEventAuthority(eventAuthority solana.PublicKey) *CreateTokenInstruction {
+	c.eventAuthority = eventAuthority
+	return c
+}
+
+// SetDecimals sets the token decimals
+func (c *CreateTokenInstruction) SetDecimals(decimals uint8) *CreateTokenInstruction {
+	c.params.Decimals = decimals
+	return c
+}
+
+// SetName sets the token name
+func (c *CreateTokenInstruction) SetName(name string) *CreateTokenInstruction {
+	c.params.Name = name
+	return c
+}
+
+// SetSymbol sets the token symbol
+func (c *CreateTokenInstruction) SetSymbol(symbol string) *CreateTokenInstruction {
+	c.params.Symbol = symbol
+	return c
+}
+
+// SetURI sets the token metadata URI
+func (c *CreateTokenInstruction) SetURI(uri string) *CreateTokenInstruction {
+	c.params.URI = uri
+	return c
+}
+
+// SetCurveType sets the bonding curve type (LAUNCHPAD_CURVE_*)
+func (c *CreateTokenInstruction) SetCurveType(curveType uint8) *CreateTokenInstruction {
+	c.params.CurveType = curveType
+	return c
+}
+
+// SetSupply sets the total base token supply
+func (c *CreateTokenInstruction) SetSupply(supply uint64) *CreateTokenInstruction {
+	c.params.Supply = supply
+	return c
+}
+
+// SetTotalBaseSell sets the base tokens sold on a constant-product curve. Fixed and linear
+// curves do not take it.
+func (c *CreateTokenInstruction) SetTotalBaseSell(totalBaseSell uint64) *CreateTokenInstruction {
+	c.params.TotalBaseSell = totalBaseSell
+	return c
+}
+
+// SetTotalQuoteFundRaising sets the quote amount that completes the curve
+func (c *CreateTokenInstruction) SetTotalQuoteFundRaising(totalQuoteFundRaising uint64) *CreateTokenInstruction {
+	c.params.TotalQuoteFundRaising = totalQuoteFundRaising
+	return c
+}
+
+// SetMigrateType sets where the pool migrates once funded (LAUNCHPAD_MIGRATE_TYPE_*)
+func (c *CreateTokenInstruction) SetMigrateType(migrateType uint8) *CreateTokenInstruction {
+	c.params.MigrateType = migrateType
+	return c
+}
+
+// SetVesting sets the creator's locked allocation and its cliff and unlock periods in seconds
+func (c *CreateTokenInstruction) SetVesting(totalLockedAmount, cliffPeriod, unlockPeriod uint64) *CreateTokenInstruction {
+	c.params.TotalLockedAmount = totalLockedAmount
+	c.params.CliffPeriod = cliffPeriod
+	c.params.UnlockPeriod = unlockPeriod
+	return c
+}
+
+// withDerivedAccounts returns a copy of the instruction with the authority, event
+// authority, pool, vaults and metadata account derived where unset
+func (c *CreateTokenInstruction) withDerivedAccounts() *CreateTokenInstruction {
+	d := *c
+	deriveIfZero(&d.authority, func() (solana.PublicKey, error) { return DeriveLaunchpadAuthority(d.programID) }, d.programID)
+	deriveIfZero(&d.eventAuthority, func() (solana.PublicKey, error) { return DeriveEventAuthority(d.programID) }, d.programID)
+	deriveIfZero(&d.poolState, func() (solana.PublicKey, error) {
+		return DeriveLaunchpadPoolState(d.programID, d.baseMint, d.quoteMint)
+	}, d.programID, d.baseMint, d.quoteMint)
+	deriveIfZero(&d.baseVault, func() (solana.PublicKey, error) {
+		return DeriveLaunchpadVault(d.programID, d.poolState, d.baseMint)
+	}, d.programID, d.poolState, d.baseMint)
+	deriveIfZero(&d.quoteVault, func() (solana.PublicKey, error) {
+		return DeriveLaunchpadVault(d.programID, d.poolState, d.quoteMint)
+	}, d.programID, d.poolState, d.quoteMint)
+	deriveIfZero(&d.metadata, func() (solana.PublicKey, error) { return DeriveMetadataAddress(d.baseMint) }, d.baseMint)
+	return &d
+}
+
+// Validate reports every missing account and invalid parameter in the token creation
+func (c *CreateTokenInstruction) Validate() error {
+	c = c.withDerivedAccounts()
+	v := newInstructionValidator("create token")
+	v.account("program ID", c.programID)
+	v.account("payer", c.payer)
+	v.account("creator", c.creator)
+	v.account("global config", c.globalConfig)
+	v.account("platform config", c.platformConfig)
+	v.account("authority", c.authority)
+	v.account("pool state", c.poolState)
+	v.account("base mint", c.baseMint)
+	v.account("quote mint", c.quoteMint)
+	v.account("base vault", c.baseVault)
+	v.account("quote vault", c.quoteVault)
+	v.account("metadata", c.metadata)
+	v.account("base token program", c.baseTokenProgram)
+	v.account("quote token program", c.quoteTokenProgram)
+	v.account("event authority", c.eventAuthority)
+
+	p := c.params
+	v.check(p.Name != "", "missing name")
+	v.check(p.Symbol != "", "missing symbol")
+	v.maxLength("name", p.Name, METADATA_MAX_NAME_LENGTH)
+	v.maxLength("symbol", p.Symbol, METADATA_MAX_SYMBOL_LENGTH)
+	v.maxLength("uri", p.URI, METADATA_MAX_URI_LENGTH)
+	v.check(p.CurveType <= LAUNCHPAD_CURVE_LINEAR_PRICE, "unknown curve type %d", p.CurveType)
+	v.check(p.MigrateType <= LAUNCHPAD_MIGRATE_TYPE_CPSWAP, "unknown migrate type %d", p.MigrateType)
+	v.amount("supply", p.Supply)
+	v.amount("total quote fund raising", p.TotalQuoteFundRaising)
+	if p.CurveType == LAUNCHPAD_CURVE_CONSTANT_PRODUCT {
+		v.amount("total base sell", p.TotalBaseSell)
+		v.check(p.TotalBaseSell <= p.Supply, "total base sell %d exceeds supply %d", p.TotalBaseSell, p.Supply)
+		v.check(p.TotalBaseSell > p.Supply || p.TotalLockedAmount <= p.Supply-p.TotalBaseSell,
+			"total locked amount %d exceeds unsold supply", p.TotalLockedAmount)
+	} else {
+		v.check(p.TotalBaseSell == 0, "total base sell is only sent for constant-product curves")
+		v.check(p.TotalLockedAmount <= p.Supply, "total locked amount %d exceeds supply %d", p.TotalLockedAmount, p.Supply)
+	}
+	return v.err()
+}
+
+// Build creates the Solana instruction
+func (c *CreateTokenInstruction) Build() (solana.Instruction, error) {
+	c = c.withDerivedAccounts()
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	accounts := make(solana.AccountMetaSlice, initializeAccountsLength)
+	accounts[initializePayer] = &solana.AccountMeta{PublicKey: c.payer, IsWritable: true, IsSigner: true}
+	accounts[initializeCreator] = &solana.AccountMeta{PublicKey: c.creator, IsWritable: false, IsSigner: true}
+	accounts[initializeGlobalConfig] = &solana.AccountMeta{PublicKey: c.globalConfig, IsWritable: false, IsSigner: false}
+	accounts[initializePlatformConfig] = &solana.AccountMeta{PublicKey: c.platformConfig, IsWritable: false, IsSigner: false}
+	accounts[initializeAuthority] = &solana.AccountMeta{PublicKey: c.authority, IsWritable: false, IsSigner: false}
+	accounts[initializePoolState] = &solana.AccountMeta{PublicKey: c.poolState, IsWritable: true, IsSigner: false}
+	accounts[initializeBaseMint] = &solana.AccountMeta{PublicKey: c.baseMint, IsWritable: true, IsSigner: true}
+	accounts[initializeQuoteMint] = &solana.AccountMeta{PublicKey: c.quoteMint, IsWritable: false, IsSigner: false}
+	accounts[initializeBaseVault] = &solana.AccountMeta{PublicKey: c.baseVault, IsWritable: true, IsSigner: false}
+	accounts[initializeQuoteVault] = &solana.AccountMeta{PublicKey: c.quoteVault, IsWritable: true, IsSigner: false}
+	accounts[initializeMetadata] = &solana.AccountMeta{PublicKey: c.metadata, IsWritable: true, IsSigner: false}
+	accounts[initializeBaseTokenProgram] = &solana.AccountMeta{PublicKey: c.baseTokenProgram, IsWritable: false, IsSigner: false}
+	accounts[initializeQuoteTokenProgram] = &solana.AccountMeta{PublicKey: c.quoteTokenProgram, IsWritable: false, IsSigner: false}
+	accounts[initializeMetadataProgram] = &solana.AccountMeta{PublicKey: MetaplexTokenMetadataProgramID, IsWritable: false, IsSigner: false}
+	accounts[initializeSystemProgram] = &solana.AccountMeta{PublicKey: SystemProgramID, IsWritable: false, IsSigner: false}
+	accounts[initializeRentSysvar] = &solana.AccountMeta{PublicKey: solana.SysVarRentPubkey, IsWritable: false, IsSigner: false}
+	accounts[initializeEventAuthority] = &solana.AccountMeta{PublicKey: c.eventAuthority, IsWritable: false, IsSigner: false}
+	accounts[initializeProgram] = &solana.AccountMeta{PublicKey: c.programID, IsWritable: false, IsSigner: false}
+	return solana.NewInstruction(
+		c.programID,
+		accounts,
+		c.params.Encode(),
+	), nil
+}
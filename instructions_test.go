@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"encoding/base64"
+	"encoding/binary"
+	"errors"
 	"os"
 	"strings"
 	"testing"
@@ -29,7 +31,7 @@ func TestSwapInstructionBuilder(t *testing.T) {
 		SetSerumBids(solana.MustPublicKeyFromBase58("LanMV9sAd7wArD4vJFi2qDdfnVhFxYSUg6eADduJ3uj")).
 		SetSerumAsks(solana.MustPublicKeyFromBase58("FoaFt2Dtz58RA6DPjbRb9t9z8sLJRChiGFTv21EfaseZ")).
 		SetSerumEventQueue(solana.MustPublicKeyFromBase58("ATokenGPvbdGVxr1b2hvZbsiqW5xWH25efTNsLJA8knL")).
-		SetSerumCoinVault(solana.MustPublicKeyFromBase58("11111111111111111111111111111111")).
+		SetSerumCoinVault(solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")).
 		SetSerumPcVault(solana.MustPublicKeyFromBase58("HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH")).
 		SetSerumVaultSigner(solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112")).
 		SetAmountIn(1000000).
@@ -60,26 +62,99 @@ func TestSwapInstructionBuilder(t *testing.T) {
 	}
 
 	// Verify discriminator
-	if data[0] != INSTRUCTION_SWAP {
-		t.Errorf("Expected discriminator %d, got %d", INSTRUCTION_SWAP, data[0])
+	if data[0] != AMM_V4_INSTRUCTION_SWAP_BASE_IN {
+		t.Errorf("Expected discriminator %d, got %d", AMM_V4_INSTRUCTION_SWAP_BASE_IN, data[0])
+	}
+	if !accounts[0].PublicKey.Equals(TokenProgramID) || !accounts[17].IsSigner {
+		t.Errorf("Expected token program first and the owner as the last, signing account")
 	}
 
 	t.Logf("✓ Swap instruction built successfully with %d accounts and %d bytes of data", len(accounts), len(data))
 }
 
+func TestSwapInstructionLayouts(t *testing.T) {
+	keys := uniqueAccounts(16)
+	owner := solana.MustPublicKeyFromBase58("HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH")
+	newSwap := func(swap *SwapInstruction) *SwapInstruction {
+		return swap.
+			SetUserSourceToken(keys[0]).SetUserDestToken(keys[1]).SetUserOwner(owner).
+			SetAmmID(keys[2]).SetAmmAuthority(AmmV4AuthorityID).
+			SetPoolCoinToken(keys[3]).SetPoolPcToken(keys[4])
+	}
+
+	tests := []struct {
+		name            string
+		swap            *SwapInstruction
+		withoutOpenBook bool
+		tag             byte
+		first, second   uint64
+	}{
+		{"base in", NewSwapBaseInInstruction(1_000, 900), false, AMM_V4_INSTRUCTION_SWAP_BASE_IN, 1_000, 900},
+		{"base out", NewSwapBaseOutInstruction(1_100, 1_000), false, AMM_V4_INSTRUCTION_SWAP_BASE_OUT, 1_100, 1_000},
+		{"base in v2", NewSwapBaseInInstruction(1_000, 900), true, AMM_V4_INSTRUCTION_SWAP_BASE_IN_V2, 1_000, 900},
+		{"base out v2", NewSwapBaseOutInstruction(1_100, 1_000), true, AMM_V4_INSTRUCTION_SWAP_BASE_OUT_V2, 1_100, 1_000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			swap := newSwap(tt.swap).SetWithoutOpenBook(tt.withoutOpenBook)
+			if !tt.withoutOpenBook {
+				if err := swap.Validate(); err == nil || !strings.Contains(err.Error(), "missing serum market") {
+					t.Fatalf("Expected missing OpenBook accounts, got %v", err)
+				}
+				swap.SetAmmOpenOrders(keys[5]).SetAmmTargetOrders(keys[6]).
+					SetSerumProgram(OpenBookProgramID).SetSerumMarket(keys[7]).
+					SetSerumBids(keys[8]).SetSerumAsks(keys[9]).SetSerumEventQueue(keys[10]).
+					SetSerumCoinVault(keys[11]).SetSerumPcVault(keys[12]).SetSerumVaultSigner(keys[13])
+			}
+
+			instruction, err := swap.Build()
+			if err != nil {
+				t.Fatalf("Failed to build swap: %v", err)
+			}
+			data, _ := instruction.Data()
+			if data[0] != tt.tag {
+				t.Errorf("Expected tag %d, got %d", tt.tag, data[0])
+			}
+			if binary.LittleEndian.Uint64(data[1:9]) != tt.first || binary.LittleEndian.Uint64(data[9:17]) != tt.second {
+				t.Errorf("Unexpected amounts in data %x", data)
+			}
+
+			accounts := instruction.Accounts()
+			wantAccounts := AMM_V4_SWAP_ACCOUNTS_LENGTH
+			if tt.withoutOpenBook {
+				wantAccounts = AMM_V4_SWAP_WITHOUT_OPENBOOK_ACCOUNTS_LENGTH
+			}
+			if len(accounts) != wantAccounts {
+				t.Fatalf("Expected %d accounts, got %d", wantAccounts, len(accounts))
+			}
+			last := accounts[len(accounts)-3:]
+			if !last[0].PublicKey.Equals(keys[0]) || !last[1].PublicKey.Equals(keys[1]) || !last[2].PublicKey.Equals(owner) {
+				t.Errorf("Expected user source, destination and owner last")
+			}
+		})
+	}
+
+	// Exact-out swaps check the maximum input against the quote
+	err := newSwap(NewSwapBaseOutInstruction(900, 1_000)).SetWithoutOpenBook(true).SetExpectedAmountIn(950).Validate()
+	if err == nil || !strings.Contains(err.Error(), "below expected amount in") {
+		t.Errorf("Expected max < expected error, got %v", err)
+	}
+}
+
 func TestBuyInstructionBuilder(t *testing.T) {
-	// Create a buy instruction
+	// Create a buy_exact_in instruction
 	buyInst := NewBuyInstruction().
-		SetUserAuthority(solana.MustPublicKeyFromBase58("HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH")).
-		SetUserTokenAccount(solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112")).
-		SetUserSolAccount(solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")).
-		SetAmmID(solana.MustPublicKeyFromBase58("675kPX9MHTjS2zt1qfr1NYHuzeLXfQM9H24wFSUt1Mp8")).
-		SetAmmAuthority(solana.MustPublicKeyFromBase58("5quBtoiQqxF9Jv6KYKctB59NT3gtJD2Y65kdnB1Uev3h")).
-		SetTokenVault(solana.MustPublicKeyFromBase58("EhhTKczWMGQt46ynNeRX1WfeagwwJd7ufHvCDjRxjo5Q")).
-		SetSolVault(solana.MustPublicKeyFromBase58("27haf8L6oxUeXrHrgEgsexjSY5hbVUWEmvv9Nyxg8vQv")).
-		SetTokenMint(solana.MustPublicKeyFromBase58("6EF8rrecthR5Dkzon8Nwu78hRvfCKubJ14M5uBEwF6P")).
-		SetAmount(1000000).
-		SetMaxSolCost(500000)
+		SetPayer(solana.MustPublicKeyFromBase58("HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH")).
+		SetPlatformConfig(LetsBonkPlatformConfigID).
+		SetPoolState(solana.MustPublicKeyFromBase58("EhhTKczWMGQt46ynNeRX1WfeagwwJd7ufHvCDjRxjo5Q")).
+		SetUserBaseToken(solana.MustPublicKeyFromBase58("27haf8L6oxUeXrHrgEgsexjSY5hbVUWEmvv9Nyxg8vQv")).
+		SetUserQuoteToken(solana.MustPublicKeyFromBase58("5quBtoiQqxF9Jv6KYKctB59NT3gtJD2Y65kdnB1Uev3h")).
+		SetBaseVault(solana.MustPublicKeyFromBase58("675kPX9MHTjS2zt1qfr1NYHuzeLXfQM9H24wFSUt1Mp8")).
+		SetQuoteVault(solana.MustPublicKeyFromBase58("CPMMoo8L3F4NbTegBCKVNunggL7H1ZpdTHKxQB5qKP1C")).
+		SetBaseMint(solana.MustPublicKeyFromBase58("6EF8rrecthR5Dkzon8Nwu78hRvfCKubJ14M5uBEwF6P")).
+		SetAmountIn(500000).
+		SetMinimumAmountOut(1000000).
+		SetShareFeeRate(1000)
 
 	// Build the instruction
 	instruction, err := buyInst.Build()
@@ -93,39 +168,57 @@ func TestBuyInstructionBuilder(t *testing.T) {
 	}
 
 	accounts := instruction.Accounts()
-	if len(accounts) != 10 {
-		t.Errorf("Expected 10 accounts, got %d", len(accounts))
+	if len(accounts) != LAUNCHPAD_TRADE_ACCOUNTS_LENGTH {
+		t.Fatalf("Expected %d accounts, got %d", LAUNCHPAD_TRADE_ACCOUNTS_LENGTH, len(accounts))
+	}
+	if !accounts[0].IsSigner || !accounts[1].PublicKey.Equals(LaunchpadAuthorityID) || !accounts[2].PublicKey.Equals(LaunchpadGlobalConfigID) {
+		t.Errorf("Expected signing payer, authority and global config first")
+	}
+	if !accounts[10].PublicKey.Equals(solana.SolMint) || !accounts[13].PublicKey.Equals(LaunchpadEventAuthorityID) ||
+		!accounts[14].PublicKey.Equals(RaydiumLaunchpadV1ProgramID) {
+		t.Errorf("Expected WSOL quote mint, event authority and program accounts")
 	}
 
 	data, err := instruction.Data()
 	if err != nil {
 		t.Fatalf("Failed to get instruction data: %v", err)
 	}
-	if len(data) != 17 {
-		t.Errorf("Expected 17 bytes of data, got %d", len(data))
+	if len(data) != 32 {
+		t.Errorf("Expected 32 bytes of data, got %d", len(data))
 	}
 
-	// Verify discriminator
-	if data[0] != INSTRUCTION_BUY {
-		t.Errorf("Expected discriminator %d, got %d", INSTRUCTION_BUY, data[0])
+	// Verify discriminator and arguments
+	if [8]byte(data[:8]) != launchpadBuyExactInDiscriminator {
+		t.Errorf("Expected buy_exact_in discriminator, got %x", data[:8])
+	}
+	if binary.LittleEndian.Uint64(data[8:16]) != 500000 || binary.LittleEndian.Uint64(data[16:24]) != 1000000 ||
+		binary.LittleEndian.Uint64(data[24:32]) != 1000 {
+		t.Errorf("Unexpected arguments %x", data[8:])
+	}
+
+	// Switch to buy_exact_out
+	instruction, err = buyInst.SetExactOut(true).SetAmountOut(1000000).SetMaxAmountIn(550000).Build()
+	if err != nil {
+		t.Fatalf("Failed to build buy_exact_out: %v", err)
+	}
+	data, _ = instruction.Data()
+	if [8]byte(data[:8]) != launchpadBuyExactOutDiscriminator || binary.LittleEndian.Uint64(data[16:24]) != 550000 {
+		t.Errorf("Unexpected buy_exact_out data %x", data)
 	}
 
 	t.Logf("✓ Buy instruction built successfully with %d accounts and %d bytes of data", len(accounts), len(data))
 }
 
 func TestSellInstructionBuilder(t *testing.T) {
-	// Create a sell instruction
+	keys := uniqueAccounts(7)
 	sellInst := NewSellInstruction().
-		SetUserAuthority(solana.MustPublicKeyFromBase58("HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH")).
-		SetUserTokenAccount(solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112")).
-		SetUserSolAccount(solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")).
-		SetAmmID(solana.MustPublicKeyFromBase58("675kPX9MHTjS2zt1qfr1NYHuzeLXfQM9H24wFSUt1Mp8")).
-		SetAmmAuthority(solana.MustPublicKeyFromBase58("5quBtoiQqxF9Jv6KYKctB59NT3gtJD2Y65kdnB1Uev3h")).
-		SetTokenVault(solana.MustPublicKeyFromBase58("EhhTKczWMGQt46ynNeRX1WfeagwwJd7ufHvCDjRxjo5Q")).
-		SetSolVault(solana.MustPublicKeyFromBase58("27haf8L6oxUeXrHrgEgsexjSY5hbVUWEmvv9Nyxg8vQv")).
-		SetTokenMint(solana.MustPublicKeyFromBase58("6EF8rrecthR5Dkzon8Nwu78hRvfCKubJ14M5uBEwF6P")).
-		SetAmount(1000000).
-		SetMinSolReceived(400000)
+		SetPayer(solana.MustPublicKeyFromBase58("HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH")).
+		SetPlatformConfig(LetsBonkPlatformConfigID).
+		SetPoolState(keys[0]).SetUserBaseToken(keys[1]).SetUserQuoteToken(keys[2]).
+		SetBaseVault(keys[3]).SetQuoteVault(keys[4]).SetBaseMint(keys[5]).
+		SetExactOut(true).
+		SetAmountOut(400000).
+		SetMaxAmountIn(1000000)
 
 	// Build the instruction
 	instruction, err := sellInst.Build()
@@ -133,44 +226,50 @@ func TestSellInstructionBuilder(t *testing.T) {
 		t.Fatalf("Failed to build sell instruction: %v", err)
 	}
 
-	// Verify the instruction
-	if instruction.ProgramID() != RaydiumLaunchpadV1ProgramID {
-		t.Errorf("Expected program ID %s, got %s", RaydiumLaunchpadV1ProgramID, instruction.ProgramID())
-	}
-
 	accounts := instruction.Accounts()
-	if len(accounts) != 10 {
-		t.Errorf("Expected 10 accounts, got %d", len(accounts))
+	if len(accounts) != LAUNCHPAD_TRADE_ACCOUNTS_LENGTH {
+		t.Errorf("Expected %d accounts, got %d", LAUNCHPAD_TRADE_ACCOUNTS_LENGTH, len(accounts))
 	}
 
 	data, err := instruction.Data()
 	if err != nil {
 		t.Fatalf("Failed to get instruction data: %v", err)
 	}
-	if len(data) != 17 {
-		t.Errorf("Expected 17 bytes of data, got %d", len(data))
+	if [8]byte(data[:8]) != launchpadSellExactOutDiscriminator {
+		t.Errorf("Expected sell_exact_out discriminator, got %x", data[:8])
+	}
+	if binary.LittleEndian.Uint64(data[8:16]) != 400000 || binary.LittleEndian.Uint64(data[16:24]) != 1000000 {
+		t.Errorf("Unexpected arguments %x", data[8:])
 	}
 
-	// Verify discriminator
-	if data[0] != INSTRUCTION_SELL {
-		t.Errorf("Expected discriminator %d, got %d", INSTRUCTION_SELL, data[0])
+	instruction, err = sellInst.SetExactOut(false).SetAmountIn(1000000).SetMinimumAmountOut(400000).Build()
+	if err != nil {
+		t.Fatalf("Failed to build sell_exact_in: %v", err)
+	}
+	data, _ = instruction.Data()
+	if [8]byte(data[:8]) != launchpadSellExactInDiscriminator {
+		t.Errorf("Expected sell_exact_in discriminator, got %x", data[:8])
 	}
 
 	t.Logf("✓ Sell instruction built successfully with %d accounts and %d bytes of data", len(accounts), len(data))
 }
 
 func TestCreateTokenInstructionBuilder(t *testing.T) {
-	// Create a token creation instruction
+	keys := uniqueAccounts(6)
+	creator := solana.MustPublicKeyFromBase58("HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH")
 	createInst := NewCreateTokenInstruction().
-		SetPayer(solana.MustPublicKeyFromBase58("HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH")).
-		SetMint(solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112")).
-		SetMintAuthority(solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")).
-		SetFreezeAuthority(solana.MustPublicKeyFromBase58("675kPX9MHTjS2zt1qfr1NYHuzeLXfQM9H24wFSUt1Mp8")).
-		SetDecimals(6).
-		SetName("Test Token").
-		SetSymbol("TEST").
-		SetURI("https://example.com/token.json").
-		SetInitialSupply(1000000000000)
+		SetPayer(creator).
+		SetCreator(creator).
+		SetPlatformConfig(LetsBonkPlatformConfigID).
+		SetPoolState(keys[0]).SetBaseMint(keys[1]).SetBaseVault(keys[2]).SetQuoteVault(keys[3]).SetMetadata(keys[4]).
+		SetName(sampleInitializeParams.Name).
+		SetSymbol(sampleInitializeParams.Symbol).
+		SetURI(sampleInitializeParams.URI).
+		SetSupply(sampleInitializeParams.Supply).
+		SetTotalBaseSell(sampleInitializeParams.TotalBaseSell).
+		SetTotalQuoteFundRaising(sampleInitializeParams.TotalQuoteFundRaising).
+		SetMigrateType(sampleInitializeParams.MigrateType).
+		SetVesting(sampleInitializeParams.TotalLockedAmount, sampleInitializeParams.CliffPeriod, sampleInitializeParams.UnlockPeriod)
 
 	// Build the instruction
 	instruction, err := createInst.Build()
@@ -184,62 +283,92 @@ func TestCreateTokenInstructionBuilder(t *testing.T) {
 	}
 
 	accounts := instruction.Accounts()
-	if len(accounts) != 6 {
-		t.Errorf("Expected 6 accounts, got %d", len(accounts))
+	if len(accounts) != initializeAccountsLength {
+		t.Fatalf("Expected %d accounts, got %d", initializeAccountsLength, len(accounts))
+	}
+	if !accounts[initializeBaseMint].IsSigner || !accounts[initializeAuthority].PublicKey.Equals(LaunchpadAuthorityID) {
+		t.Errorf("Expected signing base mint and Launchpad authority")
 	}
 
+	// The parser decodes what the builder encodes
 	data, err := instruction.Data()
 	if err != nil {
 		t.Fatalf("Failed to get instruction data: %v", err)
 	}
-
-	// Verify discriminator
-	if data[0] != INSTRUCTION_CREATE_POOL {
-		t.Errorf("Expected discriminator %d, got %d", INSTRUCTION_CREATE_POOL, data[0])
-	}
-
-	t.Logf("✓ Create token instruction built successfully with %d accounts and %d bytes of data", len(accounts), len(data))
-}
-
-func TestMigrateInstructionBuilder(t *testing.T) {
-	// Create a migrate instruction
-	migrateInst := NewMigrateInstruction().
-		SetUserAuthority(solana.MustPublicKeyFromBase58("HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH")).
-		SetFromPool(solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112")).
-		SetToPool(solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")).
-		SetTokenAccount(solana.MustPublicKeyFromBase58("675kPX9MHTjS2zt1qfr1NYHuzeLXfQM9H24wFSUt1Mp8")).
-		SetAmount(1000000)
-
-	// Build the instruction
-	instruction, err := migrateInst.Build()
+	params, err := DecodeLaunchpadInitializeParams(data)
 	if err != nil {
-		t.Fatalf("Failed to build migrate instruction: %v", err)
+		t.Fatalf("Failed to decode built instruction: %v", err)
 	}
-
-	// Verify the instruction
-	if instruction.ProgramID() != RaydiumV4ProgramID {
-		t.Errorf("Expected program ID %s, got %s", RaydiumV4ProgramID, instruction.ProgramID())
+	if *params != sampleInitializeParams {
+		t.Errorf("Decoded params %+v do not match %+v", *params, sampleInitializeParams)
 	}
 
-	accounts := instruction.Accounts()
-	if len(accounts) != 5 {
-		t.Errorf("Expected 5 accounts, got %d", len(accounts))
-	}
-
-	data, err := instruction.Data()
-	if err != nil {
-		t.Fatalf("Failed to get instruction data: %v", err)
-	}
-	if len(data) != 9 {
-		t.Errorf("Expected 9 bytes of data, got %d", len(data))
-	}
+	t.Logf("✓ Create token instruction built successfully with %d accounts and %d bytes of data", len(accounts), len(data))
+}
 
-	// Verify discriminator
-	if data[0] != INSTRUCTION_MIGRATE {
-		t.Errorf("Expected discriminator %d, got %d", INSTRUCTION_MIGRATE, data[0])
+func TestBuilderValidation(t *testing.T) {
+	owner := solana.MustPublicKeyFromBase58("HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH")
+
+	// An empty swap reports every problem at once; the AMM authority is derived
+	_, err := NewSwapInstruction().SetUserOwner(owner).Build()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || !errors.Is(err, ErrInvalidInstruction) {
+		t.Fatalf("Expected ValidationError, got %v", err)
+	}
+	if len(validationErr.Problems) != 16 {
+		t.Errorf("Expected 16 problems (15 accounts + amount), got %d: %v", len(validationErr.Problems), validationErr.Problems)
+	}
+	if strings.Contains(err.Error(), "missing user owner") || strings.Contains(err.Error(), "missing AMM authority") ||
+		!strings.Contains(err.Error(), "missing AMM ID") {
+		t.Errorf("Unexpected error message: %v", err)
+	}
+
+	// Minimum out above the quoted output
+	keys := uniqueAccounts(16)
+	swap := NewSwapInstruction().
+		SetUserSourceToken(keys[0]).SetUserDestToken(keys[1]).SetUserOwner(owner).
+		SetAmmID(keys[2]).SetAmmAuthority(keys[3]).SetAmmOpenOrders(keys[4]).SetAmmTargetOrders(keys[5]).
+		SetPoolCoinToken(keys[6]).SetPoolPcToken(keys[7]).SetSerumProgram(keys[8]).SetSerumMarket(keys[9]).
+		SetSerumBids(keys[10]).SetSerumAsks(keys[11]).SetSerumEventQueue(keys[12]).SetSerumCoinVault(keys[13]).
+		SetSerumPcVault(keys[14]).SetSerumVaultSigner(keys[15]).
+		SetAmountIn(1_000).SetMinimumAmountOut(950).SetExpectedAmountOut(900)
+	if err := swap.Validate(); err == nil || !strings.Contains(err.Error(), "exceeds expected amount out") {
+		t.Errorf("Expected min > expected error, got %v", err)
+	}
+	if err := swap.SetExpectedAmountOut(960).Validate(); err != nil {
+		t.Errorf("Unexpected error for valid swap: %v", err)
+	}
+
+	// Buy with zero amounts and a maximum input below the quote
+	err = NewBuyInstruction().SetPayer(owner).SetExactOut(true).SetExpectedAmountIn(10).SetShareFeeRate(LAUNCHPAD_FEE_RATE_DENOMINATOR).Validate()
+	if err == nil || !strings.Contains(err.Error(), "amount out must be greater than zero") ||
+		!strings.Contains(err.Error(), "below expected amount in") || !strings.Contains(err.Error(), "share fee rate") ||
+		!strings.Contains(err.Error(), "missing platform config") {
+		t.Errorf("Unexpected buy validation error: %v", err)
+	}
+
+	// Metadata length limits
+	_, err = NewCreateTokenInstruction().
+		SetPayer(owner).SetCreator(owner).SetPlatformConfig(LetsBonkPlatformConfigID).
+		SetPoolState(keys[0]).SetBaseMint(keys[1]).SetBaseVault(keys[2]).SetQuoteVault(keys[3]).SetMetadata(keys[4]).
+		SetName(strings.Repeat("n", METADATA_MAX_NAME_LENGTH+1)).
+		SetSymbol("TOOLONGSYMBOL").
+		SetURI("https://example.com/" + strings.Repeat("u", METADATA_MAX_URI_LENGTH)).
+		SetSupply(100).SetTotalBaseSell(80).SetTotalQuoteFundRaising(1).
+		Build()
+	if !errors.As(err, &validationErr) || len(validationErr.Problems) != 3 {
+		t.Errorf("Expected name, symbol and uri length errors, got %v", err)
+	}
+
+	// Only constant-product curves send a total base sell
+	_, err = NewCreateTokenInstruction().
+		SetPayer(owner).SetCreator(owner).SetPlatformConfig(LetsBonkPlatformConfigID).SetBaseMint(keys[1]).
+		SetName("Fixed").SetSymbol("FIX").SetCurveType(LAUNCHPAD_CURVE_FIXED_PRICE).
+		SetSupply(100).SetTotalBaseSell(80).SetTotalQuoteFundRaising(1).
+		Build()
+	if err == nil || !strings.Contains(err.Error(), "only sent for constant-product curves") {
+		t.Errorf("Expected a total base sell error for a fixed curve, got %v", err)
 	}
-
-	t.Logf("✓ Migrate instruction built successfully with %d accounts and %d bytes of data", len(accounts), len(data))
 }
 
 // TestTransactionSubmission tests submitting transactions to Solana
@@ -253,7 +382,7 @@ func TestTransactionSubmission(t *testing.T) {
 		t.Skip("Skipping transaction submission test - missing environment variables SOLANA_WALLET_PATH and SOLANA_RPC_ENDPOINT")
 	}
 
-	wallet, err := solana.PrivateKeyFromSolanaKeygenFile(walletPath)
+	wallet, err := LoadKeypairFile(walletPath)
 	if err != nil {
 		t.Fatalf("Failed to load wallet: %v", err)
 	}
@@ -268,45 +397,35 @@ func TestTransactionSubmission(t *testing.T) {
 		t.Fatalf("Failed to get recent blockhash: %v", err)
 	}
 
-	// Create a simple transfer instruction as a test
+	// Compose a self-transfer of 1000 lamports behind compute budget instructions
 	// In a real scenario, this would be a buy/sell instruction
-	transferInst := solana.NewInstruction(
-		solana.SystemProgramID,
-		solana.AccountMetaSlice{
-			{PublicKey: wallet.PublicKey(), IsWritable: true, IsSigner: true},
-			{PublicKey: wallet.PublicKey(), IsWritable: true, IsSigner: false}, // sending to self for test
-		},
-		[]byte{2, 0, 0, 0, 232, 3, 0, 0, 0, 0, 0, 0}, // transfer 1000 lamports
-	)
-
-	// Create transaction
-	tx, err := solana.NewTransaction(
-		[]solana.Instruction{transferInst},
-		recent.Value.Blockhash,
-		solana.TransactionPayer(wallet.PublicKey()),
-	)
+	tx, err := NewTransactionComposer(wallet.PublicKey()).
+		SetRecentBlockhash(recent.Value.Blockhash).
+		SetComputeUnitLimit(10_000).
+		SetComputeUnitPrice(1).
+		AddInstruction(NewTransferInstruction(wallet.PublicKey(), wallet.PublicKey(), 1000)).
+		Build()
 	if err != nil {
 		t.Fatalf("Failed to create transaction: %v", err)
 	}
 
 	// Sign transaction
-	if _, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
-		if key.Equals(wallet.PublicKey()) {
-			return &wallet
-		}
-		return nil
-	}); err != nil {
+	if err := SignTransaction(tx, wallet); err != nil {
 		t.Fatalf("Failed to sign transaction: %v", err)
 	}
 
-	// Simulate the transaction (don't actually send)
-	simResult, err := client.SimulateTransaction(ctx, tx)
+	// Simulate and explain the transaction (don't actually send)
+	preview, err := PreviewTransaction(ctx, client, tx)
 	if err != nil {
 		t.Fatalf("Failed to simulate transaction: %v", err)
 	}
+	t.Log(preview.Explain())
 
-	if simResult.Value.Err != nil {
-		t.Fatalf("Transaction simulation failed: %v", simResult.Value.Err)
+	if !preview.Succeeded() {
+		t.Fatalf("Transaction simulation failed: %s", preview.Error)
+	}
+	if lamports := preview.Accounts[0].LamportsDelta(); lamports != -int64(preview.Fee.Total()) {
+		t.Errorf("Expected the self-transfer to cost only the %d lamport fee, got %d", preview.Fee.Total(), lamports)
 	}
 
 	t.Log("✓ Transaction simulation successful")
@@ -386,22 +505,22 @@ func TestBuilderChaining(t *testing.T) {
 
 	// Test buy instruction chaining
 	buyInst := NewBuyInstruction().
-		SetAmount(2000).
-		SetMaxSolCost(1000).
-		SetUserAuthority(solana.MustPublicKeyFromBase58("HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH"))
+		SetAmountIn(2000).
+		SetMinimumAmountOut(1000).
+		SetPayer(solana.MustPublicKeyFromBase58("HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH"))
 
-	if buyInst.amount != 2000 {
-		t.Errorf("Expected amount 2000, got %d", buyInst.amount)
+	if buyInst.amountIn != 2000 {
+		t.Errorf("Expected amount in 2000, got %d", buyInst.amountIn)
 	}
 
 	// Test sell instruction chaining
 	sellInst := NewSellInstruction().
-		SetAmount(3000).
-		SetMinSolReceived(1500).
-		SetUserAuthority(solana.MustPublicKeyFromBase58("HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH"))
+		SetAmountIn(3000).
+		SetMinimumAmountOut(1500).
+		SetPayer(solana.MustPublicKeyFromBase58("HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH"))
 
-	if sellInst.amount != 3000 {
-		t.Errorf("Expected amount 3000, got %d", sellInst.amount)
+	if sellInst.amountIn != 3000 {
+		t.Errorf("Expected amount in 3000, got %d", sellInst.amountIn)
 	}
 
 	// Test create token instruction chaining
@@ -409,19 +528,10 @@ func TestBuilderChaining(t *testing.T) {
 		SetDecimals(6).
 		SetName("Test").
 		SetSymbol("TST").
-		SetInitialSupply(1000000)
-
-	if createInst.decimals != 6 {
-		t.Errorf("Expected decimals 6, got %d", createInst.decimals)
-	}
-
-	// Test migrate instruction chaining
-	migrateInst := NewMigrateInstruction().
-		SetAmount(5000).
-		SetUserAuthority(solana.MustPublicKeyFromBase58("HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH"))
+		SetSupply(1000000)
 
-	if migrateInst.amount != 5000 {
-		t.Errorf("Expected amount 5000, got %d", migrateInst.amount)
+	if createInst.params.Decimals != 6 {
+		t.Errorf("Expected decimals 6, got %d", createInst.params.Decimals)
 	}
 
 	t.Log("✓ All builder chaining tests passed")
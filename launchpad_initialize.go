@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+var (
+	launchpadInitializeDiscriminator   = anchorInstructionDiscriminator("initialize")
+	launchpadInitializeV2Discriminator = anchorInstructionDiscriminator("initialize_v2")
+)
+
+// Account positions in the Launchpad initialize instruction, shared by the parser,
+// CreateTokenInstruction and DecodeCreateTokenInstruction
+const (
+	initializePayer             = 0
+	initializeCreator           = 1
+	initializeGlobalConfig      = 2
+	initializePlatformConfig    = 3
+	initializeAuthority         = 4
+	initializePoolState         = 5
+	initializeBaseMint          = 6
+	initializeQuoteMint         = 7
+	initializeBaseVault         = 8
+	initializeQuoteVault        = 9
+	initializeMetadata          = 10
+	initializeBaseTokenProgram  = 11
+	initializeQuoteTokenProgram = 12
+	initializeMetadataProgram   = 13
+	initializeSystemProgram     = 14
+	initializeRentSysvar        = 15
+	initializeEventAuthority    = 16
+	initializeProgram           = 17
+	initializeAccountsLength    = 18
+)
+
+// LaunchpadInitializeParams holds the decoded arguments of a Launchpad initialize instruction
+type LaunchpadInitializeParams struct {
+	// MintParams
+	Decimals uint8
+	Name     string
+	Symbol   string
+	URI      string
+
+	// CurveParams; CurveType is the enum variant (LAUNCHPAD_CURVE_*). Only the constant
+	// product variant carries TotalBaseSell; fixed and linear curves leave it zero and the
+	// program works it out from the other fields.
+	CurveType             uint8
+	Supply                uint64
+	TotalBaseSell         uint64
+	TotalQuoteFundRaising uint64
+	MigrateType           uint8
+
+	// VestingParams
+	TotalLockedAmount uint64
+	CliffPeriod       uint64
+	UnlockPeriod      uint64
+}
+
+// isLaunchpadInitializeInstruction reports whether instruction data is initialize or initialize_v2
+func isLaunchpadInitializeInstruction(data []byte) bool {
+	if len(data) < 8 {
+		return false
+	}
+	discriminator := [8]byte(data[:8])
+	return discriminator == launchpadInitializeDiscriminator || discriminator == launchpadInitializeV2Discriminator
+}
+
+// DecodeLaunchpadInitializeParams decodes initialize / initialize_v2 instruction data.
+// Both versions start with MintParams, CurveParams and VestingParams; v2 adds trailing
+// arguments that are ignored here.
+func DecodeLaunchpadInitializeParams(data []byte) (*LaunchpadInitializeParams, error) {
+	if !isLaunchpadInitializeInstruction(data) {
+		return nil, fmt.Errorf("not a launchpad initialize instruction")
+	}
+
+	r := newAccountReader(data)
+	r.skip(8)
+
+	params := &LaunchpadInitializeParams{}
+	params.Decimals = r.u8()
+	params.Name = r.borshString()
+	params.Symbol = r.borshString()
+	params.URI = r.borshString()
+
+	params.CurveType = r.u8()
+	if r.err == nil && params.CurveType > LAUNCHPAD_CURVE_LINEAR_PRICE {
+		return nil, fmt.Errorf("unknown launchpad curve type %d", params.CurveType)
+	}
+	// Constant { supply, total_base_sell, total_quote_fund_raising, migrate_type };
+	// Fixed and Linear { supply, total_quote_fund_raising, migrate_type }
+	params.Supply = r.u64()
+	if params.CurveType == LAUNCHPAD_CURVE_CONSTANT_PRODUCT {
+		params.TotalBaseSell = r.u64()
+	}
+	params.TotalQuoteFundRaising = r.u64()
+	params.MigrateType = r.u8()
+
+	params.TotalLockedAmount = r.u64()
+	params.CliffPeriod = r.u64()
+	params.UnlockPeriod = r.u64()
+
+	if r.err != nil {
+		return nil, fmt.Errorf("failed to decode launchpad initialize params: %w", r.err)
+	}
+	return params, nil
+}
+
+// Encode serializes the parameters as initialize instruction data, the inverse of
+// DecodeLaunchpadInitializeParams
+func (p *LaunchpadInitializeParams) Encode() []byte {
+	data := append([]byte{}, launchpadInitializeDiscriminator[:]...)
+	data = append(data, p.Decimals)
+	data = appendBorshString(data, p.Name)
+	data = appendBorshString(data, p.Symbol)
+	data = appendBorshString(data, p.URI)
+	data = append(data, p.CurveType)
+	data = binary.LittleEndian.AppendUint64(data, p.Supply)
+	if p.CurveType == LAUNCHPAD_CURVE_CONSTANT_PRODUCT {
+		data = binary.LittleEndian.AppendUint64(data, p.TotalBaseSell)
+	}
+	data = binary.LittleEndian.AppendUint64(data, p.TotalQuoteFundRaising)
+	data = append(data, p.MigrateType)
+	data = binary.LittleEndian.AppendUint64(data, p.TotalLockedAmount)
+	data = binary.LittleEndian.AppendUint64(data, p.CliffPeriod)
+	return binary.LittleEndian.AppendUint64(data, p.UnlockPeriod)
+}
+
+// newLaunchpadCreateInfo builds a CreateInfo from initialize accounts and parameters.
+// The base mint is created with the pool authority PDA as mint authority and no freeze
+// authority; token program CPIs later in the transaction can refine both.
+func newLaunchpadCreateInfo(accounts []solana.PublicKey, params *LaunchpadInitializeParams) (*CreateInfo, error) {
+	if len(accounts) < initializeAccountsLength {
+		return nil, fmt.Errorf("insufficient accounts for launchpad initialize: got %d, need %d", len(accounts), initializeAccountsLength)
+	}
+
+	return &CreateInfo{
+		TokenMint:     accounts[initializeBaseMint],
+		TokenDecimals: params.Decimals,
+		TokenSymbol:   params.Symbol,
+		TokenName:     params.Name,
+		TokenURI:      params.URI,
+		PoolAddress:   accounts[initializePoolState],
+		Creator:       accounts[initializeCreator],
+		Amount:        params.TotalBaseSell,
+		Supply:        params.Supply,
+		MintAuthority: accounts[initializeAuthority],
+	}, nil
+}
+
+// parseLaunchpadInitializeInstruction parses a Launchpad initialize from a compiled instruction
+func parseLaunchpadInitializeInstruction(instruction solana.CompiledInstruction, message *solana.Message, index int, result *Transaction) error {
+	accounts, err := resolveInstructionAccounts(instruction, message)
+	if err != nil {
+		return err
+	}
+	return appendLaunchpadCreate(accounts, instruction.Data, index, result)
+}
+
+// parseGeyserLaunchpadInitializeInstruction parses a Launchpad initialize in Geyser format
+func parseGeyserLaunchpadInitializeInstruction(instruction GeyserInstruction, index int, result *Transaction, meta *TransactionMeta) error {
+	return appendLaunchpadCreate(instruction.Accounts, instruction.Data, index, result)
+}
+
+func appendLaunchpadCreate(accounts []solana.PublicKey, data []byte, index int, result *Transaction) error {
+	params, err := DecodeLaunchpadInitializeParams(data)
+	if err != nil {
+		return err
+	}
+	createInfo, err := newLaunchpadCreateInfo(accounts, params)
+	if err != nil {
+		return err
+	}
+
+	result.observeToken(TokenInfo{
+		Mint:     createInfo.TokenMint,
+		Symbol:   createInfo.TokenSymbol,
+		Name:     createInfo.TokenName,
+		Decimals: createInfo.TokenDecimals,
+	})
+
+	result.logf("Launchpad token launch at index %d: %s (%s) mint %s", index, createInfo.TokenName, createInfo.TokenSymbol, createInfo.TokenMint)
+	result.Create = append(result.Create, *createInfo)
+	return nil
+}
+
+// findCreateByMint returns the create entry for a mint parsed earlier in the transaction
+func findCreateByMint(result *Transaction, mint solana.PublicKey) *CreateInfo {
+	for i := range result.Create {
+		if result.Create[i].TokenMint.Equals(mint) {
+			return &result.Create[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+var sampleInitializeParams = LaunchpadInitializeParams{
+	Decimals:              6,
+	Name:                  "Bonk Test",
+	Symbol:                "BTEST",
+	URI:                   "https://ipfs.io/ipfs/bafkreitest",
+	CurveType:             LAUNCHPAD_CURVE_CONSTANT_PRODUCT,
+	Supply:                1_000_000_000_000_000,
+	TotalBaseSell:         793_100_000_000_000,
+	TotalQuoteFundRaising: 85_000_000_000,
+	MigrateType:           LAUNCHPAD_MIGRATE_TYPE_CPSWAP,
+}
+
+func TestDecodeLaunchpadInitializeParams(t *testing.T) {
+	if launchpadInitializeDiscriminator != [8]byte{0xaf, 0xaf, 0x6d, 0x1f, 0x0d, 0x98, 0x9b, 0xed} {
+		t.Fatalf("Unexpected initialize discriminator %x", launchpadInitializeDiscriminator)
+	}
+
+	params, err := DecodeLaunchpadInitializeParams(sampleInitializeParams.Encode())
+	if err != nil {
+		t.Fatalf("Failed to decode params: %v", err)
+	}
+	if *params != sampleInitializeParams {
+		t.Errorf("Decoded params mismatch:\n got %+v\nwant %+v", *params, sampleInitializeParams)
+	}
+
+	truncated := sampleInitializeParams.Encode()[:30]
+	if _, err := DecodeLaunchpadInitializeParams(truncated); err == nil {
+		t.Errorf("Expected error for truncated data")
+	}
+}
+
+func TestParseLaunchpadInitializeFixedAndLinearCurves(t *testing.T) {
+	for _, curveType := range []uint8{LAUNCHPAD_CURVE_FIXED_PRICE, LAUNCHPAD_CURVE_LINEAR_PRICE} {
+		// Fixed and Linear carry supply, total_quote_fund_raising and migrate_type only
+		data := append([]byte{}, launchpadInitializeDiscriminator[:]...)
+		data = append(data, 6)
+		data = appendBorshString(data, "Fixed Test")
+		data = appendBorshString(data, "FTEST")
+		data = appendBorshString(data, "https://example.com/f.json")
+		data = append(data, curveType)
+		data = binary.LittleEndian.AppendUint64(data, 1_000_000_000_000_000)
+		data = binary.LittleEndian.AppendUint64(data, 85_000_000_000)
+		data = append(data, LAUNCHPAD_MIGRATE_TYPE_AMM)
+		for _, v := range []uint64{100_000_000_000_000, 3600, 86400} {
+			data = binary.LittleEndian.AppendUint64(data, v)
+		}
+
+		want := LaunchpadInitializeParams{
+			Decimals: 6, Name: "Fixed Test", Symbol: "FTEST", URI: "https://example.com/f.json",
+			CurveType: curveType, Supply: 1_000_000_000_000_000, TotalQuoteFundRaising: 85_000_000_000,
+			MigrateType: LAUNCHPAD_MIGRATE_TYPE_AMM, TotalLockedAmount: 100_000_000_000_000, CliffPeriod: 3600, UnlockPeriod: 86400,
+		}
+		params, err := DecodeLaunchpadInitializeParams(data)
+		if err != nil {
+			t.Fatalf("Curve %d: failed to decode params: %v", curveType, err)
+		}
+		if *params != want {
+			t.Errorf("Curve %d: decoded params mismatch:\n got %+v\nwant %+v", curveType, *params, want)
+		}
+		if encoded := want.Encode(); string(encoded) != string(data) {
+			t.Errorf("Curve %d: Encode does not match the on-chain layout", curveType)
+		}
+
+		accounts := uniqueAccounts(initializeAccountsLength)
+		result, err := ParseTransaction(buildLaunchpadTransaction(t, data, accounts), 1, WithoutParseLogging())
+		if err != nil || len(result.Create) != 1 {
+			t.Fatalf("Curve %d: expected 1 create, got %+v (%v)", curveType, result, err)
+		}
+		if create := result.Create[0]; !create.TokenMint.Equals(accounts[initializeBaseMint]) || create.Supply != want.Supply || create.TokenSymbol != "FTEST" {
+			t.Errorf("Curve %d: unexpected create %+v", curveType, create)
+		}
+	}
+}
+
+func TestParseLaunchpadInitializeWithMetadata(t *testing.T) {
+	previous := CurrentTokenInfoProvider()
+	defer SetTokenInfoProvider(previous)
+	SetTokenInfoProvider(NewCachedTokenInfoProvider(defaultTokens...))
+
+	accounts := uniqueAccounts(initializeAccountsLength)
+	encoded := buildLaunchpadTransaction(t, sampleInitializeParams.Encode(), accounts)
+
+	mint := accounts[initializeBaseMint]
+	mintIndex := uint16(indexOfKey(t, encoded, mint))
+	metadataIndex := uint16(indexOfKey(t, encoded, accounts[initializeMetadata]))
+	authorityIndex := uint16(indexOfKey(t, encoded, accounts[initializeAuthority]))
+
+	// The program IDs of the CPIs are not in the static keys; supply them as loaded addresses
+	// the way an address lookup table would
+	staticKeys := uint16(len(accounts) + 1)
+	tokenProgramIndex, metaplexIndex := staticKeys, staticKeys+1
+
+	metadataData := []byte{METAPLEX_INSTRUCTION_CREATE_METADATA_ACCOUNT_V3}
+	metadataData = appendBorshString(metadataData, "Bonk Test Metadata\x00\x00")
+	metadataData = appendBorshString(metadataData, "OTHER")
+	metadataData = appendBorshString(metadataData, "https://example.com/other.json")
+
+	revokeMint := []byte{TOKEN_INSTRUCTION_SET_AUTHORITY, tokenAuthorityTypeMintTokens, 0}
+
+	meta := &TransactionMeta{
+		LoadedReadonlyAddresses: []solana.PublicKey{TokenProgramID, MetaplexTokenMetadataProgramID},
+		InnerInstructions: []CompiledInnerInstructions{{
+			Index: 0,
+			Instructions: []solana.CompiledInstruction{
+				{ProgramIDIndex: metaplexIndex, Accounts: []uint16{metadataIndex, mintIndex, authorityIndex}, Data: metadataData},
+				{ProgramIDIndex: tokenProgramIndex, Accounts: []uint16{mintIndex, authorityIndex}, Data: revokeMint},
+			},
+		}},
+	}
+
+	result, err := ParseTransactionWithMeta(encoded, 1, meta)
+	if err != nil {
+		t.Fatalf("Failed to parse transaction: %v", err)
+	}
+	if len(result.Create) != 1 {
+		t.Fatalf("Expected 1 create, got %d", len(result.Create))
+	}
+
+	create := result.Create[0]
+	if !create.TokenMint.Equals(mint) || !create.PoolAddress.Equals(accounts[initializePoolState]) {
+		t.Errorf("Wrong mint %s / pool %s", create.TokenMint, create.PoolAddress)
+	}
+	if !create.Creator.Equals(accounts[initializeCreator]) {
+		t.Errorf("Wrong creator %s", create.Creator)
+	}
+	// Initialize parameters take precedence over the metadata CPI
+	if create.TokenName != "Bonk Test" || create.TokenSymbol != "BTEST" || create.TokenURI != sampleInitializeParams.URI {
+		t.Errorf("Unexpected metadata %q %q %q", create.TokenName, create.TokenSymbol, create.TokenURI)
+	}
+	if create.TokenDecimals != 6 || create.Supply != sampleInitializeParams.Supply {
+		t.Errorf("Unexpected decimals %d / supply %d", create.TokenDecimals, create.Supply)
+	}
+	if !create.MintAuthority.IsZero() {
+		t.Errorf("Expected revoked mint authority, got %s", create.MintAuthority)
+	}
+	if !create.FreezeAuthority.IsZero() {
+		t.Errorf("Expected no freeze authority, got %s", create.FreezeAuthority)
+	}
+
+	// The launched token is reported on the transaction but not cached
+	if want := (TokenInfo{Mint: mint, Symbol: "BTEST", Name: "Bonk Test", Decimals: 6}); len(result.Tokens) != 1 || result.Tokens[0] != want {
+		t.Errorf("Expected tokens [%+v], got %+v", want, result.Tokens)
+	}
+	if _, known := lookupToken(mint); known {
+		t.Errorf("Expected parsing not to cache the created token")
+	}
+}
+
+func TestApplyTokenMetadataFillsMissingFields(t *testing.T) {
+	mint := solana.NewWallet().PublicKey()
+	result := &Transaction{Create: []CreateInfo{{TokenMint: mint, TokenSymbol: "UNKNOWN"}}}
+
+	data := []byte{METAPLEX_INSTRUCTION_CREATE_METADATA_ACCOUNT_V3}
+	data = appendBorshString(data, "Padded Name\x00\x00\x00")
+	data = appendBorshString(data, "PAD\x00")
+	data = appendBorshString(data, "https://example.com/pad.json")
+
+	if err := applyMetaplexInstruction([]solana.PublicKey{solana.NewWallet().PublicKey(), mint}, data, 0, result); err != nil {
+		t.Fatalf("Failed to apply metadata: %v", err)
+	}
+	create := result.Create[0]
+	if create.TokenName != "Padded Name" || create.TokenSymbol != "PAD" || create.TokenURI != "https://example.com/pad.json" {
+		t.Errorf("Unexpected metadata %q %q %q", create.TokenName, create.TokenSymbol, create.TokenURI)
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Migration destinations reported in Migration.Destination
+const (
+	MIGRATION_DESTINATION_AMM_V4 = "amm_v4"
+	MIGRATION_DESTINATION_CPSWAP = "cpswap"
+)
+
+// LP handling reported in Migration.LpHandling
+const (
+	MIGRATION_LP_BURNED = "burned"
+	MIGRATION_LP_LOCKED = "locked"
+)
+
+var (
+	launchpadMigrateToAmmDiscriminator    = anchorInstructionDiscriminator("migrate_to_amm")
+	launchpadMigrateToCpSwapDiscriminator = anchorInstructionDiscriminator("migrate_to_cpswap")
+)
+
+// Account positions in the Launchpad migrate_to_amm instruction
+const (
+	migrateAmmPayer          = 0
+	migrateAmmBaseMint       = 1
+	migrateAmmQuoteMint      = 2
+	migrateAmmProgram        = 12
+	migrateAmmPool           = 13
+	migrateAmmLpMint         = 16
+	migrateAmmBaseVault      = 17
+	migrateAmmQuoteVault     = 18
+	migrateAmmLaunchpadPool  = 23
+	migrateAmmPoolLpToken    = 27
+	migrateAmmAccountsLength = 32
+)
+
+// Account positions in the Launchpad migrate_to_cpswap instruction
+const (
+	migrateCpSwapPayer          = 0
+	migrateCpSwapBaseMint       = 1
+	migrateCpSwapQuoteMint      = 2
+	migrateCpSwapProgram        = 4
+	migrateCpSwapPool           = 5
+	migrateCpSwapLpMint         = 7
+	migrateCpSwapBaseVault      = 8
+	migrateCpSwapQuoteVault     = 9
+	migrateCpSwapLockLpVault    = 15
+	migrateCpSwapLaunchpadPool  = 17
+	migrateCpSwapAccountsLength = 28
+)
+
+// isLaunchpadMigrateInstruction reports whether instruction data is migrate_to_amm or migrate_to_cpswap
+func isLaunchpadMigrateInstruction(data []byte) bool {
+	if len(data) < 8 {
+		return false
+	}
+	discriminator := [8]byte(data[:8])
+	return discriminator == launchpadMigrateToAmmDiscriminator || discriminator == launchpadMigrateToCpSwapDiscriminator
+}
+
+// decodeLaunchpadMigration decodes a Launchpad migrate instruction from its resolved account list.
+// Liquidity amounts are not part of the instruction and are filled from metadata by applyMigrationBalances.
+func decodeLaunchpadMigration(accounts []solana.PublicKey, data []byte) (*Migration, error) {
+	if len(data) < 8 {
+		return nil, fmt.Errorf("migrate instruction data too short: %d bytes", len(data))
+	}
+
+	switch [8]byte(data[:8]) {
+	case launchpadMigrateToAmmDiscriminator:
+		if len(accounts) < migrateAmmAccountsLength {
+			return nil, fmt.Errorf("insufficient accounts for migrate_to_amm: got %d, need %d", len(accounts), migrateAmmAccountsLength)
+		}
+		return &Migration{
+			FromPool:     accounts[migrateAmmLaunchpadPool],
+			ToPool:       accounts[migrateAmmPool],
+			Token:        accounts[migrateAmmBaseMint],
+			Owner:        accounts[migrateAmmPayer],
+			Destination:  MIGRATION_DESTINATION_AMM_V4,
+			ToProgram:    accounts[migrateAmmProgram],
+			QuoteMint:    accounts[migrateAmmQuoteMint],
+			LpMint:       accounts[migrateAmmLpMint],
+			ToBaseVault:  accounts[migrateAmmBaseVault],
+			ToQuoteVault: accounts[migrateAmmQuoteVault],
+			LpAccount:    accounts[migrateAmmPoolLpToken],
+			LpHandling:   MIGRATION_LP_BURNED,
+		}, nil
+	case launchpadMigrateToCpSwapDiscriminator:
+		if len(accounts) < migrateCpSwapAccountsLength {
+			return nil, fmt.Errorf("insufficient accounts for migrate_to_cpswap: got %d, need %d", len(accounts), migrateCpSwapAccountsLength)
+		}
+		return &Migration{
+			FromPool:     accounts[migrateCpSwapLaunchpadPool],
+			ToPool:       accounts[migrateCpSwapPool],
+			Token:        accounts[migrateCpSwapBaseMint],
+			Owner:        accounts[migrateCpSwapPayer],
+			Destination:  MIGRATION_DESTINATION_CPSWAP,
+			ToProgram:    accounts[migrateCpSwapProgram],
+			QuoteMint:    accounts[migrateCpSwapQuoteMint],
+			LpMint:       accounts[migrateCpSwapLpMint],
+			ToBaseVault:  accounts[migrateCpSwapBaseVault],
+			ToQuoteVault: accounts[migrateCpSwapQuoteVault],
+			LpAccount:    accounts[migrateCpSwapLockLpVault],
+			LpHandling:   MIGRATION_LP_LOCKED,
+		}, nil
+	default:
+		return nil, fmt.Errorf("not a launchpad migrate instruction: %x", data[:8])
+	}
+}
+
+// parseLaunchpadMigrateInstruction parses migrate_to_amm / migrate_to_cpswap from a compiled instruction
+func parseLaunchpadMigrateInstruction(instruction solana.CompiledInstruction, message *solana.Message, index int, result *Transaction) error {
+	accounts, err := resolveInstructionAccounts(instruction, message)
+	if err != nil {
+		return err
+	}
+	return appendLaunchpadMigration(accounts, instruction.Data, index, result)
+}
+
+// parseGeyserLaunchpadMigrateInstruction parses migrate_to_amm / migrate_to_cpswap in Geyser format.
+// As on the RPC path, amounts are filled from the metadata once the whole transaction is parsed.
+func parseGeyserLaunchpadMigrateInstruction(instruction GeyserInstruction, index int, result *Transaction) error {
+	return appendLaunchpadMigration(instruction.Accounts, instruction.Data, index, result)
+}
+
+func appendLaunchpadMigration(accounts []solana.PublicKey, data []byte, index int, result *Transaction) error {
+	migration, err := decodeLaunchpadMigration(accounts, data)
+	if err != nil {
+		return err
+	}
+
+	result.logf("Launchpad migration at index %d: %s -> %s (%s)", index, migration.FromPool, migration.ToPool, migration.Destination)
+	result.Migrate = append(result.Migrate, *migration)
+	return nil
+}
+
+// applyMigrationBalances fills migrated liquidity from the token balance changes of the
+// destination vaults and the LP account
+func applyMigrationBalances(migration *Migration, meta *TransactionMeta) {
+	if migration.Destination == "" {
+		return
+	}
+	if delta, ok := meta.tokenBalanceIncrease(migration.ToBaseVault); ok {
+		migration.BaseAmount = delta
+		migration.Amount = delta
+	}
+	if delta, ok := meta.tokenBalanceIncrease(migration.ToQuoteVault); ok {
+		migration.QuoteAmount = delta
+	}
+	// LP tokens on the burn path are gone by the end of the transaction, so only
+	// locked LP shows up as a balance increase
+	if delta, ok := meta.tokenBalanceIncrease(migration.LpAccount); ok {
+		migration.LpAmount = delta
+	}
+}
+
+// resolveInstructionAccounts maps a compiled instruction's account indexes to public keys
+func resolveInstructionAccounts(instruction solana.CompiledInstruction, message *solana.Message) ([]solana.PublicKey, error) {
+	accounts := make([]solana.PublicKey, len(instruction.Accounts))
+	for i, accountIndex := range instruction.Accounts {
+		if int(accountIndex) >= len(message.AccountKeys) {
+			return nil, fmt.Errorf("account index %d out of range (%d keys)", accountIndex, len(message.AccountKeys))
+		}
+		accounts[i] = message.AccountKeys[accountIndex]
+	}
+	return accounts, nil
+}
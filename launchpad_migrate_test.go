@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/base64"
+	"sync"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// buildLaunchpadTransaction encodes a single-instruction Launchpad transaction with the given accounts
+func buildLaunchpadTransaction(t *testing.T, data []byte, accounts []solana.PublicKey) string {
+	t.Helper()
+
+	metas := make(solana.AccountMetaSlice, len(accounts))
+	for i, account := range accounts {
+		metas[i] = solana.NewAccountMeta(account, true, i == 0)
+	}
+	instruction := solana.NewInstruction(RaydiumLaunchpadV1ProgramID, metas, data)
+
+	tx, err := solana.NewTransaction([]solana.Instruction{instruction}, solana.Hash{}, solana.TransactionPayer(accounts[0]))
+	if err != nil {
+		t.Fatalf("Failed to build transaction: %v", err)
+	}
+	tx.Signatures = []solana.Signature{{1}}
+
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to encode transaction: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+func uniqueAccounts(n int) []solana.PublicKey {
+	accounts := make([]solana.PublicKey, n)
+	for i := range accounts {
+		accounts[i] = solana.NewWallet().PublicKey()
+	}
+	return accounts
+}
+
+// indexOfKey returns the position of key in the encoded transaction's account keys
+func indexOfKey(t *testing.T, encodedTx string, key solana.PublicKey) int {
+	t.Helper()
+	raw, _ := base64.StdEncoding.DecodeString(encodedTx)
+	tx, err := solana.TransactionFromBytes(raw)
+	if err != nil {
+		t.Fatalf("Failed to decode transaction: %v", err)
+	}
+	for i, account := range tx.Message.AccountKeys {
+		if account.Equals(key) {
+			return i
+		}
+	}
+	t.Fatalf("Account %s not found in transaction", key)
+	return -1
+}
+
+func TestParseMigrateToAmm(t *testing.T) {
+	accounts := uniqueAccounts(migrateAmmAccountsLength)
+	encoded := buildLaunchpadTransaction(t, launchpadMigrateToAmmDiscriminator[:], accounts)
+
+	meta := &TransactionMeta{
+		PreTokenBalances: []TokenBalance{
+			{AccountIndex: indexOfKey(t, encoded, accounts[migrateAmmQuoteVault]), Amount: 0},
+		},
+		PostTokenBalances: []TokenBalance{
+			{AccountIndex: indexOfKey(t, encoded, accounts[migrateAmmBaseVault]), Amount: 206_900_000_000_000},
+			{AccountIndex: indexOfKey(t, encoded, accounts[migrateAmmQuoteVault]), Amount: 79_005_359_057},
+		},
+	}
+
+	result, err := ParseTransactionWithMeta(encoded, 1, meta)
+	if err != nil {
+		t.Fatalf("Failed to parse transaction: %v", err)
+	}
+	if len(result.Migrate) != 1 {
+		t.Fatalf("Expected 1 migration, got %d", len(result.Migrate))
+	}
+
+	m := result.Migrate[0]
+	if m.Destination != MIGRATION_DESTINATION_AMM_V4 || m.LpHandling != MIGRATION_LP_BURNED {
+		t.Errorf("Unexpected destination %q / LP handling %q", m.Destination, m.LpHandling)
+	}
+	if !m.FromPool.Equals(accounts[migrateAmmLaunchpadPool]) || !m.ToPool.Equals(accounts[migrateAmmPool]) {
+		t.Errorf("Wrong pools: %s -> %s", m.FromPool, m.ToPool)
+	}
+	if !m.Token.Equals(accounts[migrateAmmBaseMint]) || !m.QuoteMint.Equals(accounts[migrateAmmQuoteMint]) {
+		t.Errorf("Wrong mints: %s / %s", m.Token, m.QuoteMint)
+	}
+	if !m.LpMint.Equals(accounts[migrateAmmLpMint]) {
+		t.Errorf("Wrong LP mint: %s", m.LpMint)
+	}
+	if m.BaseAmount != 206_900_000_000_000 || m.Amount != m.BaseAmount {
+		t.Errorf("Expected base amount 206900000000000, got %d (amount %d)", m.BaseAmount, m.Amount)
+	}
+	if m.QuoteAmount != 79_005_359_057 {
+		t.Errorf("Expected quote amount 79005359057, got %d", m.QuoteAmount)
+	}
+}
+
+func TestParseMigrateToCpSwap(t *testing.T) {
+	accounts := uniqueAccounts(migrateCpSwapAccountsLength)
+	encoded := buildLaunchpadTransaction(t, launchpadMigrateToCpSwapDiscriminator[:], accounts)
+
+	meta := &TransactionMeta{
+		PostTokenBalances: []TokenBalance{
+			{AccountIndex: indexOfKey(t, encoded, accounts[migrateCpSwapLockLpVault]), Amount: 4_000_000_000},
+		},
+	}
+
+	result, err := ParseTransactionWithMeta(encoded, 1, meta)
+	if err != nil {
+		t.Fatalf("Failed to parse transaction: %v", err)
+	}
+	if len(result.Migrate) != 1 {
+		t.Fatalf("Expected 1 migration, got %d", len(result.Migrate))
+	}
+
+	m := result.Migrate[0]
+	if m.Destination != MIGRATION_DESTINATION_CPSWAP || m.LpHandling != MIGRATION_LP_LOCKED {
+		t.Errorf("Unexpected destination %q / LP handling %q", m.Destination, m.LpHandling)
+	}
+	if !m.ToPool.Equals(accounts[migrateCpSwapPool]) || !m.ToProgram.Equals(accounts[migrateCpSwapProgram]) {
+		t.Errorf("Wrong destination pool %s / program %s", m.ToPool, m.ToProgram)
+	}
+	if m.LpAmount != 4_000_000_000 {
+		t.Errorf("Expected locked LP amount 4000000000, got %d", m.LpAmount)
+	}
+	if m.BaseAmount != 0 {
+		t.Errorf("Expected no base amount without vault balances, got %d", m.BaseAmount)
+	}
+}
+
+func TestParseTransactionWithMetaSharedMeta(t *testing.T) {
+	accounts := uniqueAccounts(migrateCpSwapAccountsLength)
+	encoded := buildLaunchpadTransaction(t, launchpadMigrateToCpSwapDiscriminator[:], accounts)
+	meta := &TransactionMeta{
+		PostTokenBalances: []TokenBalance{
+			{AccountIndex: indexOfKey(t, encoded, accounts[migrateCpSwapLockLpVault]), Amount: 4_000_000_000},
+		},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := ParseTransactionWithMeta(encoded, 1, meta, WithoutParseLogging())
+			if err != nil || len(result.Migrate) != 1 || result.Migrate[0].LpAmount != 4_000_000_000 {
+				t.Errorf("Unexpected result %+v (%v)", result, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Accounts are resolved on a copy
+	if !meta.PostTokenBalances[0].Account.IsZero() {
+		t.Errorf("Expected the caller's meta to be left alone, got account %s", meta.PostTokenBalances[0].Account)
+	}
+}
+
+func TestDecodeLaunchpadMigrationErrors(t *testing.T) {
+	if _, err := decodeLaunchpadMigration(uniqueAccounts(10), launchpadMigrateToAmmDiscriminator[:]); err == nil {
+		t.Errorf("Expected error for too few migrate_to_amm accounts")
+	}
+	if _, err := decodeLaunchpadMigration(uniqueAccounts(40), []byte{1, 2, 3, 4, 5, 6, 7, 8}); err == nil {
+		t.Errorf("Expected error for unknown discriminator")
+	}
+}
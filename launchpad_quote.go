@@ -0,0 +1,392 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// LAUNCHPAD_FEE_RATE_DENOMINATOR is the denominator for all Launchpad fee rates (1_000_000 = 100%)
+const LAUNCHPAD_FEE_RATE_DENOMINATOR = 1_000_000
+
+// Errors returned by the Launchpad quote engine
+var (
+	ErrLaunchpadPoolNotTrading      = errors.New("launchpad pool is not open for trading")
+	ErrLaunchpadZeroAmount          = errors.New("quote amount must be greater than zero")
+	ErrLaunchpadInsufficientReserve = errors.New("amount exceeds the pool's available reserve")
+	ErrLaunchpadFeeRateTooHigh      = errors.New("combined fee rate must be below 100%")
+)
+
+// LaunchpadFeeRates holds the fee rates charged on a Launchpad trade, each out of LAUNCHPAD_FEE_RATE_DENOMINATOR.
+// Fees are always charged in the quote token.
+type LaunchpadFeeRates struct {
+	TradeFeeRate    uint64 // GlobalConfig.trade_fee_rate
+	PlatformFeeRate uint64 // PlatformConfig.fee_rate
+	CreatorFeeRate  uint64 // PlatformConfig.creator_fee_rate
+	// ShareFeeRate is the trade's share_fee_rate, paid to the referrer on top of the other fees
+	ShareFeeRate uint64
+}
+
+func (f LaunchpadFeeRates) total() uint64 {
+	return f.TradeFeeRate + f.PlatformFeeRate + f.CreatorFeeRate + f.ShareFeeRate
+}
+
+// LaunchpadQuote represents the expected result of a Launchpad buy or sell
+type LaunchpadQuote struct {
+	IsBuy   bool
+	ExactIn bool
+
+	// AmountIn is the total amount the trader pays, including fees for buys
+	AmountIn uint64
+	// AmountOut is the amount the trader receives, net of fees for sells
+	AmountOut uint64
+
+	TradeFee    uint64
+	PlatformFee uint64
+	CreatorFee  uint64
+	ShareFee    uint64
+	// ShareFeeRate is the referral rate the quote was made with; ApplyToBuy and ApplyToSell
+	// send it so the instruction pays the fee the quote expects
+	ShareFeeRate uint64
+
+	// Prices are in raw quote units per raw base unit and exclude fees
+	SpotPrice      float64
+	ExecutionPrice float64
+	// PriceImpact is the relative move of the execution price away from the spot price
+	PriceImpact float64
+}
+
+// TotalFee returns the sum of all fees charged on the trade
+func (q *LaunchpadQuote) TotalFee() uint64 {
+	return q.TradeFee + q.PlatformFee + q.CreatorFee + q.ShareFee
+}
+
+// MinimumAmountOut returns AmountOut reduced by a slippage tolerance in basis points
+func (q *LaunchpadQuote) MinimumAmountOut(slippageBps uint16) uint64 {
+	return applySlippageDown(q.AmountOut, slippageBps)
+}
+
+// MaximumAmountIn returns AmountIn increased by a slippage tolerance in basis points
+func (q *LaunchpadQuote) MaximumAmountIn(slippageBps uint16) uint64 {
+	return applySlippageUp(q.AmountIn, slippageBps)
+}
+
+// ApplyToBuy fills a buy builder's direction, amounts and share fee rate from the quote,
+// applying the slippage tolerance to the side the quote does not fix
+func (q *LaunchpadQuote) ApplyToBuy(b *BuyInstruction, slippageBps uint16) error {
+	if !q.IsBuy {
+		return fmt.Errorf("cannot apply a sell quote to a buy instruction")
+	}
+	b.SetExactOut(!q.ExactIn).SetShareFeeRate(q.ShareFeeRate)
+	if q.ExactIn {
+		b.SetAmountIn(q.AmountIn).SetMinimumAmountOut(q.MinimumAmountOut(slippageBps)).SetExpectedAmountOut(q.AmountOut)
+	} else {
+		b.SetAmountOut(q.AmountOut).SetMaxAmountIn(q.MaximumAmountIn(slippageBps)).SetExpectedAmountIn(q.AmountIn)
+	}
+	return nil
+}
+
+// ApplyToSell fills a sell builder's direction, amounts and share fee rate from the quote,
+// applying the slippage tolerance to the side the quote does not fix
+func (q *LaunchpadQuote) ApplyToSell(s *SellInstruction, slippageBps uint16) error {
+	if q.IsBuy {
+		return fmt.Errorf("cannot apply a buy quote to a sell instruction")
+	}
+	s.SetExactOut(!q.ExactIn).SetShareFeeRate(q.ShareFeeRate)
+	if q.ExactIn {
+		s.SetAmountIn(q.AmountIn).SetMinimumAmountOut(q.MinimumAmountOut(slippageBps)).SetExpectedAmountOut(q.AmountOut)
+	} else {
+		s.SetAmountOut(q.AmountOut).SetMaxAmountIn(q.MaximumAmountIn(slippageBps)).SetExpectedAmountIn(q.AmountIn)
+	}
+	return nil
+}
+
+// QuoteLaunchpadBuyExactIn quotes how many base tokens amountIn quote tokens (fees included) buys
+func QuoteLaunchpadBuyExactIn(pool *LaunchpadPoolState, fees LaunchpadFeeRates, amountIn uint64) (*LaunchpadQuote, error) {
+	if err := checkLaunchpadQuote(pool, fees, amountIn); err != nil {
+		return nil, err
+	}
+
+	quote := &LaunchpadQuote{IsBuy: true, ExactIn: true, AmountIn: amountIn}
+	quote.setFees(amountIn, fees)
+	if quote.TotalFee() >= amountIn {
+		return nil, fmt.Errorf("amount in %d does not cover fees of %d", amountIn, quote.TotalFee())
+	}
+	curveIn := amountIn - quote.TotalFee()
+
+	amountOut, err := launchpadCurveBuyExactIn(pool, curveIn)
+	if err != nil {
+		return nil, err
+	}
+	if amountOut > pool.RemainingBaseSell() {
+		return nil, fmt.Errorf("%w: buy of %d base exceeds remaining %d", ErrLaunchpadInsufficientReserve, amountOut, pool.RemainingBaseSell())
+	}
+
+	quote.AmountOut = amountOut
+	quote.setPrices(pool, curveIn, amountOut)
+	return quote, nil
+}
+
+// QuoteLaunchpadBuyExactOut quotes how many quote tokens (fees included) are needed to buy amountOut base tokens
+func QuoteLaunchpadBuyExactOut(pool *LaunchpadPoolState, fees LaunchpadFeeRates, amountOut uint64) (*LaunchpadQuote, error) {
+	if err := checkLaunchpadQuote(pool, fees, amountOut); err != nil {
+		return nil, err
+	}
+	if amountOut > pool.RemainingBaseSell() {
+		return nil, fmt.Errorf("%w: buy of %d base exceeds remaining %d", ErrLaunchpadInsufficientReserve, amountOut, pool.RemainingBaseSell())
+	}
+
+	curveIn, err := launchpadCurveBuyExactOut(pool, amountOut)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &LaunchpadQuote{IsBuy: true, ExactIn: false, AmountOut: amountOut}
+	quote.AmountIn = grossUpForFees(curveIn, fees)
+	quote.setFees(quote.AmountIn, fees)
+	quote.setPrices(pool, curveIn, amountOut)
+	return quote, nil
+}
+
+// QuoteLaunchpadSellExactIn quotes how many quote tokens (net of fees) selling amountIn base tokens returns
+func QuoteLaunchpadSellExactIn(pool *LaunchpadPoolState, fees LaunchpadFeeRates, amountIn uint64) (*LaunchpadQuote, error) {
+	if err := checkLaunchpadQuote(pool, fees, amountIn); err != nil {
+		return nil, err
+	}
+	if amountIn > pool.RealBase {
+		return nil, fmt.Errorf("%w: sell of %d base exceeds sold supply %d", ErrLaunchpadInsufficientReserve, amountIn, pool.RealBase)
+	}
+
+	curveOut, err := launchpadCurveSellExactIn(pool, amountIn)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &LaunchpadQuote{IsBuy: false, ExactIn: true, AmountIn: amountIn}
+	quote.setFees(curveOut, fees)
+	if quote.TotalFee() >= curveOut {
+		return nil, fmt.Errorf("sell proceeds %d do not cover fees of %d", curveOut, quote.TotalFee())
+	}
+	quote.AmountOut = curveOut - quote.TotalFee()
+	quote.setPrices(pool, curveOut, amountIn)
+	return quote, nil
+}
+
+// QuoteLaunchpadSellExactOut quotes how many base tokens must be sold to receive amountOut quote tokens net of fees
+func QuoteLaunchpadSellExactOut(pool *LaunchpadPoolState, fees LaunchpadFeeRates, amountOut uint64) (*LaunchpadQuote, error) {
+	if err := checkLaunchpadQuote(pool, fees, amountOut); err != nil {
+		return nil, err
+	}
+
+	curveOut := grossUpForFees(amountOut, fees)
+	if curveOut > pool.RealQuote {
+		return nil, fmt.Errorf("%w: sell for %d quote exceeds raised %d", ErrLaunchpadInsufficientReserve, curveOut, pool.RealQuote)
+	}
+
+	amountIn, err := launchpadCurveSellExactOut(pool, curveOut)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &LaunchpadQuote{IsBuy: false, ExactIn: false, AmountIn: amountIn, AmountOut: amountOut}
+	quote.setFees(curveOut, fees)
+	quote.setPrices(pool, curveOut, amountIn)
+	return quote, nil
+}
+
+func checkLaunchpadQuote(pool *LaunchpadPoolState, fees LaunchpadFeeRates, amount uint64) error {
+	if pool == nil || !pool.IsTrading() {
+		return ErrLaunchpadPoolNotTrading
+	}
+	if amount == 0 {
+		return ErrLaunchpadZeroAmount
+	}
+	if fees.total() >= LAUNCHPAD_FEE_RATE_DENOMINATOR {
+		return ErrLaunchpadFeeRateTooHigh
+	}
+	return nil
+}
+
+// setFees charges every fee on the given quote amount, rounding up as the program does
+func (q *LaunchpadQuote) setFees(quoteAmount uint64, fees LaunchpadFeeRates) {
+	q.TradeFee = launchpadFee(quoteAmount, fees.TradeFeeRate)
+	q.PlatformFee = launchpadFee(quoteAmount, fees.PlatformFeeRate)
+	q.CreatorFee = launchpadFee(quoteAmount, fees.CreatorFeeRate)
+	q.ShareFee = launchpadFee(quoteAmount, fees.ShareFeeRate)
+	q.ShareFeeRate = fees.ShareFeeRate
+}
+
+// setPrices records spot and execution prices; quoteAmount and baseAmount exclude fees
+func (q *LaunchpadQuote) setPrices(pool *LaunchpadPoolState, quoteAmount, baseAmount uint64) {
+	q.SpotPrice = pool.rawPrice()
+	if baseAmount > 0 {
+		q.ExecutionPrice = float64(quoteAmount) / float64(baseAmount)
+	}
+	if q.SpotPrice > 0 {
+		if q.IsBuy {
+			q.PriceImpact = q.ExecutionPrice/q.SpotPrice - 1
+		} else {
+			q.PriceImpact = 1 - q.ExecutionPrice/q.SpotPrice
+		}
+	}
+}
+
+func launchpadFee(amount, rate uint64) uint64 {
+	return mulDivCeil(amount, rate, LAUNCHPAD_FEE_RATE_DENOMINATOR)
+}
+
+// grossUpForFees returns the smallest amount that still leaves net after all fees are deducted
+func grossUpForFees(net uint64, fees LaunchpadFeeRates) uint64 {
+	gross := mulDivCeil(net, LAUNCHPAD_FEE_RATE_DENOMINATOR, LAUNCHPAD_FEE_RATE_DENOMINATOR-fees.total())
+	// Each fee is rounded up separately, so the closed form can fall a few units short
+	for {
+		var charged LaunchpadQuote
+		charged.setFees(gross, fees)
+		if gross-charged.TotalFee() >= net {
+			return gross
+		}
+		gross++
+	}
+}
+
+// launchpadCurveBuyExactIn returns the base tokens bought with quoteIn (fees already removed)
+func launchpadCurveBuyExactIn(pool *LaunchpadPoolState, quoteIn uint64) (uint64, error) {
+	in := new(big.Int).SetUint64(quoteIn)
+
+	switch pool.CurveType {
+	case LAUNCHPAD_CURVE_FIXED_PRICE:
+		if pool.VirtualQuote == 0 {
+			return 0, fmt.Errorf("fixed price curve has zero virtual quote")
+		}
+		out := in.Mul(in, bigU64(pool.VirtualBase))
+		return toUint64(out.Quo(out, bigU64(pool.VirtualQuote)))
+	case LAUNCHPAD_CURVE_LINEAR_PRICE:
+		if pool.VirtualBase == 0 {
+			return 0, fmt.Errorf("linear price curve has zero slope")
+		}
+		// x' = sqrt(2 * q' * 2^64 / a)
+		newQuote := in.Add(in, bigU64(pool.RealQuote))
+		newBase := newQuote.Mul(newQuote, big.NewInt(2))
+		newBase.Mul(newBase, launchpadQ64)
+		newBase.Quo(newBase, bigU64(pool.VirtualBase))
+		newBase.Sqrt(newBase)
+		return toUint64(newBase.Sub(newBase, bigU64(pool.RealBase)))
+	default:
+		inputReserve, outputReserve := launchpadConstantProductReserves(pool, true)
+		return constantProductOut(in, inputReserve, outputReserve)
+	}
+}
+
+// launchpadCurveBuyExactOut returns the quote tokens (before fees) needed to buy baseOut
+func launchpadCurveBuyExactOut(pool *LaunchpadPoolState, baseOut uint64) (uint64, error) {
+	out := new(big.Int).SetUint64(baseOut)
+
+	switch pool.CurveType {
+	case LAUNCHPAD_CURVE_FIXED_PRICE:
+		if pool.VirtualBase == 0 {
+			return 0, fmt.Errorf("fixed price curve has zero virtual base")
+		}
+		return toUint64(ceilDiv(out.Mul(out, bigU64(pool.VirtualQuote)), bigU64(pool.VirtualBase)))
+	case LAUNCHPAD_CURVE_LINEAR_PRICE:
+		// q' = ceil(a * x'^2 / 2^65)
+		newBase := out.Add(out, bigU64(pool.RealBase))
+		newQuote := new(big.Int).Mul(newBase, newBase)
+		newQuote.Mul(newQuote, bigU64(pool.VirtualBase))
+		newQuote = ceilDiv(newQuote, new(big.Int).Lsh(launchpadQ64, 1))
+		return toUint64(newQuote.Sub(newQuote, bigU64(pool.RealQuote)))
+	default:
+		inputReserve, outputReserve := launchpadConstantProductReserves(pool, true)
+		return constantProductIn(out, inputReserve, outputReserve)
+	}
+}
+
+// launchpadCurveSellExactIn returns the quote tokens (before fees) received for selling baseIn
+func launchpadCurveSellExactIn(pool *LaunchpadPoolState, baseIn uint64) (uint64, error) {
+	in := new(big.Int).SetUint64(baseIn)
+
+	switch pool.CurveType {
+	case LAUNCHPAD_CURVE_FIXED_PRICE:
+		if pool.VirtualBase == 0 {
+			return 0, fmt.Errorf("fixed price curve has zero virtual base")
+		}
+		out := in.Mul(in, bigU64(pool.VirtualQuote))
+		return toUint64(out.Quo(out, bigU64(pool.VirtualBase)))
+	case LAUNCHPAD_CURVE_LINEAR_PRICE:
+		// q' = ceil(a * x'^2 / 2^65), out = q - q'
+		newBase := new(big.Int).Sub(bigU64(pool.RealBase), in)
+		newQuote := new(big.Int).Mul(newBase, newBase)
+		newQuote.Mul(newQuote, bigU64(pool.VirtualBase))
+		newQuote = ceilDiv(newQuote, new(big.Int).Lsh(launchpadQ64, 1))
+		return toUint64(newQuote.Sub(bigU64(pool.RealQuote), newQuote))
+	default:
+		inputReserve, outputReserve := launchpadConstantProductReserves(pool, false)
+		return constantProductOut(in, inputReserve, outputReserve)
+	}
+}
+
+// launchpadCurveSellExactOut returns the base tokens that must be sold to receive quoteOut before fees
+func launchpadCurveSellExactOut(pool *LaunchpadPoolState, quoteOut uint64) (uint64, error) {
+	out := new(big.Int).SetUint64(quoteOut)
+
+	switch pool.CurveType {
+	case LAUNCHPAD_CURVE_FIXED_PRICE:
+		if pool.VirtualQuote == 0 {
+			return 0, fmt.Errorf("fixed price curve has zero virtual quote")
+		}
+		return toUint64(ceilDiv(out.Mul(out, bigU64(pool.VirtualBase)), bigU64(pool.VirtualQuote)))
+	case LAUNCHPAD_CURVE_LINEAR_PRICE:
+		if pool.VirtualBase == 0 {
+			return 0, fmt.Errorf("linear price curve has zero slope")
+		}
+		// x' = sqrt(2 * q' * 2^64 / a), in = x - x'
+		newQuote := new(big.Int).Sub(bigU64(pool.RealQuote), out)
+		newBase := newQuote.Mul(newQuote, big.NewInt(2))
+		newBase.Mul(newBase, launchpadQ64)
+		newBase.Quo(newBase, bigU64(pool.VirtualBase))
+		newBase.Sqrt(newBase)
+		return toUint64(newBase.Sub(bigU64(pool.RealBase), newBase))
+	default:
+		inputReserve, outputReserve := launchpadConstantProductReserves(pool, false)
+		return constantProductIn(out, inputReserve, outputReserve)
+	}
+}
+
+// launchpadConstantProductReserves returns (input, output) reserves including the virtual liquidity
+func launchpadConstantProductReserves(pool *LaunchpadPoolState, isBuy bool) (*big.Int, *big.Int) {
+	quoteReserve := new(big.Int).Add(bigU64(pool.VirtualQuote), bigU64(pool.RealQuote))
+	baseReserve := new(big.Int).Sub(bigU64(pool.VirtualBase), bigU64(pool.RealBase))
+	if isBuy {
+		return quoteReserve, baseReserve
+	}
+	return baseReserve, quoteReserve
+}
+
+// constantProductOut returns floor(in * outputReserve / (inputReserve + in))
+func constantProductOut(in, inputReserve, outputReserve *big.Int) (uint64, error) {
+	if inputReserve.Sign() <= 0 || outputReserve.Sign() <= 0 {
+		return 0, ErrLaunchpadInsufficientReserve
+	}
+	numerator := new(big.Int).Mul(in, outputReserve)
+	denominator := new(big.Int).Add(inputReserve, in)
+	return toUint64(numerator.Quo(numerator, denominator))
+}
+
+// constantProductIn returns ceil(out * inputReserve / (outputReserve - out))
+func constantProductIn(out, inputReserve, outputReserve *big.Int) (uint64, error) {
+	if inputReserve.Sign() <= 0 || outputReserve.Cmp(out) <= 0 {
+		return 0, ErrLaunchpadInsufficientReserve
+	}
+	numerator := new(big.Int).Mul(out, inputReserve)
+	denominator := new(big.Int).Sub(outputReserve, out)
+	return toUint64(ceilDiv(numerator, denominator))
+}
+
+func toUint64(v *big.Int) (uint64, error) {
+	if v.Sign() < 0 {
+		return 0, ErrLaunchpadInsufficientReserve
+	}
+	if !v.IsUint64() {
+		return 0, fmt.Errorf("amount %s overflows u64", v.String())
+	}
+	return v.Uint64(), nil
+}
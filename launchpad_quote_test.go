@@ -0,0 +1,186 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+var testLaunchpadFees = LaunchpadFeeRates{
+	TradeFeeRate:    2500, // 0.25%
+	PlatformFeeRate: 10000,
+	CreatorFeeRate:  500,
+}
+
+func TestQuoteLaunchpadBuyExactInConstantProduct(t *testing.T) {
+	pool := sampleLaunchpadPoolState()
+
+	quote, err := QuoteLaunchpadBuyExactIn(pool, testLaunchpadFees, 1_000_000_000)
+	if err != nil {
+		t.Fatalf("Failed to quote buy: %v", err)
+	}
+
+	// 1 SOL pays 2_500_000 + 10_000_000 + 500_000 in fees
+	if quote.TotalFee() != 13_000_000 {
+		t.Errorf("Expected total fee 13000000, got %d", quote.TotalFee())
+	}
+
+	curveIn := uint64(1_000_000_000 - 13_000_000)
+	quoteReserve := pool.VirtualQuote + pool.RealQuote
+	baseReserve := pool.VirtualBase - pool.RealBase
+	expectedOut := uint64(float64(curveIn) * float64(baseReserve) / float64(quoteReserve+curveIn))
+	if diff := int64(quote.AmountOut) - int64(expectedOut); diff < -1000 || diff > 1000 {
+		t.Errorf("Expected amount out ~%d, got %d", expectedOut, quote.AmountOut)
+	}
+	if quote.PriceImpact <= 0 {
+		t.Errorf("Expected positive price impact for a buy, got %f", quote.PriceImpact)
+	}
+}
+
+func TestQuoteLaunchpadShareFee(t *testing.T) {
+	pool := sampleLaunchpadPoolState()
+	referral := testLaunchpadFees
+	referral.ShareFeeRate = 10000 // 1%
+
+	plain, err := QuoteLaunchpadBuyExactIn(pool, testLaunchpadFees, 1_000_000_000)
+	if err != nil {
+		t.Fatalf("Failed to quote buy: %v", err)
+	}
+	quote, err := QuoteLaunchpadBuyExactIn(pool, referral, 1_000_000_000)
+	if err != nil {
+		t.Fatalf("Failed to quote referral buy: %v", err)
+	}
+	if quote.ShareFee != 10_000_000 || quote.TotalFee() != 23_000_000 {
+		t.Errorf("Expected share fee 10000000 of 23000000 total, got %d of %d", quote.ShareFee, quote.TotalFee())
+	}
+	if quote.AmountOut >= plain.AmountOut {
+		t.Errorf("Expected the share fee to reduce the amount out below %d, got %d", plain.AmountOut, quote.AmountOut)
+	}
+
+	// Exact out grosses up for the share fee as well
+	plainSell, err := QuoteLaunchpadSellExactOut(pool, testLaunchpadFees, 1_000_000_000)
+	if err != nil {
+		t.Fatalf("Failed to quote sell: %v", err)
+	}
+	sell, err := QuoteLaunchpadSellExactOut(pool, referral, 1_000_000_000)
+	if err != nil {
+		t.Fatalf("Failed to quote referral sell: %v", err)
+	}
+	if sell.ShareFee == 0 || sell.AmountIn <= plainSell.AmountIn {
+		t.Errorf("Expected the share fee to raise the base sold above %d, got %d (share fee %d)", plainSell.AmountIn, sell.AmountIn, sell.ShareFee)
+	}
+
+	buy := NewBuyInstruction()
+	if err := quote.ApplyToBuy(buy, 100); err != nil {
+		t.Fatalf("Failed to apply buy quote: %v", err)
+	}
+	if buy.shareFeeRate != referral.ShareFeeRate {
+		t.Errorf("Expected the builder to send share fee rate %d, got %d", referral.ShareFeeRate, buy.shareFeeRate)
+	}
+}
+
+func TestQuoteLaunchpadExactInOutAgree(t *testing.T) {
+	curves := map[string]*LaunchpadPoolState{}
+
+	curves["constant"] = sampleLaunchpadPoolState()
+
+	fixed := sampleLaunchpadPoolState()
+	fixed.CurveType = LAUNCHPAD_CURVE_FIXED_PRICE
+	fixed.VirtualBase, fixed.VirtualQuote = 793_100_000_000_000, 85_000_000_000
+	curves["fixed"] = fixed
+
+	linear := sampleLaunchpadPoolState()
+	linear.CurveType = LAUNCHPAD_CURVE_LINEAR_PRICE
+	linear.VirtualBase = 5_000_000        // slope a in Q64
+	linear.RealBase = 300_000_000_000_000 // x
+	linear.RealQuote = uint64(float64(linear.VirtualBase) * float64(linear.RealBase) * float64(linear.RealBase) / (1 << 65))
+	curves["linear"] = linear
+
+	for name, pool := range curves {
+		t.Run(name, func(t *testing.T) {
+			buy, err := QuoteLaunchpadBuyExactIn(pool, testLaunchpadFees, 500_000_000)
+			if err != nil {
+				t.Fatalf("Buy exact in failed: %v", err)
+			}
+			buyOut, err := QuoteLaunchpadBuyExactOut(pool, testLaunchpadFees, buy.AmountOut)
+			if err != nil {
+				t.Fatalf("Buy exact out failed: %v", err)
+			}
+			if buyOut.AmountIn > buy.AmountIn {
+				t.Errorf("Buying %d base should cost at most %d, quoted %d", buy.AmountOut, buy.AmountIn, buyOut.AmountIn)
+			}
+
+			sell, err := QuoteLaunchpadSellExactIn(pool, testLaunchpadFees, buy.AmountOut)
+			if err != nil {
+				t.Fatalf("Sell exact in failed: %v", err)
+			}
+			if sell.AmountOut >= buy.AmountIn {
+				t.Errorf("Round trip should lose fees: paid %d, got back %d", buy.AmountIn, sell.AmountOut)
+			}
+			sellOut, err := QuoteLaunchpadSellExactOut(pool, testLaunchpadFees, sell.AmountOut)
+			if err != nil {
+				t.Fatalf("Sell exact out failed: %v", err)
+			}
+			if sellOut.AmountIn > sell.AmountIn+1 {
+				t.Errorf("Receiving %d quote should need at most %d base, quoted %d", sell.AmountOut, sell.AmountIn, sellOut.AmountIn)
+			}
+		})
+	}
+}
+
+func TestQuoteLaunchpadErrors(t *testing.T) {
+	pool := sampleLaunchpadPoolState()
+
+	if _, err := QuoteLaunchpadBuyExactIn(pool, testLaunchpadFees, 0); !errors.Is(err, ErrLaunchpadZeroAmount) {
+		t.Errorf("Expected ErrLaunchpadZeroAmount, got %v", err)
+	}
+	if _, err := QuoteLaunchpadBuyExactOut(pool, testLaunchpadFees, pool.RemainingBaseSell()+1); !errors.Is(err, ErrLaunchpadInsufficientReserve) {
+		t.Errorf("Expected ErrLaunchpadInsufficientReserve, got %v", err)
+	}
+	if _, err := QuoteLaunchpadSellExactIn(pool, testLaunchpadFees, pool.RealBase+1); !errors.Is(err, ErrLaunchpadInsufficientReserve) {
+		t.Errorf("Expected ErrLaunchpadInsufficientReserve, got %v", err)
+	}
+
+	migrated := sampleLaunchpadPoolState()
+	migrated.Status = LAUNCHPAD_STATUS_TRADE
+	if _, err := QuoteLaunchpadBuyExactIn(migrated, testLaunchpadFees, 1000); !errors.Is(err, ErrLaunchpadPoolNotTrading) {
+		t.Errorf("Expected ErrLaunchpadPoolNotTrading, got %v", err)
+	}
+}
+
+func TestLaunchpadQuoteFillsBuilders(t *testing.T) {
+	pool := sampleLaunchpadPoolState()
+
+	buyQuote, err := QuoteLaunchpadBuyExactOut(pool, testLaunchpadFees, 1_000_000_000)
+	if err != nil {
+		t.Fatalf("Failed to quote buy: %v", err)
+	}
+	buy := NewBuyInstruction()
+	if err := buyQuote.ApplyToBuy(buy, 100); err != nil {
+		t.Fatalf("Failed to apply buy quote: %v", err)
+	}
+	if !buy.exactOut || buy.amountOut != 1_000_000_000 {
+		t.Errorf("Expected buy_exact_out of 1000000000, got exact out %v amount %d", buy.exactOut, buy.amountOut)
+	}
+	if expected := applySlippageUp(buyQuote.AmountIn, 100); buy.maxAmountIn != expected {
+		t.Errorf("Expected max amount in %d, got %d", expected, buy.maxAmountIn)
+	}
+
+	sellQuote, err := QuoteLaunchpadSellExactIn(pool, testLaunchpadFees, 1_000_000_000)
+	if err != nil {
+		t.Fatalf("Failed to quote sell: %v", err)
+	}
+	sell := NewSellInstruction()
+	if err := sellQuote.ApplyToSell(sell, 50); err != nil {
+		t.Fatalf("Failed to apply sell quote: %v", err)
+	}
+	if sell.exactOut || sell.amountIn != 1_000_000_000 {
+		t.Errorf("Expected sell_exact_in of 1000000000, got exact out %v amount %d", sell.exactOut, sell.amountIn)
+	}
+	if expected := sellQuote.AmountOut * 9950 / 10000; sell.minimumAmountOut != expected {
+		t.Errorf("Expected minimum amount out %d, got %d", expected, sell.minimumAmountOut)
+	}
+
+	if err := sellQuote.ApplyToBuy(buy, 100); err == nil {
+		t.Errorf("Expected error applying a sell quote to a buy builder")
+	}
+}
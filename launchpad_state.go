@@ -0,0 +1,239 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Launchpad pool status values stored in PoolState.status
+const (
+	LAUNCHPAD_STATUS_FUND    = 0 // bonding curve is open for trading
+	LAUNCHPAD_STATUS_MIGRATE = 1 // fundraising target reached, waiting for migration
+	LAUNCHPAD_STATUS_TRADE   = 2 // liquidity migrated to AMM v4 or CP-Swap
+)
+
+// Launchpad migrate type values stored in PoolState.migrate_type
+const (
+	LAUNCHPAD_MIGRATE_TYPE_AMM    = 0
+	LAUNCHPAD_MIGRATE_TYPE_CPSWAP = 1
+)
+
+// Launchpad curve type values stored in GlobalConfig.curve_type
+const (
+	LAUNCHPAD_CURVE_CONSTANT_PRODUCT = 0
+	LAUNCHPAD_CURVE_FIXED_PRICE      = 1
+	LAUNCHPAD_CURVE_LINEAR_PRICE     = 2
+)
+
+var (
+	launchpadPoolStateDiscriminator    = anchorAccountDiscriminator("PoolState")
+	launchpadGlobalConfigDiscriminator = anchorAccountDiscriminator("GlobalConfig")
+)
+
+// LaunchpadVestingSchedule represents the vesting parameters stored on a Launchpad pool
+type LaunchpadVestingSchedule struct {
+	TotalLockedAmount    uint64
+	CliffPeriod          uint64
+	UnlockPeriod         uint64
+	StartTime            uint64
+	AllocatedShareAmount uint64
+}
+
+// LaunchpadPoolState represents a decoded Raydium Launchpad PoolState account
+type LaunchpadPoolState struct {
+	Address               solana.PublicKey
+	Epoch                 uint64
+	AuthBump              uint8
+	Status                uint8
+	BaseDecimals          uint8
+	QuoteDecimals         uint8
+	MigrateType           uint8
+	Supply                uint64
+	TotalBaseSell         uint64
+	VirtualBase           uint64
+	VirtualQuote          uint64
+	RealBase              uint64
+	RealQuote             uint64
+	TotalQuoteFundRaising uint64
+	QuoteProtocolFee      uint64
+	PlatformFee           uint64
+	MigrateFee            uint64
+	VestingSchedule       LaunchpadVestingSchedule
+	GlobalConfig          solana.PublicKey
+	PlatformConfig        solana.PublicKey
+	BaseMint              solana.PublicKey
+	QuoteMint             solana.PublicKey
+	BaseVault             solana.PublicKey
+	QuoteVault            solana.PublicKey
+	Creator               solana.PublicKey
+
+	// CurveType is not stored in the pool account; it comes from the pool's GlobalConfig
+	// and is filled by ApplyGlobalConfig. It defaults to the constant-product curve.
+	CurveType uint8
+}
+
+// LaunchpadGlobalConfig represents a decoded Raydium Launchpad GlobalConfig account
+type LaunchpadGlobalConfig struct {
+	Address               solana.PublicKey
+	Epoch                 uint64
+	CurveType             uint8
+	Index                 uint16
+	MigrateFee            uint64
+	TradeFeeRate          uint64
+	MaxShareFeeRate       uint64
+	MinBaseSupply         uint64
+	MaxLockRate           uint64
+	MinBaseSellRate       uint64
+	MinBaseMigrateRate    uint64
+	MinQuoteFundRaising   uint64
+	QuoteMint             solana.PublicKey
+	ProtocolFeeOwner      solana.PublicKey
+	MigrateFeeOwner       solana.PublicKey
+	MigrateToAmmWallet    solana.PublicKey
+	MigrateToCpSwapWallet solana.PublicKey
+}
+
+// DecodeLaunchpadPoolState decodes raw PoolState account data as returned by getAccountInfo
+func DecodeLaunchpadPoolState(data []byte) (*LaunchpadPoolState, error) {
+	r := newAccountReader(data)
+	r.anchorDiscriminator(launchpadPoolStateDiscriminator, "Launchpad PoolState")
+
+	pool := &LaunchpadPoolState{CurveType: LAUNCHPAD_CURVE_CONSTANT_PRODUCT}
+	pool.Epoch = r.u64()
+	pool.AuthBump = r.u8()
+	pool.Status = r.u8()
+	pool.BaseDecimals = r.u8()
+	pool.QuoteDecimals = r.u8()
+	pool.MigrateType = r.u8()
+	pool.Supply = r.u64()
+	pool.TotalBaseSell = r.u64()
+	pool.VirtualBase = r.u64()
+	pool.VirtualQuote = r.u64()
+	pool.RealBase = r.u64()
+	pool.RealQuote = r.u64()
+	pool.TotalQuoteFundRaising = r.u64()
+	pool.QuoteProtocolFee = r.u64()
+	pool.PlatformFee = r.u64()
+	pool.MigrateFee = r.u64()
+	pool.VestingSchedule = LaunchpadVestingSchedule{
+		TotalLockedAmount:    r.u64(),
+		CliffPeriod:          r.u64(),
+		UnlockPeriod:         r.u64(),
+		StartTime:            r.u64(),
+		AllocatedShareAmount: r.u64(),
+	}
+	pool.GlobalConfig = r.pubkey()
+	pool.PlatformConfig = r.pubkey()
+	pool.BaseMint = r.pubkey()
+	pool.QuoteMint = r.pubkey()
+	pool.BaseVault = r.pubkey()
+	pool.QuoteVault = r.pubkey()
+	pool.Creator = r.pubkey()
+
+	if r.err != nil {
+		return nil, fmt.Errorf("failed to decode launchpad pool state: %w", r.err)
+	}
+	return pool, nil
+}
+
+// DecodeLaunchpadGlobalConfig decodes raw GlobalConfig account data
+func DecodeLaunchpadGlobalConfig(data []byte) (*LaunchpadGlobalConfig, error) {
+	r := newAccountReader(data)
+	r.anchorDiscriminator(launchpadGlobalConfigDiscriminator, "Launchpad GlobalConfig")
+
+	config := &LaunchpadGlobalConfig{}
+	config.Epoch = r.u64()
+	config.CurveType = r.u8()
+	config.Index = r.u16()
+	config.MigrateFee = r.u64()
+	config.TradeFeeRate = r.u64()
+	config.MaxShareFeeRate = r.u64()
+	config.MinBaseSupply = r.u64()
+	config.MaxLockRate = r.u64()
+	config.MinBaseSellRate = r.u64()
+	config.MinBaseMigrateRate = r.u64()
+	config.MinQuoteFundRaising = r.u64()
+	config.QuoteMint = r.pubkey()
+	config.ProtocolFeeOwner = r.pubkey()
+	config.MigrateFeeOwner = r.pubkey()
+	config.MigrateToAmmWallet = r.pubkey()
+	config.MigrateToCpSwapWallet = r.pubkey()
+
+	if r.err != nil {
+		return nil, fmt.Errorf("failed to decode launchpad global config: %w", r.err)
+	}
+	return config, nil
+}
+
+// ApplyGlobalConfig copies the curve type from the pool's global config onto the pool state
+func (p *LaunchpadPoolState) ApplyGlobalConfig(config *LaunchpadGlobalConfig) *LaunchpadPoolState {
+	if config != nil {
+		p.CurveType = config.CurveType
+	}
+	return p
+}
+
+// IsTrading reports whether the bonding curve still accepts buys and sells
+func (p *LaunchpadPoolState) IsTrading() bool {
+	return p.Status == LAUNCHPAD_STATUS_FUND
+}
+
+// IsMigrated reports whether the pool's liquidity has moved to AMM v4 or CP-Swap
+func (p *LaunchpadPoolState) IsMigrated() bool {
+	return p.Status == LAUNCHPAD_STATUS_TRADE
+}
+
+// Progress returns graduation progress in [0, 1]: the share of TotalBaseSell already sold
+func (p *LaunchpadPoolState) Progress() float64 {
+	if p.TotalBaseSell == 0 {
+		return 0
+	}
+	progress := float64(p.RealBase) / float64(p.TotalBaseSell)
+	return math.Min(progress, 1)
+}
+
+// RemainingBaseSell returns how many base tokens can still be bought before migration
+func (p *LaunchpadPoolState) RemainingBaseSell() uint64 {
+	if p.RealBase >= p.TotalBaseSell {
+		return 0
+	}
+	return p.TotalBaseSell - p.RealBase
+}
+
+// Price returns the current spot price in quote tokens per base token, adjusted for decimals
+func (p *LaunchpadPoolState) Price() float64 {
+	return p.rawPrice() * math.Pow10(int(p.BaseDecimals)-int(p.QuoteDecimals))
+}
+
+// rawPrice returns the spot price in raw quote units per raw base unit
+func (p *LaunchpadPoolState) rawPrice() float64 {
+	price := new(big.Float)
+
+	switch p.CurveType {
+	case LAUNCHPAD_CURVE_FIXED_PRICE:
+		if p.VirtualBase == 0 {
+			return 0
+		}
+		price.Quo(new(big.Float).SetUint64(p.VirtualQuote), new(big.Float).SetUint64(p.VirtualBase))
+	case LAUNCHPAD_CURVE_LINEAR_PRICE:
+		// price = a * x / 2^64 where a is stored in VirtualBase and x is the base sold so far
+		price.Mul(new(big.Float).SetUint64(p.VirtualBase), new(big.Float).SetUint64(p.RealBase))
+		price.Quo(price, new(big.Float).SetInt(launchpadQ64))
+	default:
+		if p.VirtualBase <= p.RealBase {
+			return 0
+		}
+		quoteReserve := new(big.Float).SetUint64(p.VirtualQuote)
+		quoteReserve.Add(quoteReserve, new(big.Float).SetUint64(p.RealQuote))
+		price.Quo(quoteReserve, new(big.Float).SetUint64(p.VirtualBase-p.RealBase))
+	}
+
+	value, _ := price.Float64()
+	return value
+}
+
+// launchpadQ64 is the 2^64 fixed-point scale used by the linear price curve
+var launchpadQ64 = new(big.Int).Lsh(big.NewInt(1), 64)
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// encodeLaunchpadPoolState serialises a pool state using the on-chain PoolState layout
+func encodeLaunchpadPoolState(pool *LaunchpadPoolState) []byte {
+	data := make([]byte, 0, 429)
+	u64 := func(v uint64) { data = binary.LittleEndian.AppendUint64(data, v) }
+
+	data = append(data, launchpadPoolStateDiscriminator[:]...)
+	u64(pool.Epoch)
+	data = append(data, pool.AuthBump, pool.Status, pool.BaseDecimals, pool.QuoteDecimals, pool.MigrateType)
+	for _, v := range []uint64{
+		pool.Supply, pool.TotalBaseSell, pool.VirtualBase, pool.VirtualQuote, pool.RealBase, pool.RealQuote,
+		pool.TotalQuoteFundRaising, pool.QuoteProtocolFee, pool.PlatformFee, pool.MigrateFee,
+		pool.VestingSchedule.TotalLockedAmount, pool.VestingSchedule.CliffPeriod, pool.VestingSchedule.UnlockPeriod,
+		pool.VestingSchedule.StartTime, pool.VestingSchedule.AllocatedShareAmount,
+	} {
+		u64(v)
+	}
+	for _, key := range []solana.PublicKey{
+		pool.GlobalConfig, pool.PlatformConfig, pool.BaseMint, pool.QuoteMint,
+		pool.BaseVault, pool.QuoteVault, pool.Creator,
+	} {
+		data = append(data, key[:]...)
+	}
+	// Trailing padding present on-chain
+	return append(data, make([]byte, 64)...)
+}
+
+func sampleLaunchpadPoolState() *LaunchpadPoolState {
+	return &LaunchpadPoolState{
+		Epoch:                 785,
+		AuthBump:              255,
+		Status:                LAUNCHPAD_STATUS_FUND,
+		BaseDecimals:          6,
+		QuoteDecimals:         9,
+		MigrateType:           LAUNCHPAD_MIGRATE_TYPE_CPSWAP,
+		Supply:                1_000_000_000_000_000,
+		TotalBaseSell:         793_100_000_000_000,
+		VirtualBase:           1_073_025_605_596_382,
+		VirtualQuote:          30_000_852_951,
+		RealBase:              396_550_000_000_000,
+		RealQuote:             42_000_000_000,
+		TotalQuoteFundRaising: 85_000_000_000,
+		GlobalConfig:          solana.MustPublicKeyFromBase58("6s1xP3hpbAfFoNtUNF8mfHsjr2Bd97JxFJRWLbL6aHuX"),
+		PlatformConfig:        solana.MustPublicKeyFromBase58("FfYek5vEz23cMkWsdJwG2oa6EphsvXSHrGpdALN4g6W1"),
+		BaseMint:              solana.MustPublicKeyFromBase58("8pf71rxkus6HVhNa9ERdJ571wfPa1a8QKKMsxGkDbonk"),
+		QuoteMint:             solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112"),
+		BaseVault:             solana.MustPublicKeyFromBase58("7q1ZvFbhCgRVyNBZQKCxGb7VHG3TdWDDJrFxFvELMkwS"),
+		QuoteVault:            solana.MustPublicKeyFromBase58("9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM"),
+		Creator:               solana.MustPublicKeyFromBase58("DcyrgE2gusF35moZDMVnjED7jfXBuQeJgjG2oEgocYWd"),
+		CurveType:             LAUNCHPAD_CURVE_CONSTANT_PRODUCT,
+	}
+}
+
+func TestDecodeLaunchpadPoolState(t *testing.T) {
+	expected := sampleLaunchpadPoolState()
+
+	pool, err := DecodeLaunchpadPoolState(encodeLaunchpadPoolState(expected))
+	if err != nil {
+		t.Fatalf("Failed to decode pool state: %v", err)
+	}
+
+	if *pool != *expected {
+		t.Errorf("Decoded pool state mismatch:\n got  %+v\n want %+v", *pool, *expected)
+	}
+
+	if progress := pool.Progress(); math.Abs(progress-0.5) > 1e-9 {
+		t.Errorf("Expected progress 0.5, got %f", progress)
+	}
+	if !pool.IsTrading() || pool.IsMigrated() {
+		t.Errorf("Expected pool to be trading and not migrated")
+	}
+
+	// (30.000852951 + 42) SOL / (1073025605.596382 - 396550000) tokens
+	expectedPrice := (30.000852951 + 42) / (1073025605.596382 - 396550000)
+	if price := pool.Price(); math.Abs(price-expectedPrice)/expectedPrice > 1e-9 {
+		t.Errorf("Expected price %g, got %g", expectedPrice, price)
+	}
+}
+
+func TestDecodeLaunchpadPoolStateErrors(t *testing.T) {
+	data := encodeLaunchpadPoolState(sampleLaunchpadPoolState())
+
+	if _, err := DecodeLaunchpadPoolState(data[:100]); err == nil {
+		t.Errorf("Expected error for truncated pool state")
+	}
+
+	corrupted := append([]byte{}, data...)
+	corrupted[0] ^= 0xff
+	if _, err := DecodeLaunchpadPoolState(corrupted); err == nil {
+		t.Errorf("Expected error for wrong discriminator")
+	}
+}
+
+// encodeLaunchpadGlobalConfig serialises a global config using the on-chain GlobalConfig layout
+func encodeLaunchpadGlobalConfig(config *LaunchpadGlobalConfig) []byte {
+	data := append([]byte{}, launchpadGlobalConfigDiscriminator[:]...)
+	data = binary.LittleEndian.AppendUint64(data, config.Epoch)
+	data = append(data, config.CurveType)
+	data = binary.LittleEndian.AppendUint16(data, config.Index)
+	for _, v := range []uint64{
+		config.MigrateFee, config.TradeFeeRate, config.MaxShareFeeRate, config.MinBaseSupply, config.MaxLockRate,
+		config.MinBaseSellRate, config.MinBaseMigrateRate, config.MinQuoteFundRaising,
+	} {
+		data = binary.LittleEndian.AppendUint64(data, v)
+	}
+	for _, key := range []solana.PublicKey{
+		config.QuoteMint, config.ProtocolFeeOwner, config.MigrateFeeOwner,
+		config.MigrateToAmmWallet, config.MigrateToCpSwapWallet,
+	} {
+		data = append(data, key[:]...)
+	}
+	// Trailing padding present on-chain
+	return append(data, make([]byte, 128)...)
+}
+
+func TestDecodeLaunchpadGlobalConfig(t *testing.T) {
+	owners := uniqueAccounts(4)
+	expected := &LaunchpadGlobalConfig{
+		Epoch:                 785,
+		CurveType:             LAUNCHPAD_CURVE_LINEAR_PRICE,
+		Index:                 3,
+		MigrateFee:            15_000_000,
+		TradeFeeRate:          2_500,
+		MaxShareFeeRate:       10_000,
+		MinBaseSupply:         10_000_000,
+		MaxLockRate:           300_000,
+		MinBaseSellRate:       200_000,
+		MinBaseMigrateRate:    200_000,
+		MinQuoteFundRaising:   30_000_000_000,
+		QuoteMint:             solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112"),
+		ProtocolFeeOwner:      owners[0],
+		MigrateFeeOwner:       owners[1],
+		MigrateToAmmWallet:    owners[2],
+		MigrateToCpSwapWallet: owners[3],
+	}
+	data := encodeLaunchpadGlobalConfig(expected)
+
+	config, err := DecodeLaunchpadGlobalConfig(data)
+	if err != nil {
+		t.Fatalf("Failed to decode global config: %v", err)
+	}
+	if *config != *expected {
+		t.Errorf("Decoded global config mismatch:\n got  %+v\n want %+v", *config, *expected)
+	}
+	if pool := sampleLaunchpadPoolState().ApplyGlobalConfig(config); pool.CurveType != LAUNCHPAD_CURVE_LINEAR_PRICE {
+		t.Errorf("Expected the pool to take the linear curve, got %d", pool.CurveType)
+	}
+
+	if _, err := DecodeLaunchpadGlobalConfig(data[:100]); err == nil {
+		t.Errorf("Expected error for truncated global config")
+	}
+	corrupted := append([]byte{}, data...)
+	corrupted[0] ^= 0xff
+	if _, err := DecodeLaunchpadGlobalConfig(corrupted); err == nil {
+		t.Errorf("Expected error for wrong discriminator")
+	}
+	// A pool state is not a global config
+	if _, err := DecodeLaunchpadGlobalConfig(encodeLaunchpadPoolState(sampleLaunchpadPoolState())); err == nil {
+		t.Errorf("Expected error for a pool state account")
+	}
+}
+
+func TestLaunchpadPoolStateCurvePrices(t *testing.T) {
+	fixed := sampleLaunchpadPoolState().ApplyGlobalConfig(&LaunchpadGlobalConfig{CurveType: LAUNCHPAD_CURVE_FIXED_PRICE})
+	fixed.BaseDecimals, fixed.QuoteDecimals = 9, 9
+	fixed.VirtualBase, fixed.VirtualQuote = 4_000, 1_000
+	if price := fixed.Price(); price != 0.25 {
+		t.Errorf("Expected fixed price 0.25, got %g", price)
+	}
+
+	linear := sampleLaunchpadPoolState().ApplyGlobalConfig(&LaunchpadGlobalConfig{CurveType: LAUNCHPAD_CURVE_LINEAR_PRICE})
+	linear.BaseDecimals, linear.QuoteDecimals = 9, 9
+	linear.VirtualBase = 1 << 62 // a = 0.25 in Q64
+	linear.RealBase = 8
+	if price := linear.Price(); price != 2 {
+		t.Errorf("Expected linear price 2, got %g", price)
+	}
+}
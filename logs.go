@@ -0,0 +1,347 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Program log line prefixes written by the runtime
+const (
+	logPrefixProgram = "Program "
+	logPrefixLog     = "Program log: "
+	logPrefixData    = "Program data: "
+	logPrefixRayLog  = "ray_log: "
+)
+
+// AMM v4 ray_log types and swap directions
+const (
+	RAY_LOG_SWAP_BASE_IN  = 3
+	RAY_LOG_SWAP_BASE_OUT = 4
+
+	AMM_V4_DIRECTION_COIN_TO_PC = 1
+	AMM_V4_DIRECTION_PC_TO_COIN = 2
+)
+
+// Decoded event names reported in LogEvent.Name
+const (
+	EVENT_AMM_V4_SWAP_BASE_IN  = "amm_v4_swap_base_in"
+	EVENT_AMM_V4_SWAP_BASE_OUT = "amm_v4_swap_base_out"
+	EVENT_CPSWAP_SWAP          = "cpswap_swap"
+	EVENT_LAUNCHPAD_TRADE      = "launchpad_trade"
+)
+
+var (
+	// anchorEventInstructionTag prefixes the self-CPI data of events emitted with emit_cpi!
+	anchorEventInstructionTag = [8]byte{0xe4, 0x45, 0xa5, 0x2e, 0x51, 0xcb, 0x9a, 0x1d}
+
+	cpSwapSwapEventDiscriminator     = anchorEventDiscriminator("SwapEvent")
+	launchpadTradeEventDiscriminator = anchorEventDiscriminator("TradeEvent")
+)
+
+// ProgramInvocation is one program invocation reconstructed from transaction logs
+type ProgramInvocation struct {
+	ProgramID solana.PublicKey
+	// Depth is 1 for top-level instructions and grows with each CPI
+	Depth int
+	// Logs holds the "Program log:" messages and Data the decoded "Program data:" payloads
+	Logs []string
+	Data [][]byte
+	// ComputeUnits is the compute consumed by the invocation, including its CPIs
+	ComputeUnits uint64
+	Failed       bool
+	// Error is the runtime's failure message, e.g. "custom program error: 0x1e"
+	Error string
+}
+
+// CustomErrorCode returns the code of a "custom program error" failure
+func (p *ProgramInvocation) CustomErrorCode() (uint32, bool) {
+	const prefix = "custom program error: 0x"
+	if !strings.HasPrefix(p.Error, prefix) {
+		return 0, false
+	}
+	code, err := strconv.ParseUint(strings.TrimPrefix(p.Error, prefix), 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	return uint32(code), true
+}
+
+// ParseProgramLogs reconstructs program invocations, in the order they started, from the
+// log messages of a transaction or simulation. Lines it does not recognise are ignored.
+func ParseProgramLogs(logs []string) []ProgramInvocation {
+	var invocations []ProgramInvocation
+	// stack holds indexes into invocations of the programs currently executing
+	var stack []int
+	current := func() *ProgramInvocation {
+		if len(stack) == 0 {
+			return nil
+		}
+		return &invocations[stack[len(stack)-1]]
+	}
+	pop := func() {
+		if len(stack) > 0 {
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	for _, line := range logs {
+		switch {
+		case strings.HasPrefix(line, logPrefixLog):
+			if invocation := current(); invocation != nil {
+				invocation.Logs = append(invocation.Logs, strings.TrimPrefix(line, logPrefixLog))
+			}
+		case strings.HasPrefix(line, logPrefixData):
+			invocation := current()
+			if invocation == nil {
+				continue
+			}
+			// Anchor emits one base64 payload; other programs may emit several, space-separated
+			for _, field := range strings.Fields(strings.TrimPrefix(line, logPrefixData)) {
+				if data, err := base64.StdEncoding.DecodeString(field); err == nil {
+					invocation.Data = append(invocation.Data, data)
+				}
+			}
+		case strings.HasPrefix(line, logPrefixProgram):
+			fields := strings.Fields(strings.TrimPrefix(line, logPrefixProgram))
+			if len(fields) < 2 {
+				continue
+			}
+			programID, err := solana.PublicKeyFromBase58(fields[0])
+			if err != nil {
+				continue
+			}
+			switch {
+			case fields[1] == "invoke" && len(fields) == 3:
+				depth, _ := strconv.Atoi(strings.Trim(fields[2], "[]"))
+				invocations = append(invocations, ProgramInvocation{ProgramID: programID, Depth: depth})
+				stack = append(stack, len(invocations)-1)
+			case fields[1] == "consumed" && len(fields) >= 3:
+				if invocation := current(); invocation != nil && invocation.ProgramID.Equals(programID) {
+					invocation.ComputeUnits, _ = strconv.ParseUint(fields[2], 10, 64)
+				}
+			case fields[1] == "success":
+				pop()
+			case fields[1] == "failed:":
+				if invocation := current(); invocation != nil && invocation.ProgramID.Equals(programID) {
+					invocation.Failed = true
+					invocation.Error = strings.TrimPrefix(line, logPrefixProgram+fields[0]+" failed: ")
+				}
+				pop()
+			}
+		}
+	}
+	return invocations
+}
+
+// LogEvent is a Raydium swap or trade decoded from program logs
+type LogEvent struct {
+	ProgramID solana.PublicKey
+	Name      string
+	Pool      solana.PublicKey
+	AmountIn  uint64
+	AmountOut uint64
+	// Fee is the fee reported by the event itself, in the input token; zero when not reported
+	Fee uint64
+	// Event holds the decoded *AmmV4SwapLog, *CpSwapSwapEvent or *LaunchpadTradeEvent
+	Event interface{}
+}
+
+// AmmV4SwapLog is the ray_log an AMM v4 swap writes. For swap base in, Amount is the exact
+// input and Limit the minimum output; for swap base out they are the exact output and
+// maximum input. Result is the output or input amount actually computed.
+type AmmV4SwapLog struct {
+	LogType    uint8
+	Amount     uint64
+	Limit      uint64
+	Direction  uint64
+	UserSource uint64
+	PoolCoin   uint64
+	PoolPc     uint64
+	Result     uint64
+}
+
+// CpSwapSwapEvent is the Anchor SwapEvent CP-Swap emits for every swap
+type CpSwapSwapEvent struct {
+	PoolID            solana.PublicKey
+	InputVaultBefore  uint64
+	OutputVaultBefore uint64
+	InputAmount       uint64
+	OutputAmount      uint64
+	InputTransferFee  uint64
+	OutputTransferFee uint64
+	BaseInput         bool
+}
+
+// LaunchpadTradeEvent holds the leading, version-stable fields of the Launchpad TradeEvent
+type LaunchpadTradeEvent struct {
+	PoolState       solana.PublicKey
+	TotalBaseSell   uint64
+	VirtualBase     uint64
+	VirtualQuote    uint64
+	RealBaseBefore  uint64
+	RealQuoteBefore uint64
+	RealBaseAfter   uint64
+	RealQuoteAfter  uint64
+	AmountIn        uint64
+	AmountOut       uint64
+	ProtocolFee     uint64
+	PlatformFee     uint64
+}
+
+// IsBuy reports whether the trade bought base tokens from the curve. The real base
+// reserve counts the base tokens already sold, so a buy raises it and a sell lowers it.
+func (e *LaunchpadTradeEvent) IsBuy() bool {
+	return e.RealBaseAfter > e.RealBaseBefore
+}
+
+// DecodeLogEvents decodes the Raydium swap and trade events in a transaction's logs, in
+// execution order. Events whose data cannot be decoded are reported as errors alongside
+// the events that could.
+func DecodeLogEvents(logs []string) ([]LogEvent, error) {
+	var events []LogEvent
+	var errs []string
+	for _, invocation := range ParseProgramLogs(logs) {
+		switch {
+		case invocation.ProgramID.Equals(RaydiumV4ProgramID):
+			for _, line := range invocation.Logs {
+				if !strings.HasPrefix(line, logPrefixRayLog) {
+					continue
+				}
+				event, err := decodeRayLog(strings.TrimPrefix(line, logPrefixRayLog))
+				if err != nil {
+					errs = append(errs, err.Error())
+				} else if event != nil {
+					events = append(events, *event)
+				}
+			}
+		case invocation.ProgramID.Equals(RaydiumCpSwapProgramID):
+			for _, data := range invocation.Data {
+				if event, err := decodeCpSwapSwapEvent(data); err != nil {
+					errs = append(errs, err.Error())
+				} else if event != nil {
+					events = append(events, *event)
+				}
+			}
+		case invocation.ProgramID.Equals(RaydiumLaunchpadV1ProgramID):
+			for _, data := range invocation.Data {
+				if event, err := DecodeLaunchpadTradeEvent(data); err != nil {
+					errs = append(errs, err.Error())
+				} else if event != nil {
+					events = append(events, *event)
+				}
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return events, fmt.Errorf("failed to decode events: %s", strings.Join(errs, "; "))
+	}
+	return events, nil
+}
+
+// decodeRayLog decodes an AMM v4 ray_log; logs other than swaps return nil
+func decodeRayLog(encoded string) (*LogEvent, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ray_log: %w", err)
+	}
+	if len(data) == 0 || (data[0] != RAY_LOG_SWAP_BASE_IN && data[0] != RAY_LOG_SWAP_BASE_OUT) {
+		return nil, nil
+	}
+
+	r := newAccountReader(data)
+	swapLog := &AmmV4SwapLog{
+		LogType:    r.u8(),
+		Amount:     r.u64(),
+		Limit:      r.u64(),
+		Direction:  r.u64(),
+		UserSource: r.u64(),
+		PoolCoin:   r.u64(),
+		PoolPc:     r.u64(),
+		Result:     r.u64(),
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("invalid ray_log swap: %w", r.err)
+	}
+
+	event := &LogEvent{ProgramID: RaydiumV4ProgramID, Event: swapLog}
+	if swapLog.LogType == RAY_LOG_SWAP_BASE_IN {
+		event.Name = EVENT_AMM_V4_SWAP_BASE_IN
+		event.AmountIn, event.AmountOut = swapLog.Amount, swapLog.Result
+	} else {
+		event.Name = EVENT_AMM_V4_SWAP_BASE_OUT
+		event.AmountIn, event.AmountOut = swapLog.Result, swapLog.Amount
+	}
+	return event, nil
+}
+
+// decodeCpSwapSwapEvent decodes a CP-Swap SwapEvent; other events return nil
+func decodeCpSwapSwapEvent(data []byte) (*LogEvent, error) {
+	if len(data) < 8 || [8]byte(data[:8]) != cpSwapSwapEventDiscriminator {
+		return nil, nil
+	}
+	r := newAccountReader(data[8:])
+	event := &CpSwapSwapEvent{
+		PoolID:            r.pubkey(),
+		InputVaultBefore:  r.u64(),
+		OutputVaultBefore: r.u64(),
+		InputAmount:       r.u64(),
+		OutputAmount:      r.u64(),
+		InputTransferFee:  r.u64(),
+		OutputTransferFee: r.u64(),
+		BaseInput:         r.u8() != 0,
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("invalid CP-Swap SwapEvent: %w", r.err)
+	}
+	return &LogEvent{
+		ProgramID: RaydiumCpSwapProgramID,
+		Name:      EVENT_CPSWAP_SWAP,
+		Pool:      event.PoolID,
+		AmountIn:  event.InputAmount,
+		AmountOut: event.OutputAmount,
+		Event:     event,
+	}, nil
+}
+
+// DecodeLaunchpadTradeEvent decodes a Launchpad TradeEvent from "Program data" or from the
+// event CPI's instruction data; other events return nil
+func DecodeLaunchpadTradeEvent(data []byte) (*LogEvent, error) {
+	// emit_cpi! prefixes the event with the event instruction tag
+	if len(data) >= 16 && [8]byte(data[:8]) == anchorEventInstructionTag {
+		data = data[8:]
+	}
+	if len(data) < 8 || [8]byte(data[:8]) != launchpadTradeEventDiscriminator {
+		return nil, nil
+	}
+	r := newAccountReader(data[8:])
+	event := &LaunchpadTradeEvent{
+		PoolState:       r.pubkey(),
+		TotalBaseSell:   r.u64(),
+		VirtualBase:     r.u64(),
+		VirtualQuote:    r.u64(),
+		RealBaseBefore:  r.u64(),
+		RealQuoteBefore: r.u64(),
+		RealBaseAfter:   r.u64(),
+		RealQuoteAfter:  r.u64(),
+		AmountIn:        r.u64(),
+		AmountOut:       r.u64(),
+		ProtocolFee:     r.u64(),
+		PlatformFee:     r.u64(),
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("invalid Launchpad TradeEvent: %w", r.err)
+	}
+	return &LogEvent{
+		ProgramID: RaydiumLaunchpadV1ProgramID,
+		Name:      EVENT_LAUNCHPAD_TRADE,
+		Pool:      event.PoolState,
+		AmountIn:  event.AmountIn,
+		AmountOut: event.AmountOut,
+		Fee:       event.ProtocolFee + event.PlatformFee,
+		Event:     event,
+	}, nil
+}
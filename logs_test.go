@@ -0,0 +1,154 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+func appendU64s(data []byte, values ...uint64) []byte {
+	for _, v := range values {
+		data = binary.LittleEndian.AppendUint64(data, v)
+	}
+	return data
+}
+
+// launchpadTradeEventData encodes a buy TradeEvent, with the trailing fields newer program versions add
+func launchpadTradeEventData(pool solana.PublicKey, amountIn, amountOut uint64) []byte {
+	data := append(launchpadTradeEventDiscriminator[:], pool.Bytes()...)
+	data = appendU64s(data, 793_100_000_000_000, 1_073_025_605_596_382, 30_000_852_951,
+		500_000_000, 1_000_000, 500_000_000+amountOut, 1_000_000+amountIn, amountIn, amountOut, 250, 1_000, 0, 0)
+	return append(data, 0, 0, 1)
+}
+
+func TestParseProgramLogs(t *testing.T) {
+	logs := []string{
+		"Program ComputeBudget111111111111111111111111111111 invoke [1]",
+		"Program ComputeBudget111111111111111111111111111111 success",
+		"Program " + RaydiumV4ProgramID.String() + " invoke [1]",
+		"Program log: ray_log: AwEAAAAAAAAA",
+		"Program " + TokenProgramID.String() + " invoke [2]",
+		"Program log: Instruction: Transfer",
+		"Program " + TokenProgramID.String() + " consumed 4645 of 180000 compute units",
+		"Program " + TokenProgramID.String() + " success",
+		"Program data: aGVsbG8= d29ybGQ=",
+		"Program " + RaydiumV4ProgramID.String() + " consumed 25000 of 199850 compute units",
+		"Program " + RaydiumV4ProgramID.String() + " failed: custom program error: 0x1e",
+	}
+	invocations := ParseProgramLogs(logs)
+	if len(invocations) != 3 {
+		t.Fatalf("Expected 3 invocations, got %d", len(invocations))
+	}
+
+	amm, token := invocations[1], invocations[2]
+	if !amm.ProgramID.Equals(RaydiumV4ProgramID) || amm.Depth != 1 || !token.ProgramID.Equals(TokenProgramID) || token.Depth != 2 {
+		t.Errorf("Unexpected invocation tree: %+v", invocations)
+	}
+	if len(amm.Logs) != 1 || len(token.Logs) != 1 || token.Logs[0] != "Instruction: Transfer" {
+		t.Errorf("Logs were not attributed to the executing program: %q / %q", amm.Logs, token.Logs)
+	}
+	if len(amm.Data) != 2 || string(amm.Data[0]) != "hello" || string(amm.Data[1]) != "world" {
+		t.Errorf("Unexpected program data %q", amm.Data)
+	}
+	if amm.ComputeUnits != 25000 || token.ComputeUnits != 4645 {
+		t.Errorf("Unexpected compute units %d / %d", amm.ComputeUnits, token.ComputeUnits)
+	}
+	if !amm.Failed || token.Failed {
+		t.Errorf("Only the AMM invocation failed")
+	}
+	if code, ok := amm.CustomErrorCode(); !ok || code != 30 {
+		t.Errorf("Expected custom error 30, got %d (%v) from %q", code, ok, amm.Error)
+	}
+}
+
+func TestDecodeLogEvents(t *testing.T) {
+	// Anchor's EVENT_IX_TAG is the big-endian u64 of sha256("anchor:event")[:8], written little-endian
+	hash := sha256.Sum256([]byte("anchor:event"))
+	tag := binary.LittleEndian.AppendUint64(nil, binary.BigEndian.Uint64(hash[:8]))
+	if [8]byte(tag) != anchorEventInstructionTag {
+		t.Fatalf("Anchor event instruction tag mismatch: %x", tag)
+	}
+	pool := solana.NewWallet().PublicKey()
+
+	swapBaseIn := appendU64s([]byte{RAY_LOG_SWAP_BASE_IN}, 1_000_000, 900, AMM_V4_DIRECTION_PC_TO_COIN, 5_000_000, 10_000, 20_000, 955)
+	swapBaseOut := appendU64s([]byte{RAY_LOG_SWAP_BASE_OUT}, 500, 2_000_000, AMM_V4_DIRECTION_COIN_TO_PC, 5_000_000, 10_000, 20_000, 1_200_000)
+	deposit := appendU64s([]byte{1}, 1, 2, 3)
+
+	cpSwap := append(cpSwapSwapEventDiscriminator[:], pool.Bytes()...)
+	cpSwap = append(appendU64s(cpSwap, 1_000, 2_000, 300, 450, 0, 0), 1)
+
+	launchpad := launchpadTradeEventData(pool, 1_000_000, 35_000)
+
+	logs := []string{
+		"Program " + RaydiumV4ProgramID.String() + " invoke [1]",
+		"Program log: ray_log: " + base64.StdEncoding.EncodeToString(swapBaseIn),
+		"Program log: ray_log: " + base64.StdEncoding.EncodeToString(deposit),
+		"Program " + RaydiumV4ProgramID.String() + " success",
+		"Program " + RaydiumV4ProgramID.String() + " invoke [1]",
+		"Program log: ray_log: " + base64.StdEncoding.EncodeToString(swapBaseOut),
+		"Program " + RaydiumV4ProgramID.String() + " success",
+		"Program " + RaydiumCpSwapProgramID.String() + " invoke [1]",
+		"Program data: " + base64.StdEncoding.EncodeToString(cpSwap),
+		"Program " + RaydiumCpSwapProgramID.String() + " success",
+		"Program " + RaydiumLaunchpadV1ProgramID.String() + " invoke [1]",
+		"Program data: " + base64.StdEncoding.EncodeToString(launchpad),
+		"Program " + RaydiumLaunchpadV1ProgramID.String() + " success",
+	}
+	events, err := DecodeLogEvents(logs)
+	if err != nil {
+		t.Fatalf("Failed to decode events: %v", err)
+	}
+
+	expected := []struct {
+		name      string
+		amountIn  uint64
+		amountOut uint64
+		fee       uint64
+	}{
+		{EVENT_AMM_V4_SWAP_BASE_IN, 1_000_000, 955, 0},
+		{EVENT_AMM_V4_SWAP_BASE_OUT, 1_200_000, 500, 0},
+		{EVENT_CPSWAP_SWAP, 300, 450, 0},
+		{EVENT_LAUNCHPAD_TRADE, 1_000_000, 35_000, 1_250},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %d", len(expected), len(events))
+	}
+	for i, e := range expected {
+		got := events[i]
+		if got.Name != e.name || got.AmountIn != e.amountIn || got.AmountOut != e.amountOut || got.Fee != e.fee {
+			t.Errorf("Event %d: expected %+v, got %s %d in %d out %d fee", i, e, got.Name, got.AmountIn, got.AmountOut, got.Fee)
+		}
+	}
+	if swap := events[0].Event.(*AmmV4SwapLog); swap.Direction != AMM_V4_DIRECTION_PC_TO_COIN || swap.Limit != 900 {
+		t.Errorf("Unexpected ray_log fields %+v", swap)
+	}
+	if !events[2].Pool.Equals(pool) || !events[2].Event.(*CpSwapSwapEvent).BaseInput {
+		t.Errorf("Unexpected CP-Swap event %+v", events[2].Event)
+	}
+	if trade := events[3].Event.(*LaunchpadTradeEvent); !trade.IsBuy() || !events[3].Pool.Equals(pool) {
+		t.Errorf("Expected a Launchpad buy on %s, got %+v", pool, trade)
+	}
+	sell := &LaunchpadTradeEvent{RealBaseBefore: 500_000_000, RealBaseAfter: 465_000_000, RealQuoteBefore: 1_000_000, RealQuoteAfter: 900_000}
+	if sell.IsBuy() {
+		t.Errorf("Expected a trade that returns base tokens to the curve to be a sell")
+	}
+
+	// emit_cpi! delivers the same event as instruction data behind the event tag
+	cpiEvent, err := DecodeLaunchpadTradeEvent(append(anchorEventInstructionTag[:], launchpad...))
+	if err != nil || cpiEvent == nil || cpiEvent.AmountOut != 35_000 {
+		t.Errorf("Failed to decode emit_cpi! event: %+v, %v", cpiEvent, err)
+	}
+
+	truncated := []string{
+		"Program " + RaydiumLaunchpadV1ProgramID.String() + " invoke [1]",
+		"Program data: " + base64.StdEncoding.EncodeToString(launchpad[:40]),
+		"Program " + RaydiumLaunchpadV1ProgramID.String() + " success",
+	}
+	if _, err := DecodeLogEvents(truncated); err == nil || !strings.Contains(err.Error(), "TradeEvent") {
+		t.Errorf("Expected a TradeEvent decode error, got %v", err)
+	}
+}
@@ -5,9 +5,13 @@ import (
 	"encoding/base64"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
 	"strings"
 	"time"
 
@@ -19,9 +23,59 @@ import (
 const realTxSignature = "2N9VyxzFmHibuWy5HmJH52R6Hy6NZPw5iCdFc9X1JT4JBPCa4VZmxv3RhSvP9UfDdCdgDYvoeaN62v29toJNAWtD"
 
 func main() {
+	// sign, batch, backfill, stream and block write only their results to stdout so the output can be piped
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "sign":
+			if err := runSignCommand(os.Args[2:], os.Stdin, os.Stdout); err != nil {
+				fmt.Fprintf(os.Stderr, "sign: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "backfill":
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			err := runBackfillCommand(ctx, os.Args[2:], os.Stdout, NewRPCFetcherFromEnv())
+			stop()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "backfill: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "stream":
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			err := runStreamCommand(ctx, os.Args[2:], os.Stdout, NewRPCFetcherFromEnv().SetCommitment(rpc.CommitmentConfirmed))
+			stop()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "stream: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "block":
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			err := runBlockCommand(ctx, os.Args[2:], os.Stdout, NewRPCFetcherFromEnv())
+			stop()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "block: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "batch":
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			err := runBatchCommand(ctx, os.Args[2:], os.Stdin, os.Stdout, NewRPCFetcherFromEnv())
+			stop()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "batch: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	fmt.Println("Raydium Transaction Parser")
 	fmt.Println("==========================")
 
+	loadTokenListFromEnv()
+
 	// Check command line arguments
 	if len(os.Args) > 1 {
 		switch os.Args[1] {
@@ -45,16 +99,10 @@ func main() {
 
 	fmt.Println("Fetching real transaction from Solana mainnet...")
 
-	// Try multiple RPC endpoints in case one fails
-	rpcEndpoints := []string{
-		rpc.MainNetBeta_RPC,
-		"https://solana-api.projectserum.com",
-		"https://api.mainnet-beta.solana.com",
-		"https://solana-mainnet.g.alchemy.com/v2/demo",
-	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	fetcher := NewRPCFetcherFromEnv()
 
-	var txResp *rpc.GetTransactionResult
-	var client *rpc.Client
 	signature, err := solana.SignatureFromBase58(realTxSignature)
 	if err != nil {
 		log.Printf("Failed to parse signature: %v", err)
@@ -63,37 +111,7 @@ func main() {
 		return
 	}
 
-	// Try each RPC endpoint
-	for i, endpoint := range rpcEndpoints {
-		fmt.Printf("Trying RPC endpoint %d/%d: %s\n", i+1, len(rpcEndpoints), endpoint)
-		client = rpc.New(endpoint)
-
-		// Create a context with timeout for each request
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-
-		txResp, err = client.GetTransaction(
-			ctx,
-			signature,
-			&rpc.GetTransactionOpts{
-				MaxSupportedTransactionVersion: &[]uint64{0}[0], // Support version 0 transactions
-				Encoding:                       "base64",
-			},
-		)
-
-		cancel() // Clean up the context
-
-		if err == nil && txResp != nil && txResp.Transaction != nil {
-			fmt.Printf("✅ Successfully fetched transaction from endpoint %d\n", i+1)
-			break
-		}
-
-		log.Printf("❌ Endpoint %d failed: %v", i+1, err)
-		if i < len(rpcEndpoints)-1 {
-			fmt.Printf("Trying next endpoint...\n")
-		}
-	}
-	if err != nil || txResp == nil || txResp.Transaction == nil {
-		log.Printf("❌ All RPC endpoints failed. Last error: %v", err)
+	if !fetchAndParseTransaction(ctx, fetcher, signature) {
 		fmt.Println("Falling back to basic demo...")
 		demonstrateBasicFunctionality()
 		testWithRaydiumData()
@@ -101,33 +119,27 @@ func main() {
 		return
 	}
 
-	// Get the base64 encoded transaction
-	encoded := txResp.Transaction.GetBinary()
-	slot := txResp.Slot
-
-	fmt.Println("Parsing transaction...")
-
-	transaction, err := ParseTransactionWithSignature(base64.StdEncoding.EncodeToString(encoded), slot, signature)
-	if err != nil {
-		fmt.Printf("Failed to parse transaction: %v\n", err)
-		demonstrateBasicFunctionality()
-		return
-	}
-
-	fmt.Printf("Transaction successfully parsed!\n\n")
-
-	issues := ValidateTransaction(transaction)
-	PrintValidationResults(issues)
-	fmt.Println()
-
-	AnalyzeTransaction(transaction)
-	printTransaction(transaction)
-
 	// Optional: Load another transaction from a file
 	if _, err := os.Stat("sample_transaction.txt"); err == nil {
 		fmt.Println("\nLoading transaction from file...")
-		loadAndParseFromFile("sample_transaction.txt")
+		loadAndParseFromFile(ctx, fetcher, "sample_transaction.txt")
+	}
+}
+
+// loadTokenListFromEnv loads extra token metadata from the file named by RAYDIUM_TOKEN_LIST
+func loadTokenListFromEnv() {
+	path := os.Getenv("RAYDIUM_TOKEN_LIST")
+	if path == "" {
+		return
+	}
+	provider := NewCachedTokenInfoProvider(defaultTokens...)
+	count, err := provider.LoadFile(path)
+	if err != nil {
+		log.Printf("Failed to load token list %s: %v", path, err)
+		return
 	}
+	SetTokenInfoProvider(provider)
+	fmt.Printf("Loaded %d tokens from %s\n", count, path)
 }
 
 // printUsage prints the usage information
@@ -137,14 +149,443 @@ func printUsage() {
 	fmt.Println("Commands:")
 	fmt.Println("  test         Run all tests in offline mode")
 	fmt.Println("  offline      Run in offline mode (same as test)")
+	fmt.Println("  sign         Sign a transaction offline and print it as base64")
+	fmt.Println("  batch        Fetch and parse the signatures listed in a file or stdin")
+	fmt.Println("  backfill     Fetch and parse an address's transaction history, resumable")
+	fmt.Println("  stream       Follow Raydium programs over WebSocket until interrupted")
+	fmt.Println("  block        Parse every Raydium transaction in a block")
 	fmt.Println("  help         Show this help message")
 	fmt.Println("  (no args)    Fetch and parse a real transaction from Solana mainnet")
 	fmt.Println()
+	fmt.Println("Environment:")
+	fmt.Println("  RAYDIUM_TOKEN_LIST   JSON or CSV token list used for symbols and decimals")
+	fmt.Println("  SOLANA_WALLET_PATH   Keygen JSON keypair used by sign when -keypair is omitted")
+	fmt.Println("  SOLANA_PRIVATE_KEY   Base58 secret key used when SOLANA_WALLET_PATH is unset")
+	fmt.Println("  SOLANA_RPC_ENDPOINT  Comma separated RPC endpoints tried in order, replacing the public defaults")
+	fmt.Println("  SOLANA_WS_ENDPOINT   WebSocket endpoint the stream command subscribes to")
+	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  go run .                    # Fetch real transaction")
 	fmt.Println("  go run . test               # Run tests")
 	fmt.Println("  go run . offline            # Run in offline mode")
 	fmt.Println("  ./raydium-parser test       # Run tests (compiled)")
+	fmt.Println("  go run . sign -tx - -blockhash <hash> < unsigned.txt")
+	fmt.Println("  go run . sign -to <address> -lamports 1000 -blockhash <hash>")
+	fmt.Println("  go run . batch -workers 8 test_signatures.txt")
+	fmt.Println("  cat signatures.txt | go run . batch -json")
+	fmt.Println("  go run . backfill -address <pool> -from 2025-06-01 -checkpoint pool.json")
+	fmt.Println("  go run . stream -mode logs -json")
+	fmt.Println("  go run . block -slot 353025037")
+}
+
+// batchRecord is the JSON line batch and backfill write with -json for each signature
+type batchRecord struct {
+	Input       string       `json:"input"`
+	Transaction *Transaction `json:"transaction,omitempty"`
+	Error       string       `json:"error,omitempty"`
+	BlockTime   *time.Time   `json:"blockTime,omitempty"`
+}
+
+// commandParseOptions are the parse options of the commands that write results to stdout.
+// The parser's debug dumps go to stdout as well and would corrupt the results.
+func commandParseOptions(verify bool) []ParseOption {
+	opts := []ParseOption{WithoutParseLogging()}
+	if verify {
+		opts = append(opts, WithSignatureVerification())
+	}
+	return opts
+}
+
+// runBatchCommand fetches and parses the signatures listed in a file, or stdin when the
+// file is omitted or "-", and writes one line per signature in input order. Failed
+// signatures are reported in place; the command fails once all of them are written.
+func runBatchCommand(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer, fetcher TransactionFetcher) error {
+	flags := flag.NewFlagSet("batch", flag.ContinueOnError)
+	workers := flags.Int("workers", DEFAULT_BATCH_WORKERS, "signatures fetched concurrently")
+	asJSON := flags.Bool("json", false, "write one JSON object per signature instead of a summary line")
+	verify := flags.Bool("verify", true, "reject transactions whose signatures do not verify")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		return fmt.Errorf("expected at most one signature file, got %d", flags.NArg())
+	}
+
+	input := stdin
+	if name := flags.Arg(0); name != "" && name != "-" {
+		file, err := os.Open(name)
+		if err != nil {
+			return fmt.Errorf("failed to open signature file: %w", err)
+		}
+		defer file.Close()
+		input = file
+	}
+	entries, err := ReadSignatures(input)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no signatures to parse")
+	}
+
+	opts := commandParseOptions(*verify)
+	results := ParseBatch(ctx, fetcher, entries, *workers, opts...)
+
+	failed := 0
+	for i, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+		if err := writeBatchResult(stdout, *asJSON, i+1, result, time.Time{}); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d signatures failed", failed, len(results))
+	}
+	return nil
+}
+
+// writeBatchResult writes the n-th result as a summary line, or as a JSON line with asJSON
+func writeBatchResult(w io.Writer, asJSON bool, n int, result BatchResult, blockTime time.Time) error {
+	if asJSON {
+		record := batchRecord{Input: result.Input, Transaction: result.Transaction}
+		if result.Err != nil {
+			record.Error = result.Err.Error()
+		}
+		if !blockTime.IsZero() {
+			record.BlockTime = &blockTime
+		}
+		return json.NewEncoder(w).Encode(record)
+	}
+	if result.Err != nil {
+		_, err := fmt.Fprintf(w, "%d\t%s\tFAILED\t%v\n", n, result.Input, result.Err)
+		return err
+	}
+	tx := result.Transaction
+	_, err := fmt.Fprintf(w, "%d\t%s\tslot %d\t%d creates, %d trades (%d buys, %d sells), %d migrations\n",
+		n, tx.Signature, tx.Slot, len(tx.Create), len(tx.Trade), len(tx.TradeBuys), len(tx.TradeSells), len(tx.Migrate))
+	return err
+}
+
+// runBackfillCommand walks the history of -address from newest to oldest and writes one
+// line per transaction. With -checkpoint, an interrupted run resumes where it stopped.
+func runBackfillCommand(ctx context.Context, args []string, stdout io.Writer, source BackfillSource) error {
+	flags := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	address := flags.String("address", "", "pool or program address whose transactions are backfilled")
+	checkpoint := flags.String("checkpoint", "", "file progress is saved to and resumed from")
+	from := flags.String("from", "", "oldest block time to include, RFC 3339 or YYYY-MM-DD")
+	to := flags.String("to", "", "block time to stop before, RFC 3339 or YYYY-MM-DD")
+	before := flags.String("before", "", "signature to start below instead of the newest transaction")
+	until := flags.String("until", "", "signature to stop at, excluded")
+	pageSize := flags.Int("page-size", MAX_SIGNATURES_PAGE_SIZE, "signatures listed per request")
+	workers := flags.Int("workers", DEFAULT_BATCH_WORKERS, "transactions fetched concurrently")
+	asJSON := flags.Bool("json", false, "write one JSON object per transaction instead of a summary line")
+	verify := flags.Bool("verify", true, "reject transactions whose signatures do not verify")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	target, err := solana.PublicKeyFromBase58(*address)
+	if err != nil {
+		return fmt.Errorf("invalid -address: %w", err)
+	}
+	backfill := NewBackfill(source, target).
+		SetCheckpointFile(*checkpoint).
+		SetPageSize(*pageSize).
+		SetWorkers(*workers)
+
+	var fromTime, toTime time.Time
+	if fromTime, err = parseTimeFlag(*from); err != nil {
+		return fmt.Errorf("invalid -from: %w", err)
+	}
+	if toTime, err = parseTimeFlag(*to); err != nil {
+		return fmt.Errorf("invalid -to: %w", err)
+	}
+	backfill.SetTimeRange(fromTime, toTime)
+	if *before != "" {
+		signature, err := solana.SignatureFromBase58(*before)
+		if err != nil {
+			return fmt.Errorf("invalid -before: %w", err)
+		}
+		backfill.SetBefore(signature)
+	}
+	if *until != "" {
+		signature, err := solana.SignatureFromBase58(*until)
+		if err != nil {
+			return fmt.Errorf("invalid -until: %w", err)
+		}
+		backfill.SetUntil(signature)
+	}
+	backfill.SetParseOptions(commandParseOptions(*verify)...)
+
+	written, failed := 0, 0
+	handled, err := backfill.Run(ctx, func(item BackfillItem) error {
+		written++
+		if item.Err != nil {
+			failed++
+		}
+		return writeBatchResult(stdout, *asJSON, written, item.BatchResult, item.BlockTime)
+	})
+	if err != nil {
+		return fmt.Errorf("stopped after %d transactions: %w", handled, err)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d transactions failed", failed, handled)
+	}
+	return nil
+}
+
+// runStreamCommand follows Raydium programs over WebSocket and writes one line per
+// transaction until interrupted
+func runStreamCommand(ctx context.Context, args []string, stdout io.Writer, source BackfillSource) error {
+	flags := flag.NewFlagSet("stream", flag.ContinueOnError)
+	endpoint := flags.String("ws", streamEndpointFromEnv(), "WebSocket endpoint (default: "+SOLANA_WS_ENDPOINT_ENV+" or mainnet)")
+	mode := flags.String("mode", string(STREAM_MODE_LOGS), "subscription to use: logs or blocks")
+	programs := flags.String("programs", "", "comma separated program or pool addresses (default: Raydium AMM v4, CP-Swap, CLMM and Launchpad)")
+	asJSON := flags.Bool("json", false, "write one JSON object per transaction instead of a summary line")
+	verify := flags.Bool("verify", true, "reject transactions whose signatures do not verify")
+	workers := flags.Int("workers", DEFAULT_STREAM_FETCH_WORKERS, "logs mode transactions fetched concurrently")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var addresses []solana.PublicKey
+	for _, value := range strings.Split(*programs, ",") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		address, err := solana.PublicKeyFromBase58(value)
+		if err != nil {
+			return fmt.Errorf("invalid program %q: %w", value, err)
+		}
+		addresses = append(addresses, address)
+	}
+	stream := NewTransactionStream(*endpoint, source, addresses...).
+		SetMode(StreamMode(*mode)).
+		SetFetchWorkers(*workers).
+		SetParseOptions(commandParseOptions(*verify)...)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	out := make(chan *Transaction)
+	done := make(chan error, 1)
+	go func() {
+		done <- stream.Run(ctx, out)
+	}()
+
+	for n := 1; ; n++ {
+		select {
+		case tx := <-out:
+			result := BatchResult{Input: tx.Signature.String(), Signature: tx.Signature, Transaction: tx}
+			if err := writeBatchResult(stdout, *asJSON, n, result, time.Time{}); err != nil {
+				return err
+			}
+		case err := <-done:
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// blockRecord is the JSON object block -json writes
+type blockRecord struct {
+	Slot         uint64                   `json:"slot"`
+	ParentSlot   uint64                   `json:"parentSlot"`
+	Blockhash    solana.Hash              `json:"blockhash"`
+	BlockTime    *time.Time               `json:"blockTime,omitempty"`
+	BlockHeight  *uint64                  `json:"blockHeight,omitempty"`
+	Transactions []blockTransactionRecord `json:"transactions"`
+	Skipped      int                      `json:"skipped"`
+	Errors       []string                 `json:"errors,omitempty"`
+}
+
+type blockTransactionRecord struct {
+	Position    int          `json:"position"`
+	Transaction *Transaction `json:"transaction"`
+}
+
+// runBlockCommand parses the Raydium transactions of the block at -slot, fetched over RPC
+// or read from a saved getBlock response with -file
+func runBlockCommand(ctx context.Context, args []string, stdout io.Writer, fetcher BlockFetcher) error {
+	flags := flag.NewFlagSet("block", flag.ContinueOnError)
+	slot := flags.Uint64("slot", 0, "slot of the block")
+	file := flags.String("file", "", "getBlock JSON response to read instead of fetching the block")
+	asJSON := flags.Bool("json", false, "write the block as one JSON object instead of summary lines")
+	verify := flags.Bool("verify", true, "reject transactions whose signatures do not verify")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *slot == 0 {
+		return fmt.Errorf("-slot is required")
+	}
+
+	opts := commandParseOptions(*verify)
+	var block *ParsedBlock
+	var err error
+	if *file != "" {
+		data, readErr := os.ReadFile(*file)
+		if readErr != nil {
+			return fmt.Errorf("failed to read block file: %w", readErr)
+		}
+		block, err = ParseBlockJSON(data, *slot, opts...)
+	} else {
+		block, err = FetchAndParseBlock(ctx, fetcher, *slot, opts...)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		record := blockRecord{
+			Slot:         block.Slot,
+			ParentSlot:   block.ParentSlot,
+			Blockhash:    block.Blockhash,
+			BlockHeight:  block.BlockHeight,
+			Transactions: []blockTransactionRecord{},
+			Skipped:      block.Skipped,
+		}
+		if !block.BlockTime.IsZero() {
+			record.BlockTime = &block.BlockTime
+		}
+		for _, tx := range block.Transactions {
+			record.Transactions = append(record.Transactions, blockTransactionRecord{Position: tx.Position, Transaction: tx.Transaction})
+		}
+		for _, err := range block.Errors {
+			record.Errors = append(record.Errors, err.Error())
+		}
+		if err := json.NewEncoder(stdout).Encode(record); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(stdout, "Block %d (parent %d, %s): %d Raydium transactions, %d skipped\n",
+			block.Slot, block.ParentSlot, block.BlockTime.UTC().Format(time.RFC3339), len(block.Transactions), block.Skipped)
+		for _, tx := range block.Transactions {
+			result := BatchResult{Input: tx.Signature.String(), Signature: tx.Signature, Transaction: tx.Transaction}
+			if err := writeBatchResult(stdout, false, tx.Position, result, time.Time{}); err != nil {
+				return err
+			}
+		}
+		for _, err := range block.Errors {
+			fmt.Fprintf(stdout, "FAILED\t%v\n", err)
+		}
+	}
+	if len(block.Errors) > 0 {
+		return fmt.Errorf("%d transactions could not be parsed", len(block.Errors))
+	}
+	return nil
+}
+
+// parseTimeFlag accepts RFC 3339 timestamps and plain UTC dates; empty is the zero time
+func parseTimeFlag(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+// keypairFlags collects repeated -keypair values
+type keypairFlags []string
+
+func (k *keypairFlags) String() string { return strings.Join(*k, ",") }
+
+func (k *keypairFlags) Set(value string) error {
+	*k = append(*k, value)
+	return nil
+}
+
+// runSignCommand signs a transaction without contacting the network. It signs the
+// base64 transaction given by -tx ("-" reads stdin), or composes a SOL transfer from -to
+// and -lamports, and writes the signed transaction to stdout as base64.
+func runSignCommand(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("sign", flag.ContinueOnError)
+	var keypairs keypairFlags
+	flags.Var(&keypairs, "keypair", "keygen JSON file or base58 secret key; repeat for extra signers (default: environment)")
+	encoded := flags.String("tx", "", "base64 transaction to sign, or - to read it from stdin")
+	blockhash := flags.String("blockhash", "", "recent blockhash; replaces the transaction's blockhash when set")
+	to := flags.String("to", "", "recipient of a SOL transfer when -tx is not given")
+	lamports := flags.Uint64("lamports", 0, "lamports to transfer when -tx is not given")
+	computeUnitPrice := flags.Uint64("compute-unit-price", 0, "priority fee in micro-lamports per compute unit for a composed transfer")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var signers []Signer
+	for _, source := range keypairs {
+		signer, err := LoadSigner(source)
+		if err != nil {
+			return err
+		}
+		signers = append(signers, signer)
+	}
+	if len(signers) == 0 {
+		signer, err := LoadSignerFromEnv()
+		if err != nil {
+			return fmt.Errorf("no -keypair given: %w", err)
+		}
+		signers = append(signers, signer)
+	}
+
+	var recentBlockhash solana.Hash
+	if *blockhash != "" {
+		hash, err := solana.HashFromBase58(*blockhash)
+		if err != nil {
+			return fmt.Errorf("invalid blockhash: %w", err)
+		}
+		recentBlockhash = hash
+	}
+
+	var tx *solana.Transaction
+	switch {
+	case *encoded != "":
+		data := *encoded
+		if data == "-" {
+			raw, err := io.ReadAll(stdin)
+			if err != nil {
+				return fmt.Errorf("failed to read transaction: %w", err)
+			}
+			data = string(raw)
+		}
+		decoded, err := solana.TransactionFromBase64(strings.TrimSpace(data))
+		if err != nil {
+			return fmt.Errorf("failed to decode transaction: %w", err)
+		}
+		if !recentBlockhash.IsZero() {
+			decoded.Message.RecentBlockhash = recentBlockhash
+		}
+		tx = decoded
+	case *to != "":
+		recipient, err := solana.PublicKeyFromBase58(*to)
+		if err != nil {
+			return fmt.Errorf("invalid recipient: %w", err)
+		}
+		payer := signers[0].PublicKey()
+		tx, err = NewTransactionComposer(payer).
+			SetRecentBlockhash(recentBlockhash).
+			SetComputeUnitPrice(*computeUnitPrice).
+			AddInstruction(NewTransferInstruction(payer, recipient, *lamports)).
+			Build()
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("either -tx or -to is required")
+	}
+
+	if err := SignTransaction(tx, signers...); err != nil {
+		return err
+	}
+	out, err := tx.ToBase64()
+	if err != nil {
+		return fmt.Errorf("failed to encode transaction: %w", err)
+	}
+	_, err = fmt.Fprintln(stdout, out)
+	return err
 }
 
 // printTransaction prints the transaction details in a formatted way
@@ -164,6 +605,11 @@ func printTransaction(tx *Transaction) {
 		for i, create := range tx.Create {
 			fmt.Printf("  [%d] Token: %s, Pool: %s, Creator: %s\n",
 				i, create.TokenMint.String(), create.PoolAddress.String(), create.Creator.String())
+			if create.TokenName != "" {
+				fmt.Printf("      Name: %s, Symbol: %s, URI: %s, Supply: %s\n",
+					create.TokenName, create.TokenSymbol, create.TokenURI,
+					FormatTokenAmount(create.Supply, create.TokenDecimals))
+			}
 		}
 	}
 
@@ -182,6 +628,11 @@ func printTransaction(tx *Transaction) {
 			fmt.Printf("  [%d] From: %s, To: %s, Token: %s, Owner: %s\n",
 				i, migration.FromPool.String(), migration.ToPool.String(),
 				migration.Token.String(), migration.Owner.String())
+			if migration.Destination != "" {
+				fmt.Printf("      Destination: %s, Quote: %s, Base In: %d, Quote In: %d, LP: %s (%d %s)\n",
+					migration.Destination, migration.QuoteMint.String(), migration.BaseAmount,
+					migration.QuoteAmount, migration.LpMint.String(), migration.LpAmount, migration.LpHandling)
+			}
 		}
 	}
 
@@ -214,62 +665,11 @@ func printTransaction(tx *Transaction) {
 }
 
 // fetchAndParseTransaction fetches a transaction by signature and parses it
-func fetchAndParseTransaction(signature solana.Signature) bool {
-	// Try multiple RPC endpoints in case one fails
-	rpcEndpoints := []string{
-		rpc.MainNetBeta_RPC,
-		"https://solana-api.projectserum.com",
-		"https://api.mainnet-beta.solana.com",
-		"https://solana-mainnet.g.alchemy.com/v2/demo",
-	}
-
-	var txResp *rpc.GetTransactionResult
-	var err error
-
-	// Try each RPC endpoint
-	for i, endpoint := range rpcEndpoints {
-		fmt.Printf("Trying RPC endpoint %d/%d: %s\n", i+1, len(rpcEndpoints), endpoint)
-		client := rpc.New(endpoint)
-
-		// Create a context with timeout for each request
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-
-		txResp, err = client.GetTransaction(
-			ctx,
-			signature,
-			&rpc.GetTransactionOpts{
-				MaxSupportedTransactionVersion: &[]uint64{0}[0], // Support version 0 transactions
-				Encoding:                       "base64",
-			},
-		)
-
-		cancel() // Clean up the context
-
-		if err == nil && txResp != nil && txResp.Transaction != nil {
-			fmt.Printf("✅ Successfully fetched transaction from endpoint %d\n", i+1)
-			break
-		}
-
-		log.Printf("❌ Endpoint %d failed: %v", i+1, err)
-		if i < len(rpcEndpoints)-1 {
-			fmt.Printf("Trying next endpoint...\n")
-		}
-	}
-
-	if err != nil || txResp == nil || txResp.Transaction == nil {
-		log.Printf("❌ All RPC endpoints failed. Last error: %v", err)
-		return false
-	}
-
-	// Get the base64 encoded transaction
-	encoded := txResp.Transaction.GetBinary()
-	slot := txResp.Slot
-
-	fmt.Println("Parsing transaction...")
-
-	transaction, err := ParseTransactionWithSignature(base64.StdEncoding.EncodeToString(encoded), slot, signature)
+func fetchAndParseTransaction(ctx context.Context, fetcher TransactionFetcher, signature solana.Signature) bool {
+	fmt.Printf("Fetching transaction %s...\n", signature)
+	transaction, err := FetchAndParseTransaction(ctx, fetcher, signature, WithSignatureVerification())
 	if err != nil {
-		fmt.Printf("Failed to parse transaction: %v\n", err)
+		log.Printf("❌ %v", err)
 		return false
 	}
 
@@ -286,7 +686,7 @@ func fetchAndParseTransaction(signature solana.Signature) bool {
 }
 
 // loadAndParseFromFile loads a transaction from a file and parses it
-func loadAndParseFromFile(filename string) {
+func loadAndParseFromFile(ctx context.Context, fetcher TransactionFetcher, filename string) {
 	data, err := os.ReadFile(filename)
 	if err != nil {
 		log.Printf("Error reading file %s: %v", filename, err)
@@ -309,7 +709,7 @@ func loadAndParseFromFile(filename string) {
 		}
 
 		// Try to fetch the transaction using the same RPC logic as main
-		if !fetchAndParseTransaction(signature) {
+		if !fetchAndParseTransaction(ctx, fetcher, signature) {
 			log.Printf("Failed to fetch transaction with signature: %s", content)
 		}
 		return
@@ -571,18 +971,33 @@ func testWithMockRaydiumTransaction() {
 // testInstructionBuilders tests the instruction builder functionality
 func testInstructionBuilders() {
 	fmt.Println("Testing instruction builders...")
+	failures := 0
+
+	owner := solana.MustPublicKeyFromBase58("HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH")
+	usdcMint := solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")
+	// The SOL-USDC AMM v4 pool and its vaults
+	solUsdcPool := solana.MustPublicKeyFromBase58("58oQChx4yWmvKdwLLZzBi4ChoCc2fqCUWBkwMihLYQo2")
+	userSol, _ := DeriveAssociatedTokenAddress(owner, solana.SolMint, TokenProgramID)
+	userUsdc, _ := DeriveAssociatedTokenAddress(owner, usdcMint, TokenProgramID)
+	// A token launched on LetsBonk; its pool, vaults and metadata account are derived from it
+	launchMint := solana.MustPublicKeyFromBase58("8pf71rxkus6HVhNa9ERdJ571wfPa1a8QKKMsxGkDbonk")
 
 	// Test Swap Instruction
 	fmt.Println("\n1. Testing Swap Instruction Builder:")
 	swapInst := NewSwapInstruction().
-		SetUserSourceToken(solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112")).
-		SetUserDestToken(solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")).
-		SetUserOwner(solana.MustPublicKeyFromBase58("HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH")).
+		SetWithoutOpenBook(true).
+		SetAmmID(solUsdcPool).
+		SetPoolCoinToken(solana.MustPublicKeyFromBase58("DQyrAcCrDXQ7NeoqGgDCZwBvWDcYmFCjSb9JtteuvPpz")).
+		SetPoolPcToken(solana.MustPublicKeyFromBase58("HLmqeL62xR1QoZ1HKKbXRrdN1p3phKpxRMb2VVopvBBz")).
+		SetUserSourceToken(userSol).
+		SetUserDestToken(userUsdc).
+		SetUserOwner(owner).
 		SetAmountIn(1000000).
 		SetMinimumAmountOut(950000)
 
 	swapInstruction, err := swapInst.Build()
 	if err != nil {
+		failures++
 		fmt.Printf("   ❌ Failed to build swap instruction: %v\n", err)
 	} else {
 		fmt.Printf("   ✅ Swap instruction built successfully\n")
@@ -597,13 +1012,15 @@ func testInstructionBuilders() {
 	// Test Buy Instruction
 	fmt.Println("\n2. Testing Buy Instruction Builder:")
 	buyInst := NewBuyInstruction().
-		SetUserAuthority(solana.MustPublicKeyFromBase58("HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH")).
-		SetTokenMint(solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")).
-		SetAmount(1000000).
-		SetMaxSolCost(500000)
+		SetPayer(owner).
+		SetPlatformConfig(LetsBonkPlatformConfigID).
+		SetBaseMint(launchMint).
+		SetAmountIn(500000).
+		SetMinimumAmountOut(1000000)
 
 	buyInstruction, err := buyInst.Build()
 	if err != nil {
+		failures++
 		fmt.Printf("   ❌ Failed to build buy instruction: %v\n", err)
 	} else {
 		fmt.Printf("   ✅ Buy instruction built successfully\n")
@@ -612,19 +1029,21 @@ func testInstructionBuilders() {
 
 		data, _ := buyInstruction.Data()
 		fmt.Printf("   - Data length: %d bytes\n", len(data))
-		fmt.Printf("   - Instruction discriminator: %d\n", data[0])
+		fmt.Printf("   - Instruction discriminator: %x\n", data[:8])
 	}
 
 	// Test Sell Instruction
 	fmt.Println("\n3. Testing Sell Instruction Builder:")
 	sellInst := NewSellInstruction().
-		SetUserAuthority(solana.MustPublicKeyFromBase58("HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH")).
-		SetTokenMint(solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")).
-		SetAmount(1000000).
-		SetMinSolReceived(450000)
+		SetPayer(owner).
+		SetPlatformConfig(LetsBonkPlatformConfigID).
+		SetBaseMint(launchMint).
+		SetAmountIn(1000000).
+		SetMinimumAmountOut(450000)
 
 	sellInstruction, err := sellInst.Build()
 	if err != nil {
+		failures++
 		fmt.Printf("   ❌ Failed to build sell instruction: %v\n", err)
 	} else {
 		fmt.Printf("   ✅ Sell instruction built successfully\n")
@@ -633,21 +1052,27 @@ func testInstructionBuilders() {
 
 		data, _ := sellInstruction.Data()
 		fmt.Printf("   - Data length: %d bytes\n", len(data))
-		fmt.Printf("   - Instruction discriminator: %d\n", data[0])
+		fmt.Printf("   - Instruction discriminator: %x\n", data[:8])
 	}
 
 	// Test Create Token Instruction
 	fmt.Println("\n4. Testing Create Token Instruction Builder:")
 	createInst := NewCreateTokenInstruction().
-		SetPayer(solana.MustPublicKeyFromBase58("HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH")).
-		SetMint(solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112")).
+		SetPayer(owner).
+		SetCreator(owner).
+		SetPlatformConfig(LetsBonkPlatformConfigID).
+		SetBaseMint(launchMint).
 		SetDecimals(6).
 		SetName("Test Token").
 		SetSymbol("TEST").
-		SetInitialSupply(1000000000)
+		// 1B tokens, 793.1M of them sold on the curve to raise 85 SOL
+		SetSupply(1_000_000_000_000_000).
+		SetTotalBaseSell(793_100_000_000_000).
+		SetTotalQuoteFundRaising(85_000_000_000)
 
 	createInstruction, err := createInst.Build()
 	if err != nil {
+		failures++
 		fmt.Printf("   ❌ Failed to build create token instruction: %v\n", err)
 	} else {
 		fmt.Printf("   ✅ Create token instruction built successfully\n")
@@ -656,28 +1081,13 @@ func testInstructionBuilders() {
 
 		data, _ := createInstruction.Data()
 		fmt.Printf("   - Data length: %d bytes\n", len(data))
-		fmt.Printf("   - Instruction discriminator: %d\n", data[0])
+		fmt.Printf("   - Instruction discriminator: %x\n", data[:8])
 	}
 
-	// Test Migrate Instruction
-	fmt.Println("\n5. Testing Migrate Instruction Builder:")
-	migrateInst := NewMigrateInstruction().
-		SetUserAuthority(solana.MustPublicKeyFromBase58("HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH")).
-		SetAmount(1000000)
-
-	migrateInstruction, err := migrateInst.Build()
-	if err != nil {
-		fmt.Printf("   ❌ Failed to build migrate instruction: %v\n", err)
-	} else {
-		fmt.Printf("   ✅ Migrate instruction built successfully\n")
-		fmt.Printf("   - Program ID: %s\n", migrateInstruction.ProgramID())
-		fmt.Printf("   - Number of accounts: %d\n", len(migrateInstruction.Accounts()))
-
-		data, _ := migrateInstruction.Data()
-		fmt.Printf("   - Data length: %d bytes\n", len(data))
-		fmt.Printf("   - Instruction discriminator: %d\n", data[0])
+	if failures > 0 {
+		fmt.Printf("\n❌ %d of 4 instruction builder tests failed\n", failures)
+		os.Exit(1)
 	}
-
 	fmt.Println("\n✅ All instruction builder tests completed successfully!")
 	fmt.Println("\nNext steps:")
 	fmt.Println("- Set environment variables SOLANA_WALLET_PATH and SOLANA_RPC_ENDPOINT to test transaction submission")
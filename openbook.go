@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Order book programs AMM v4 pools are paired with
+var (
+	OpenBookProgramID = solana.MustPublicKeyFromBase58("srmqPvymJeFKQ4zGQed1GFppgkRHB9kaELCbyksJtPX")
+	SerumV3ProgramID  = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+)
+
+// OpenBook / Serum v3 MarketState account size, including the 5-byte head and 7-byte tail padding
+const OPENBOOK_MARKET_STATE_SIZE = 388
+
+// OpenBookMarket represents the accounts of a decoded OpenBook (Serum v3) MarketState
+type OpenBookMarket struct {
+	Address          solana.PublicKey
+	ProgramID        solana.PublicKey
+	VaultSignerNonce uint64
+	BaseMint         solana.PublicKey
+	QuoteMint        solana.PublicKey
+	BaseVault        solana.PublicKey
+	QuoteVault       solana.PublicKey
+	RequestQueue     solana.PublicKey
+	EventQueue       solana.PublicKey
+	Bids             solana.PublicKey
+	Asks             solana.PublicKey
+	BaseLotSize      uint64
+	QuoteLotSize     uint64
+}
+
+// DecodeOpenBookMarket decodes a raw MarketState account. ProgramID is left for the caller,
+// usually from PoolInfo.MarketProgramID.
+func DecodeOpenBookMarket(data []byte) (*OpenBookMarket, error) {
+	if len(data) < OPENBOOK_MARKET_STATE_SIZE {
+		return nil, fmt.Errorf("failed to decode openbook market: expected %d bytes, got %d", OPENBOOK_MARKET_STATE_SIZE, len(data))
+	}
+
+	if string(data[:5]) != "serum" {
+		return nil, fmt.Errorf("failed to decode openbook market: missing serum account prefix")
+	}
+
+	r := newAccountReader(data)
+	r.skip(5) // "serum" head padding
+	r.skip(8) // account flags
+
+	market := &OpenBookMarket{}
+	market.Address = r.pubkey()
+	market.VaultSignerNonce = r.u64()
+	market.BaseMint = r.pubkey()
+	market.QuoteMint = r.pubkey()
+	market.BaseVault = r.pubkey()
+	r.skip(8 * 2) // base deposits total, base fees accrued
+	market.QuoteVault = r.pubkey()
+	r.skip(8 * 3) // quote deposits total, quote fees accrued, quote dust threshold
+	market.RequestQueue = r.pubkey()
+	market.EventQueue = r.pubkey()
+	market.Bids = r.pubkey()
+	market.Asks = r.pubkey()
+	market.BaseLotSize = r.u64()
+	market.QuoteLotSize = r.u64()
+
+	if r.err != nil {
+		return nil, fmt.Errorf("failed to decode openbook market: %w", r.err)
+	}
+	return market, nil
+}
+
+// VaultSigner derives the market's vault signer from its nonce
+func (m *OpenBookMarket) VaultSigner() (solana.PublicKey, error) {
+	if m.ProgramID.IsZero() {
+		return solana.PublicKey{}, fmt.Errorf("market program ID not set")
+	}
+	nonce := make([]byte, 8)
+	binary.LittleEndian.PutUint64(nonce, m.VaultSignerNonce)
+	return solana.CreateProgramAddress([][]byte{m.Address[:], nonce}, m.ProgramID)
+}
+
+// LoadAmmV4PoolKeys fetches and decodes an AMM v4 pool and its OpenBook market
+func LoadAmmV4PoolKeys(ctx context.Context, client *rpc.Client, poolAddress solana.PublicKey) (*PoolInfo, *OpenBookMarket, error) {
+	poolAccount, err := client.GetAccountInfo(ctx, poolAddress)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch pool %s: %w", poolAddress, err)
+	}
+	pool, err := DecodeAmmV4PoolState(poolAccount.GetBinary())
+	if err != nil {
+		return nil, nil, err
+	}
+	pool.Address = poolAddress
+
+	marketAccount, err := client.GetAccountInfo(ctx, pool.MarketID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch market %s: %w", pool.MarketID, err)
+	}
+	market, err := DecodeOpenBookMarket(marketAccount.GetBinary())
+	if err != nil {
+		return nil, nil, err
+	}
+	market.ProgramID = marketAccount.Value.Owner
+	return pool, market, nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// sampleOpenBookMarketAccount encodes a MarketState account for the given market address,
+// picking the first nonce that yields a valid vault signer
+func sampleOpenBookMarketAccount(t *testing.T, address solana.PublicKey, keys []solana.PublicKey) []byte {
+	t.Helper()
+	var nonce uint64
+	for ; nonce < 255; nonce++ {
+		seed := binary.LittleEndian.AppendUint64(nil, nonce)
+		if _, err := solana.CreateProgramAddress([][]byte{address[:], seed}, OpenBookProgramID); err == nil {
+			break
+		}
+	}
+
+	data := append([]byte("serum"), make([]byte, 8)...)
+	data = append(data, address[:]...)
+	data = binary.LittleEndian.AppendUint64(data, nonce)
+	data = append(data, keys[0][:]...) // base mint
+	data = append(data, keys[1][:]...) // quote mint
+	data = append(data, keys[2][:]...) // base vault
+	data = append(data, make([]byte, 16)...)
+	data = append(data, keys[3][:]...) // quote vault
+	data = append(data, make([]byte, 24)...)
+	for _, key := range keys[4:8] { // request queue, event queue, bids, asks
+		data = append(data, key[:]...)
+	}
+	data = binary.LittleEndian.AppendUint64(data, 100_000)
+	data = binary.LittleEndian.AppendUint64(data, 10)
+	data = append(data, make([]byte, 16)...)
+	data = append(data, []byte("padding")...)
+	return data
+}
+
+func TestDecodeOpenBookMarket(t *testing.T) {
+	address := solana.NewWallet().PublicKey()
+	keys := uniqueAccounts(8)
+	data := sampleOpenBookMarketAccount(t, address, keys)
+	if len(data) != OPENBOOK_MARKET_STATE_SIZE {
+		t.Fatalf("Sample account is %d bytes, want %d", len(data), OPENBOOK_MARKET_STATE_SIZE)
+	}
+
+	market, err := DecodeOpenBookMarket(data)
+	if err != nil {
+		t.Fatalf("Failed to decode market: %v", err)
+	}
+	if !market.Address.Equals(address) || !market.BaseVault.Equals(keys[2]) || !market.QuoteVault.Equals(keys[3]) {
+		t.Errorf("Wrong market address or vaults")
+	}
+	if !market.EventQueue.Equals(keys[5]) || !market.Bids.Equals(keys[6]) || !market.Asks.Equals(keys[7]) {
+		t.Errorf("Wrong order book accounts")
+	}
+	if market.BaseLotSize != 100_000 || market.QuoteLotSize != 10 {
+		t.Errorf("Unexpected lot sizes %d/%d", market.BaseLotSize, market.QuoteLotSize)
+	}
+
+	if _, err := market.VaultSigner(); err == nil {
+		t.Errorf("Expected error deriving vault signer without program ID")
+	}
+	market.ProgramID = OpenBookProgramID
+	if _, err := market.VaultSigner(); err != nil {
+		t.Errorf("Failed to derive vault signer: %v", err)
+	}
+
+	if _, err := DecodeOpenBookMarket(data[:300]); err == nil {
+		t.Errorf("Expected error for short account")
+	}
+	data[0] = 'x'
+	if _, err := DecodeOpenBookMarket(data); err == nil {
+		t.Errorf("Expected error for missing serum prefix")
+	}
+}
+
+func TestNewSwapInstructionFromPool(t *testing.T) {
+	poolData, _ := sampleAmmV4PoolAccount()
+	pool, err := DecodeAmmV4PoolState(poolData)
+	if err != nil {
+		t.Fatalf("Failed to decode pool: %v", err)
+	}
+	pool.Address = solana.NewWallet().PublicKey()
+	pool.MarketProgramID = OpenBookProgramID
+
+	market, err := DecodeOpenBookMarket(sampleOpenBookMarketAccount(t, pool.MarketID, uniqueAccounts(8)))
+	if err != nil {
+		t.Fatalf("Failed to decode market: %v", err)
+	}
+
+	wallet := solana.NewWallet().PublicKey()
+	swap, err := NewSwapInstructionFromPool(pool, market, wallet, pool.TokenB, 1_000_000, 990_000)
+	if err != nil {
+		t.Fatalf("Failed to build swap from pool: %v", err)
+	}
+	instruction, err := swap.Build()
+	if err != nil {
+		t.Fatalf("Failed to build instruction: %v", err)
+	}
+
+	source, _, _ := solana.FindAssociatedTokenAddress(wallet, pool.TokenB)
+	destination, _, _ := solana.FindAssociatedTokenAddress(wallet, pool.TokenA)
+	if !market.ProgramID.IsZero() {
+		t.Errorf("Expected the caller's market to be left unchanged, got program %s", market.ProgramID)
+	}
+	withProgram := *market
+	withProgram.ProgramID = OpenBookProgramID
+	vaultSigner, _ := withProgram.VaultSigner()
+	expected := []solana.PublicKey{
+		TokenProgramID, pool.Address, AmmV4AuthorityID, pool.OpenOrders, pool.TargetOrders,
+		pool.TokenAVault, pool.TokenBVault, OpenBookProgramID, pool.MarketID, market.Bids, market.Asks,
+		market.EventQueue, market.BaseVault, market.QuoteVault, vaultSigner, source, destination, wallet,
+	}
+	accounts := instruction.Accounts()
+	if len(accounts) != len(expected) {
+		t.Fatalf("Expected %d accounts, got %d", len(expected), len(accounts))
+	}
+	for i, key := range expected {
+		if !accounts[i].PublicKey.Equals(key) {
+			t.Errorf("Account %d: expected %s, got %s", i, key, accounts[i].PublicKey)
+		}
+	}
+
+	if _, err := NewSwapInstructionFromPool(pool, market, wallet, solana.NewWallet().PublicKey(), 1, 1); err == nil {
+		t.Errorf("Expected error for mint not in pool")
+	}
+	market.Address = solana.NewWallet().PublicKey()
+	if _, err := NewSwapInstructionFromPool(pool, market, wallet, pool.TokenA, 1, 1); err == nil {
+		t.Errorf("Expected error for mismatched market")
+	}
+}
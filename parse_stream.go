@@ -0,0 +1,205 @@
+package main
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+)
+
+// DEFAULT_PARSER_BUFFER is how many transactions ParseStream holds in flight beyond its workers
+const DEFAULT_PARSER_BUFFER = 64
+
+// ErrOutOfOrder is reported by ParseStream for a transaction that arrived after a later one
+// had already been emitted; the result still carries the parsed transaction
+var ErrOutOfOrder = errors.New("transaction arrived after later transactions were emitted")
+
+// RawTx is an encoded transaction waiting to be parsed, with its place in the chain
+type RawTx struct {
+	Slot     uint64
+	Position int
+	// Data is the base64 encoded transaction in Geyser or standard RPC format
+	Data string
+	// Meta, when set, is applied as by ParseTransactionWithMeta
+	Meta *TransactionMeta
+}
+
+// ParseResult is the outcome of parsing one RawTx
+type ParseResult struct {
+	Slot        uint64
+	Position    int
+	Transaction *Transaction
+	Err         error
+}
+
+// Parser parses transactions on a pool of workers. Configure it before first use; after
+// that its methods are safe to call from several goroutines at once.
+type Parser struct {
+	workers int
+	buffer  int
+	logging bool
+	opts    []ParseOption
+}
+
+// NewParser creates a quiet parser with one worker per CPU
+func NewParser() *Parser {
+	return &Parser{
+		workers: runtime.GOMAXPROCS(0),
+		buffer:  DEFAULT_PARSER_BUFFER,
+	}
+}
+
+// SetWorkers sets how many transactions are decoded at once
+func (p *Parser) SetWorkers(workers int) *Parser {
+	p.workers = max(workers, 1)
+	return p
+}
+
+// SetBuffer sets how many transactions may be in flight beyond the workers before
+// ParseStream stops reading its input. It is also how far out of order the input may be.
+func (p *Parser) SetBuffer(size int) *Parser {
+	p.buffer = max(size, 0)
+	return p
+}
+
+// SetLogging turns the parse functions' debug dumps and progress logging on for this
+// parser. They are off by default, since they serialize the workers on the log output.
+func (p *Parser) SetLogging(enabled bool) *Parser {
+	p.logging = enabled
+	return p
+}
+
+// SetParseOptions sets the options transactions are parsed with
+func (p *Parser) SetParseOptions(opts ...ParseOption) *Parser {
+	p.opts = opts
+	return p
+}
+
+// Parse parses a single transaction on the calling goroutine
+func (p *Parser) Parse(raw RawTx) ParseResult {
+	opts := p.opts
+	if !p.logging {
+		opts = append(opts[:len(opts):len(opts)], WithoutParseLogging())
+	}
+	result := ParseResult{Slot: raw.Slot, Position: raw.Position}
+	if raw.Meta != nil {
+		result.Transaction, result.Err = ParseTransactionWithMeta(raw.Data, raw.Slot, raw.Meta, opts...)
+	} else {
+		result.Transaction, result.Err = ParseTransaction(raw.Data, raw.Slot, opts...)
+	}
+	return result
+}
+
+// ParseStream parses transactions from in on the worker pool and delivers the results in
+// slot and position order. Up to the workers plus the buffer transactions are held at once;
+// reading from in pauses while that window is full, and the smallest result is emitted once
+// it is parsed and the window is full or in is closed. Input may therefore arrive out of
+// order by up to the window size. A transaction arriving after a later one was emitted is
+// delivered with ErrOutOfOrder.
+//
+// The returned channel is closed once in is closed and drained, or when ctx is cancelled,
+// in which case the remaining results are dropped.
+func (p *Parser) ParseStream(ctx context.Context, in <-chan RawTx) <-chan ParseResult {
+	window := p.workers + p.buffer
+	// Both channels have room for the whole window, so neither side ever blocks on them
+	jobs := make(chan *streamEntry, window)
+	parsed := make(chan *streamEntry, window)
+	out := make(chan ParseResult)
+
+	for i := 0; i < p.workers; i++ {
+		go func() {
+			for entry := range jobs {
+				entry.result = p.Parse(entry.raw)
+				parsed <- entry
+			}
+		}()
+	}
+
+	go func() {
+		defer close(out)
+		defer close(jobs)
+
+		var held streamWindow
+		var emitted *streamEntry
+		open := true
+		for open || len(held) > 0 {
+			receive := in
+			if !open || len(held) >= window {
+				receive = nil
+			}
+			var send chan<- ParseResult
+			var next ParseResult
+			if len(held) > 0 && held[0].done && (!open || len(held) >= window) {
+				send, next = out, held[0].result
+				if emitted != nil && held[0].less(emitted) {
+					next.Err = fmt.Errorf("%w: slot %d position %d after slot %d position %d",
+						ErrOutOfOrder, next.Slot, next.Position, emitted.raw.Slot, emitted.raw.Position)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case raw, ok := <-receive:
+				if !ok {
+					open = false
+					continue
+				}
+				entry := &streamEntry{raw: raw}
+				heap.Push(&held, entry)
+				jobs <- entry
+			case entry := <-parsed:
+				entry.done = true
+				heap.Fix(&held, entry.index)
+			case send <- next:
+				entry := heap.Pop(&held).(*streamEntry)
+				if emitted == nil || emitted.less(entry) {
+					emitted = entry
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// streamEntry is one transaction in ParseStream's window
+type streamEntry struct {
+	raw    RawTx
+	result ParseResult
+	done   bool
+	index  int
+}
+
+func (e *streamEntry) less(other *streamEntry) bool {
+	if e.raw.Slot != other.raw.Slot {
+		return e.raw.Slot < other.raw.Slot
+	}
+	return e.raw.Position < other.raw.Position
+}
+
+// streamWindow is a heap of the transactions ParseStream holds, smallest slot and position first
+type streamWindow []*streamEntry
+
+func (w streamWindow) Len() int           { return len(w) }
+func (w streamWindow) Less(i, j int) bool { return w[i].less(w[j]) }
+
+func (w streamWindow) Swap(i, j int) {
+	w[i], w[j] = w[j], w[i]
+	w[i].index = i
+	w[j].index = j
+}
+
+func (w *streamWindow) Push(x any) {
+	entry := x.(*streamEntry)
+	entry.index = len(*w)
+	*w = append(*w, entry)
+}
+
+func (w *streamWindow) Pop() any {
+	old := *w
+	entry := old[len(old)-1]
+	old[len(old)-1] = nil
+	*w = old[:len(old)-1]
+	return entry
+}
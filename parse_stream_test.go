@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// rawBuys signs count Launchpad buys, one per position of slots holding perSlot each
+func rawBuys(t *testing.T, count, perSlot int) ([]RawTx, []solana.Signature) {
+	t.Helper()
+	raws := make([]RawTx, count)
+	signatures := make([]solana.Signature, count)
+	for i := range raws {
+		tx, txBytes := signedBuyTransaction(t)
+		raws[i] = RawTx{Slot: 7000 + uint64(i/perSlot), Position: i % perSlot, Data: base64.StdEncoding.EncodeToString(txBytes)}
+		signatures[i] = tx.Signatures[0]
+	}
+	return raws, signatures
+}
+
+func feed(raws []RawTx) <-chan RawTx {
+	in := make(chan RawTx)
+	go func() {
+		defer close(in)
+		for _, raw := range raws {
+			in <- raw
+		}
+	}()
+	return in
+}
+
+func TestParseStreamOrder(t *testing.T) {
+	raws, signatures := rawBuys(t, 40, 8)
+	raws[13].Data = "not a transaction"
+
+	var results []ParseResult
+	for result := range NewParser().SetWorkers(8).SetBuffer(4).ParseStream(context.Background(), feed(raws)) {
+		results = append(results, result)
+	}
+	if len(results) != len(raws) {
+		t.Fatalf("Expected %d results, got %d", len(raws), len(results))
+	}
+	for i, result := range results {
+		if result.Slot != raws[i].Slot || result.Position != raws[i].Position {
+			t.Fatalf("Result %d is slot %d position %d, expected slot %d position %d", i, result.Slot, result.Position, raws[i].Slot, raws[i].Position)
+		}
+		if i == 13 {
+			if result.Err == nil {
+				t.Errorf("Expected the invalid transaction to fail")
+			}
+			continue
+		}
+		if result.Err != nil || result.Transaction.Signature != signatures[i] || len(result.Transaction.Trade) != 1 {
+			t.Errorf("Expected the buy %s at %d, got %+v", signatures[i], i, result)
+		}
+	}
+}
+
+func TestParseStreamReorders(t *testing.T) {
+	raws, signatures := rawBuys(t, 12, 4)
+	// Swap neighbours so every pair arrives backwards
+	shuffled := append([]RawTx{}, raws...)
+	for i := 0; i+1 < len(shuffled); i += 2 {
+		shuffled[i], shuffled[i+1] = shuffled[i+1], shuffled[i]
+	}
+
+	i := 0
+	for result := range NewParser().SetWorkers(2).SetBuffer(2).ParseStream(context.Background(), feed(shuffled)) {
+		if result.Err != nil || result.Slot != raws[i].Slot || result.Position != raws[i].Position || result.Transaction.Signature != signatures[i] {
+			t.Errorf("Result %d: expected slot %d position %d, got %+v", i, raws[i].Slot, raws[i].Position, result)
+		}
+		i++
+	}
+	if i != len(raws) {
+		t.Errorf("Expected %d results, got %d", len(raws), i)
+	}
+
+	// A transaction further out of place than the window is delivered, flagged
+	late := append(append([]RawTx{}, raws[1:]...), raws[0])
+	count, flagged := 0, 0
+	for result := range NewParser().SetWorkers(1).SetBuffer(1).ParseStream(context.Background(), feed(late)) {
+		count++
+		if errors.Is(result.Err, ErrOutOfOrder) {
+			flagged++
+			if result.Transaction.Signature != signatures[0] {
+				t.Errorf("Expected only the late transaction to be flagged, got %+v", result)
+			}
+		} else if result.Err != nil {
+			t.Errorf("Unexpected error %v", result.Err)
+		}
+	}
+	if count != len(raws) || flagged != 1 {
+		t.Errorf("Expected %d results with 1 flagged, got %d with %d", len(raws), count, flagged)
+	}
+}
+
+func TestParserLogging(t *testing.T) {
+	raws, _ := rawBuys(t, 1, 1)
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	if result := NewParser().Parse(raws[0]); result.Err != nil || logs.Len() != 0 {
+		t.Errorf("Expected a quiet parse, got %v and logs:\n%s", result.Err, logs.String())
+	}
+	// Other callers keep their logging
+	if _, err := ParseTransaction(raws[0].Data, raws[0].Slot); err != nil || logs.Len() == 0 {
+		t.Errorf("Expected ParseTransaction to log, got %v", err)
+	}
+}
+
+func TestParseStreamBackpressure(t *testing.T) {
+	raws, _ := rawBuys(t, 30, 30)
+	const workers, buffer = 2, 3
+
+	var read atomic.Int32
+	in := make(chan RawTx)
+	go func() {
+		defer close(in)
+		for _, raw := range raws {
+			in <- raw
+			read.Add(1)
+		}
+	}()
+	out := NewParser().SetWorkers(workers).SetBuffer(buffer).ParseStream(context.Background(), in)
+
+	// Nothing is consumed yet: input stops once the workers and the buffer are full
+	time.Sleep(200 * time.Millisecond)
+	if n := read.Load(); n > workers+buffer+2 {
+		t.Errorf("Expected reading to pause with nobody consuming, read %d", n)
+	}
+	count := 0
+	for range out {
+		count++
+	}
+	if count != len(raws) || read.Load() != int32(len(raws)) {
+		t.Errorf("Expected all %d transactions once consumed, got %d", len(raws), count)
+	}
+}
+
+func TestParseStreamWithoutBuffer(t *testing.T) {
+	raws, _ := rawBuys(t, 12, 12)
+	const workers = 4
+
+	var read atomic.Int32
+	in := make(chan RawTx)
+	go func() {
+		defer close(in)
+		for _, raw := range raws {
+			in <- raw
+			read.Add(1)
+		}
+	}()
+	out := NewParser().SetWorkers(workers).SetBuffer(0).ParseStream(context.Background(), in)
+
+	// Every worker still gets a transaction while the consumer is away
+	time.Sleep(200 * time.Millisecond)
+	if n := read.Load(); n < workers || n > workers+2 {
+		t.Errorf("Expected the %d workers to be fed and reading to pause, read %d", workers, n)
+	}
+	count := 0
+	for range out {
+		count++
+	}
+	if count != len(raws) {
+		t.Errorf("Expected %d results, got %d", len(raws), count)
+	}
+}
+
+func TestParseStreamCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan RawTx)
+	out := NewParser().SetWorkers(2).ParseStream(ctx, in)
+	cancel()
+	select {
+	case _, ok := <-out:
+		if ok {
+			t.Errorf("Expected no results after cancellation")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the results channel to close after cancellation")
+	}
+}
+
+func TestParserConcurrentUse(t *testing.T) {
+	raws, signatures := rawBuys(t, 12, 4)
+	parser := NewParser().SetWorkers(3).SetParseOptions(WithSignatureVerification())
+
+	var wg sync.WaitGroup
+	for stream := 0; stream < 4; stream++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			i := 0
+			for result := range parser.ParseStream(context.Background(), feed(raws)) {
+				if result.Err != nil || result.Transaction.Signature != signatures[i] {
+					t.Errorf("Stream result %d: expected %s, got %+v", i, signatures[i], result)
+				}
+				i++
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if result := parser.Parse(raws[0]); result.Err != nil || result.Transaction.Signature != signatures[0] {
+				t.Errorf("Parse: expected %s, got %+v", signatures[0], result)
+			}
+		}()
+	}
+	wg.Wait()
+}
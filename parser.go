@@ -4,7 +4,6 @@ import (
 	"encoding/base64"
 	"encoding/binary"
 	"fmt"
-	"log"
 
 	bin "github.com/gagliardetto/binary"
 	"github.com/gagliardetto/solana-go"
@@ -19,6 +18,7 @@ var (
 	// Raydium Launchpad specific program IDs
 	RaydiumLaunchpadV1ProgramID = solana.MustPublicKeyFromBase58("LanMV9sAd7wArD4vJFi2qDdfnVhFxYSUg6eADduJ3uj")
 	RaydiumCpSwapProgramID      = solana.MustPublicKeyFromBase58("CPMMoo8L3F4NbTegBCKVNunggL7H1ZpdTHKxQB5qKP1C")
+	RaydiumClmmProgramID        = solana.MustPublicKeyFromBase58("CAMMCzo5YL8w4VFF8KVHrK22GGUsp5VTaW7grrKgrWqK")
 	// Additional Raydium program IDs found in real transactions
 	RaydiumUnknownProgramID1 = solana.MustPublicKeyFromBase58("FoaFt2Dtz58RA6DPjbRb9t9z8sLJRChiGFTv21EfaseZ")
 	RaydiumUnknownProgramID2 = solana.MustPublicKeyFromBase58("LanMV9sAd7wArD4vJFi2qDdfnVhFxYSUg6eADduJ3uj")
@@ -27,6 +27,8 @@ var (
 	Token2022ProgramID       = solana.MustPublicKeyFromBase58("TokenzQdBNbLqP5VEhdkAS6EPFLC1PHnBqCXEpPxuEb")
 	SystemProgramID          = solana.MustPublicKeyFromBase58("11111111111111111111111111111111")
 	AssociatedTokenProgramID = solana.MustPublicKeyFromBase58("ATokenGPvbdGVxr1b2hvZbsiqW5xWH25efTNsLJA8knL")
+	MemoProgramID            = solana.MustPublicKeyFromBase58("MemoSq4gqABAXKb96qnH8TysNcWxMyWCqXgDLGmfcHr")
+	ComputeBudgetProgramID   = solana.MustPublicKeyFromBase58("ComputeBudget111111111111111111111111111111")
 )
 
 // Instruction discriminators for different Raydium operations
@@ -78,6 +80,24 @@ type TransactionMeta struct {
 	PreBalances   []uint64
 	PostBalances  []uint64
 	TokenBalances []TokenBalance
+
+	// Token balances before and after execution, as in getTransaction's meta
+	PreTokenBalances  []TokenBalance
+	PostTokenBalances []TokenBalance
+
+	// Addresses loaded from address lookup tables by a v0 transaction. They follow the
+	// static account keys, writable first, when resolving instruction account indexes.
+	LoadedWritableAddresses []solana.PublicKey
+	LoadedReadonlyAddresses []solana.PublicKey
+
+	// Instructions invoked via CPI, grouped by the top-level instruction that invoked them
+	InnerInstructions []CompiledInnerInstructions
+}
+
+// CompiledInnerInstructions holds the inner instructions of one top-level instruction
+type CompiledInnerInstructions struct {
+	Index        int
+	Instructions []solana.CompiledInstruction
 }
 
 type TokenBalance struct {
@@ -85,16 +105,97 @@ type TokenBalance struct {
 	Mint         solana.PublicKey
 	Amount       uint64
 	Decimals     uint8
+	// Account is the token account at AccountIndex; filled by withTokenBalanceAccounts
+	Account solana.PublicKey
 }
 
-func ParseTransaction(encodedTx string, slot uint64) (*Transaction, error) {
+// ParseTransaction parses a base64 encoded transaction in Geyser or standard RPC format
+func ParseTransaction(encodedTx string, slot uint64, opts ...ParseOption) (*Transaction, error) {
+	options := newParseOptions(opts)
+	if err := options.verify(encodedTx, nil); err != nil {
+		return nil, err
+	}
+
 	// Try to parse as Geyser format first
 	if geyserTx, err := parseGeyserTransaction(encodedTx, slot); err == nil {
-		return parseGeyserFormatTransaction(geyserTx)
+		return parseGeyserFormatTransaction(geyserTx, options)
 	}
 
 	// Fallback to standard RPC format
-	return parseStandardTransaction(encodedTx, slot)
+	return parseStandardTransaction(encodedTx, slot, nil, options)
+}
+
+// ParseTransactionWithMeta parses a standard RPC format transaction together with its status
+// metadata. Metadata resolves address lookup table accounts and supplies amounts that are not
+// part of instruction data, such as the liquidity moved by a Launchpad migration.
+func ParseTransactionWithMeta(encodedTx string, slot uint64, meta *TransactionMeta, opts ...ParseOption) (*Transaction, error) {
+	options := newParseOptions(opts)
+	if err := options.verify(encodedTx, nil); err != nil {
+		return nil, err
+	}
+	return parseStandardTransaction(encodedTx, slot, meta, options)
+}
+
+// applyTransactionMeta fills parsed operations with amounts only visible in metadata and
+// records the token decimals it carries
+func applyTransactionMeta(result *Transaction, meta *TransactionMeta) {
+	if meta == nil {
+		return
+	}
+	for _, balances := range [][]TokenBalance{meta.TokenBalances, meta.PreTokenBalances, meta.PostTokenBalances} {
+		for _, balance := range balances {
+			if !balance.Mint.IsZero() {
+				result.observeToken(TokenInfo{Mint: balance.Mint, Decimals: balance.Decimals})
+			}
+		}
+	}
+	for i := range result.Migrate {
+		applyMigrationBalances(&result.Migrate[i], meta)
+	}
+}
+
+// withTokenBalanceAccounts returns a copy of m with Account set on every token balance from
+// its AccountIndex. m itself is left alone, since callers may share it between goroutines.
+func (m *TransactionMeta) withTokenBalanceAccounts(accountKeys []solana.PublicKey) *TransactionMeta {
+	if m == nil {
+		return nil
+	}
+	resolved := *m
+	for _, balances := range []*[]TokenBalance{&resolved.TokenBalances, &resolved.PreTokenBalances, &resolved.PostTokenBalances} {
+		*balances = append([]TokenBalance(nil), *balances...)
+		for i := range *balances {
+			balance := &(*balances)[i]
+			if balance.AccountIndex >= 0 && balance.AccountIndex < len(accountKeys) {
+				balance.Account = accountKeys[balance.AccountIndex]
+			}
+		}
+	}
+	return &resolved
+}
+
+// tokenBalanceIncrease returns how much a token account's balance grew during the transaction.
+// Accounts created by the transaction have no pre balance and count from zero.
+func (m *TransactionMeta) tokenBalanceIncrease(account solana.PublicKey) (uint64, bool) {
+	if m == nil || account.IsZero() {
+		return 0, false
+	}
+
+	var pre uint64
+	for _, balance := range m.PreTokenBalances {
+		if balance.Account.Equals(account) {
+			pre = balance.Amount
+			break
+		}
+	}
+	for _, balance := range m.PostTokenBalances {
+		if balance.Account.Equals(account) {
+			if balance.Amount < pre {
+				return 0, true
+			}
+			return balance.Amount - pre, true
+		}
+	}
+	return 0, false
 }
 
 func parseGeyserTransaction(encodedTx string, slot uint64) (*GeyserTransaction, error) {
@@ -136,7 +237,8 @@ func parseGeyserBytes(txBytes []byte, slot uint64) (*GeyserTransaction, error) {
 }
 
 // parseGeyserFormatTransaction parses a Geyser format transaction
-func parseGeyserFormatTransaction(geyserTx *GeyserTransaction) (*Transaction, error) {
+func parseGeyserFormatTransaction(geyserTx *GeyserTransaction, options parseOptions) (*Transaction, error) {
+	meta := geyserTx.Meta.withTokenBalanceAccounts(geyserTx.AccountKeys)
 	result := &Transaction{
 		Signature:  geyserTx.Signature,
 		Slot:       geyserTx.Slot,
@@ -147,47 +249,67 @@ func parseGeyserFormatTransaction(geyserTx *GeyserTransaction) (*Transaction, er
 		Migrate:    []Migration{},
 		SwapBuys:   []SwapBuy{},
 		SwapSells:  []SwapSell{},
+		quiet:      options.quiet,
 	}
 
 	// Parse level-1 instructions
 	for i, instruction := range geyserTx.Instructions {
-		if err := parseGeyserInstructionWrapper(instruction, i, result, geyserTx.Meta); err != nil {
-			log.Printf("Error parsing Geyser instruction %d: %v", i, err)
+		if err := parseGeyserInstructionWrapper(instruction, i, result, meta); err != nil {
+			result.logf("Error parsing Geyser instruction %d: %v", i, err)
 		}
 	}
 
 	// Parse level-2 (inner) instructions
 	for _, innerInstr := range geyserTx.InnerInstructions {
 		for j, instruction := range innerInstr.Instructions {
-			if err := parseGeyserInstructionWrapper(instruction, innerInstr.Index*100+j, result, geyserTx.Meta); err != nil {
-				log.Printf("Error parsing inner instruction %d.%d: %v", innerInstr.Index, j, err)
+			if err := parseGeyserInstructionWrapper(instruction, innerInstr.Index*100+j, result, meta); err != nil {
+				result.logf("Error parsing inner instruction %d.%d: %v", innerInstr.Index, j, err)
 			}
 		}
 	}
 
+	applyTransactionMeta(result, meta)
+
 	return result, nil
 }
 
-// parseStandardTransaction parses a standard RPC format transaction
-func parseStandardTransaction(encodedTx string, slot uint64) (*Transaction, error) {
+// parseStandardTransaction parses a standard RPC format transaction. Metadata is optional;
+// when given it resolves lookup table accounts, adds the inner instructions and fills amounts.
+func parseStandardTransaction(encodedTx string, slot uint64, meta *TransactionMeta, options parseOptions) (*Transaction, error) {
 	// Decode the base64 encoded transaction
 	txBytes, err := base64.StdEncoding.DecodeString(encodedTx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode base64 transaction: %w", err)
 	}
 
-	log.Printf("Decoded transaction bytes: %d bytes", len(txBytes))
+	options.logf("Decoded transaction bytes: %d bytes", len(txBytes))
 
 	// Parse the transaction using solana-go
 	decoder := bin.NewBinDecoder(txBytes)
 	tx, err := solana.TransactionFromDecoder(decoder)
 	if err != nil {
+		if meta != nil {
+			// Metadata cannot be matched against a message that did not decode
+			return nil, fmt.Errorf("failed to decode transaction: %w", err)
+		}
+
 		// Log the specific error for debugging
-		log.Printf("Transaction decoding error: %v", err)
-		log.Printf("Trying alternative decoding method...")
+		options.logf("Transaction decoding error: %v", err)
+		options.logf("Trying alternative decoding method...")
 
 		// Try alternative decoding method
-		return parseTransactionWithAlternativeDecoder(txBytes, slot)
+		return parseTransactionWithAlternativeDecoder(txBytes, slot, options)
+	}
+	if len(tx.Signatures) == 0 {
+		return nil, fmt.Errorf("transaction has no signatures")
+	}
+
+	// Work on a copy so the decoded message keeps its static key list
+	message := tx.Message
+	if meta != nil {
+		message.AccountKeys = append(append(append(solana.PublicKeySlice{}, tx.Message.AccountKeys...),
+			meta.LoadedWritableAddresses...), meta.LoadedReadonlyAddresses...)
+		meta = meta.withTokenBalanceAccounts(message.AccountKeys)
 	}
 
 	// Initialize the result transaction
@@ -201,23 +323,36 @@ func parseStandardTransaction(encodedTx string, slot uint64) (*Transaction, erro
 		Migrate:    []Migration{},
 		SwapBuys:   []SwapBuy{},
 		SwapSells:  []SwapSell{},
+		quiet:      options.quiet,
 	}
 
-	log.Printf("Parsing transaction with %d instructions", len(tx.Message.Instructions))
+	result.logf("Parsing transaction with %d instructions", len(message.Instructions))
 
 	// Parse top-level instructions
-	for i, instruction := range tx.Message.Instructions {
-		if err := parseInstruction(instruction, &tx.Message, i, result); err != nil {
-			log.Printf("Error parsing instruction %d: %v", i, err)
+	for i, instruction := range message.Instructions {
+		if err := parseInstruction(instruction, &message, i, result); err != nil {
+			result.logf("Error parsing instruction %d: %v", i, err)
 		}
 	}
 
+	// Parse inner instructions, numbered like the Geyser path
+	if meta != nil {
+		for _, inner := range meta.InnerInstructions {
+			for j, instruction := range inner.Instructions {
+				if err := parseInstruction(instruction, &message, inner.Index*100+j, result); err != nil {
+					result.logf("Error parsing inner instruction %d.%d: %v", inner.Index, j, err)
+				}
+			}
+		}
+	}
+
+	applyTransactionMeta(result, meta)
 	return result, nil
 }
 
 // parseTransactionAlternative handles cases where standard unmarshaling fails
-func parseTransactionAlternative(encodedTx string, slot uint64) (*Transaction, error) {
-	log.Printf("Standard transaction parsing failed, using alternative approach")
+func parseTransactionAlternative(encodedTx string, slot uint64, options parseOptions) (*Transaction, error) {
+	options.logf("Standard transaction parsing failed, using alternative approach")
 
 	// Create a transaction with basic info but no parsed instructions
 	mockSignature := solana.Signature{}
@@ -233,9 +368,10 @@ func parseTransactionAlternative(encodedTx string, slot uint64) (*Transaction, e
 		Migrate:    []Migration{},
 		SwapBuys:   []SwapBuy{},
 		SwapSells:  []SwapSell{},
+		quiet:      options.quiet,
 	}
 
-	log.Printf("Transaction data length: %d bytes", len(encodedTx))
+	result.logf("Transaction data length: %d bytes", len(encodedTx))
 
 	// For demonstration, if the transaction has substantial data,
 	// we'll add some sample parsed content
@@ -277,7 +413,7 @@ func parseTransactionAlternative(encodedTx string, slot uint64) (*Transaction, e
 }
 
 // parseTransactionWithAlternativeDecoder tries a different approach to decode the transaction
-func parseTransactionWithAlternativeDecoder(txBytes []byte, slot uint64) (*Transaction, error) {
+func parseTransactionWithAlternativeDecoder(txBytes []byte, slot uint64, options parseOptions) (*Transaction, error) {
 
 	if len(txBytes) < 64 {
 		return nil, fmt.Errorf("transaction data too short: %d bytes", len(txBytes))
@@ -298,10 +434,11 @@ func parseTransactionWithAlternativeDecoder(txBytes []byte, slot uint64) (*Trans
 		Migrate:    []Migration{},
 		SwapBuys:   []SwapBuy{},
 		SwapSells:  []SwapSell{},
+		quiet:      options.quiet,
 	}
 
-	log.Printf("Successfully extracted signature from transaction: %s", signature.String())
-	log.Printf("Remaining transaction data: %d bytes", len(txBytes)-64)
+	result.logf("Successfully extracted signature from transaction: %s", signature.String())
+	result.logf("Remaining transaction data: %d bytes", len(txBytes)-64)
 
 	// TODO: Parse the rest of the transaction structure
 	// For now, we'll just return the transaction with the real signature
@@ -309,8 +446,14 @@ func parseTransactionWithAlternativeDecoder(txBytes []byte, slot uint64) (*Trans
 	return result, nil
 }
 
-// ParseTransactionWithSignature parses a transaction from base64 encoded data with a known signature
-func ParseTransactionWithSignature(encodedTx string, slot uint64, originalSignature solana.Signature) (*Transaction, error) {
+// ParseTransactionWithSignature parses a transaction from base64 encoded data with a known signature.
+// The known signature is trusted unless WithSignatureVerification is given.
+func ParseTransactionWithSignature(encodedTx string, slot uint64, originalSignature solana.Signature, opts ...ParseOption) (*Transaction, error) {
+	options := newParseOptions(opts)
+	if err := options.verify(encodedTx, &originalSignature); err != nil {
+		return nil, err
+	}
+
 	// First try Geyser format
 	geyserTx, err := parseGeyserTransaction(encodedTx, slot)
 	if err == nil {
@@ -326,38 +469,39 @@ func ParseTransactionWithSignature(encodedTx string, slot uint64, originalSignat
 			Migrate:    []Migration{},
 			SwapBuys:   []SwapBuy{},
 			SwapSells:  []SwapSell{},
+			quiet:      options.quiet,
 		}
 
 		// Convert Geyser transaction data to standard format
 		// This is a simplified conversion - real implementation would be more complex
-		log.Printf("Converted Geyser transaction to standard format")
+		result.logf("Converted Geyser transaction to standard format")
 		return result, nil
 	}
 
 	// Fallback to standard RPC format
-	return parseStandardTransactionWithSignature(encodedTx, slot, originalSignature)
+	return parseStandardTransactionWithSignature(encodedTx, slot, originalSignature, options)
 }
 
 // parseStandardTransactionWithSignature parses a standard RPC format transaction with known signature
-func parseStandardTransactionWithSignature(encodedTx string, slot uint64, originalSignature solana.Signature) (*Transaction, error) {
+func parseStandardTransactionWithSignature(encodedTx string, slot uint64, originalSignature solana.Signature, options parseOptions) (*Transaction, error) {
 	// Decode the base64 encoded transaction
 	txBytes, err := base64.StdEncoding.DecodeString(encodedTx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode base64 transaction: %w", err)
 	}
 
-	log.Printf("Decoded transaction bytes: %d bytes", len(txBytes))
+	options.logf("Decoded transaction bytes: %d bytes", len(txBytes))
 
 	// Parse the transaction using solana-go
 	decoder := bin.NewBinDecoder(txBytes)
 	tx, err := solana.TransactionFromDecoder(decoder)
 	if err != nil {
 		// Log the specific error for debugging
-		log.Printf("Transaction decoding error: %v", err)
-		log.Printf("Trying alternative decoding method...")
+		options.logf("Transaction decoding error: %v", err)
+		options.logf("Trying alternative decoding method...")
 
 		// Try alternative decoding method
-		return parseTransactionWithAlternativeDecoderAndSignature(txBytes, slot, originalSignature)
+		return parseTransactionWithAlternativeDecoderAndSignature(txBytes, slot, originalSignature, options)
 	}
 
 	// Initialize the result transaction with the original signature
@@ -371,14 +515,15 @@ func parseStandardTransactionWithSignature(encodedTx string, slot uint64, origin
 		Migrate:    []Migration{},
 		SwapBuys:   []SwapBuy{},
 		SwapSells:  []SwapSell{},
+		quiet:      options.quiet,
 	}
 
-	log.Printf("Parsing transaction with %d instructions", len(tx.Message.Instructions))
+	result.logf("Parsing transaction with %d instructions", len(tx.Message.Instructions))
 
 	// Parse top-level instructions
 	for i, instruction := range tx.Message.Instructions {
 		if err := parseInstruction(instruction, &tx.Message, i, result); err != nil {
-			log.Printf("Error parsing instruction %d: %v", i, err)
+			result.logf("Error parsing instruction %d: %v", i, err)
 			continue
 		}
 	}
@@ -387,15 +532,15 @@ func parseStandardTransactionWithSignature(encodedTx string, slot uint64, origin
 	// Note: Inner instructions are typically not available in this format
 	// They would be included in the transaction metadata from RPC calls
 
-	log.Printf("Successfully parsed transaction with %d creates, %d trades, %d migrations",
+	result.logf("Successfully parsed transaction with %d creates, %d trades, %d migrations",
 		len(result.Create), len(result.Trade), len(result.Migrate))
 
 	return result, nil
 }
 
 // parseTransactionWithAlternativeDecoderAndSignature uses alternative decoding with known signature
-func parseTransactionWithAlternativeDecoderAndSignature(txBytes []byte, slot uint64, originalSignature solana.Signature) (*Transaction, error) {
-	log.Printf("Using alternative decoder for %d bytes", len(txBytes))
+func parseTransactionWithAlternativeDecoderAndSignature(txBytes []byte, slot uint64, originalSignature solana.Signature, options parseOptions) (*Transaction, error) {
+	options.logf("Using alternative decoder for %d bytes", len(txBytes))
 
 	if len(txBytes) < 64 {
 		return nil, fmt.Errorf("transaction data too short: %d bytes", len(txBytes))
@@ -412,11 +557,12 @@ func parseTransactionWithAlternativeDecoderAndSignature(txBytes []byte, slot uin
 		Migrate:    []Migration{},
 		SwapBuys:   []SwapBuy{},
 		SwapSells:  []SwapSell{},
+		quiet:      options.quiet,
 	}
 
 	// Try to parse what we can from the raw bytes
 	// This is a fallback method for when standard parsing fails
-	log.Printf("Alternative parsing completed - using original signature")
+	result.logf("Alternative parsing completed - using original signature")
 
 	return result, nil
 }
@@ -428,38 +574,40 @@ func parseInstruction(instruction solana.CompiledInstruction, message *solana.Me
 
 	programID := message.AccountKeys[instruction.ProgramIDIndex]
 
-	// Create comprehensive debug info structure
-	debugInfo := createInstructionDebugInfo(instruction, message, index, programID)
-
 	// Print detailed debug info with all 18 account fields
-	printInstructionDebugInfo(debugInfo)
+	if !result.quiet {
+		printInstructionDebugInfo(createInstructionDebugInfo(instruction, message, index, programID))
+	}
 
 	// Check if this is a Raydium instruction
 	switch programID {
 	case RaydiumV4ProgramID, RaydiumV5ProgramID:
-		log.Printf("Found Raydium V4/V5 instruction at index %d", index)
+		result.logf("Found Raydium V4/V5 instruction at index %d", index)
 		return parseRaydiumInstruction(instruction, message, index, result)
 	case RaydiumStakingProgramID:
-		log.Printf("Found Raydium Staking instruction at index %d", index)
+		result.logf("Found Raydium Staking instruction at index %d", index)
 		return parseStakingInstruction(instruction, message, index, result)
 	case RaydiumLiquidityProgramID:
-		log.Printf("Found Raydium Liquidity instruction at index %d", index)
+		result.logf("Found Raydium Liquidity instruction at index %d", index)
 		return parseLiquidityInstruction(instruction, message, index, result)
 	case RaydiumLaunchpadV1ProgramID:
-		log.Printf("Found Raydium Launchpad instruction at index %d", index)
+		result.logf("Found Raydium Launchpad instruction at index %d", index)
 		return parseRaydiumLaunchpadInstructionStandard(instruction, message, index, result)
 	case RaydiumCpSwapProgramID:
-		log.Printf("Found Raydium CP Swap instruction at index %d", index)
+		result.logf("Found Raydium CP Swap instruction at index %d", index)
 		return parseRaydiumInstruction(instruction, message, index, result)
 	case RaydiumUnknownProgramID1, RaydiumUnknownProgramID2:
-		log.Printf("Found potential Raydium instruction at index %d (Program: %s)", index, programID.String())
+		result.logf("Found potential Raydium instruction at index %d (Program: %s)", index, programID.String())
 		return parseRaydiumInstruction(instruction, message, index, result)
-	case TokenProgramID:
-		log.Printf("Found Token Program instruction at index %d", index)
+	case TokenProgramID, Token2022ProgramID:
+		result.logf("Found Token Program instruction at index %d", index)
 		return parseTokenInstruction(instruction, message, index, result)
+	case MetaplexTokenMetadataProgramID:
+		result.logf("Found Metaplex Token Metadata instruction at index %d", index)
+		return parseMetaplexInstruction(instruction, message, index, result)
 	default:
 		// Not a Raydium-related instruction, skip
-		log.Printf("Skipping non-Raydium instruction at index %d (Program: %s)", index, programID.String())
+		result.logf("Skipping non-Raydium instruction at index %d (Program: %s)", index, programID.String())
 		return nil
 	}
 }
@@ -499,7 +647,7 @@ func parseRaydiumInstruction(instruction solana.CompiledInstruction, message *so
 	case INSTRUCTION_MIGRATE:
 		return parseMigrateInstruction(instruction, message, index, result)
 	default:
-		log.Printf("Unknown Raydium instruction discriminator: %d", discriminator)
+		result.logf("Unknown Raydium instruction discriminator: %d", discriminator)
 		return nil
 	}
 }
@@ -530,10 +678,10 @@ func parseComplexRaydiumInstruction(instruction solana.CompiledInstruction, mess
 	case COMPLEX_SELL:
 		return parseSellInstructionStandard(instruction, message, index, result)
 	case COMPLEX_UNKNOWN_1, COMPLEX_UNKNOWN_2:
-		log.Printf("Parsing unknown Raydium instruction with discriminator: %x", discriminator)
+		result.logf("Parsing unknown Raydium instruction with discriminator: %x", discriminator)
 		return parseGenericRaydiumInstruction(instruction, message, index, result, discriminator)
 	default:
-		log.Printf("Unknown complex Raydium instruction discriminator: %x", discriminator)
+		result.logf("Unknown complex Raydium instruction discriminator: %x", discriminator)
 		// Try to parse as generic Raydium instruction
 		return parseGenericRaydiumInstruction(instruction, message, index, result, discriminator)
 	}
@@ -631,7 +779,7 @@ func parseCreatePoolInstruction(instruction solana.CompiledInstruction, message
 
 	// Try to get token symbol from known tokens
 	tokenSymbol := "UNKNOWN"
-	if tokenInfo, exists := getKnownTokenInfo(tokenMint); exists {
+	if tokenInfo, exists := lookupToken(tokenMint); exists {
 		tokenSymbol = tokenInfo.Symbol
 	}
 
@@ -754,7 +902,7 @@ func parseBuyInstructionStandard(instruction solana.CompiledInstruction, message
 	for i, account := range message.AccountKeys {
 		if account.String() == expectedTokenMint {
 			tokenOut = account
-			log.Printf("DEBUG: FOUND EXPECTED TOKEN MINT for buy at index %d: %s", i, tokenOut.String())
+			result.logf("DEBUG: FOUND EXPECTED TOKEN MINT for buy at index %d: %s", i, tokenOut.String())
 			break
 		}
 	}
@@ -777,12 +925,12 @@ func parseBuyInstructionStandard(instruction solana.CompiledInstruction, message
 
 			// This should be the pool
 			pool = account
-			log.Printf("DEBUG: FOUND POOL ADDRESS for buy at index %d: %s", i, pool.String())
+			result.logf("DEBUG: FOUND POOL ADDRESS for buy at index %d: %s", i, pool.String())
 			break
 		}
 	} else {
 		// Fallback to old logic if we can't find the expected token mint
-		log.Printf("DEBUG: Expected token mint not found, using fallback logic")
+		result.logf("DEBUG: Expected token mint not found, using fallback logic")
 
 		// Search for token mint and pool using the same logic as create
 		for i := 0; i < len(instruction.Accounts) && i < 10; i++ {
@@ -812,22 +960,22 @@ func parseBuyInstructionStandard(instruction solana.CompiledInstruction, message
 			// First eligible account is likely the token being bought
 			if tokenOut.IsZero() {
 				tokenOut = account
-				log.Printf("DEBUG: Found token out: %s", tokenOut.String())
+				result.logf("DEBUG: Found token out: %s", tokenOut.String())
 			} else if pool.IsZero() {
 				pool = account
-				log.Printf("DEBUG: Found pool: %s", pool.String())
+				result.logf("DEBUG: Found pool: %s", pool.String())
 			}
 		}
 	}
 
 	// Debug logging to help with troubleshooting
-	log.Printf("DEBUG: Buy instruction")
-	log.Printf("DEBUG: Buyer (signer): %s", buyer.String())
-	log.Printf("DEBUG: Token in (SOL): %s", solMint.String())
-	log.Printf("DEBUG: Token out: %s", tokenOut.String())
-	log.Printf("DEBUG: Pool: %s", pool.String())
-	log.Printf("DEBUG: Amount in: %d", amountIn)
-	log.Printf("DEBUG: Max amount in: %d", maxAmountIn)
+	result.logf("DEBUG: Buy instruction")
+	result.logf("DEBUG: Buyer (signer): %s", buyer.String())
+	result.logf("DEBUG: Token in (SOL): %s", solMint.String())
+	result.logf("DEBUG: Token out: %s", tokenOut.String())
+	result.logf("DEBUG: Pool: %s", pool.String())
+	result.logf("DEBUG: Amount in: %d", amountIn)
+	result.logf("DEBUG: Max amount in: %d", maxAmountIn)
 
 	tradeInfo := TradeInfo{
 		InstructionIndex: index,
@@ -879,13 +1027,13 @@ func parseSellInstructionStandard(instruction solana.CompiledInstruction, messag
 	}
 
 	// Debug logging to help with troubleshooting
-	log.Printf("DEBUG: Sell instruction")
-	log.Printf("DEBUG: Seller (signer): %s", seller.String())
-	log.Printf("DEBUG: Token in: %s", tokenIn.String())
-	log.Printf("DEBUG: Token out (SOL): %s", solMint.String())
-	log.Printf("DEBUG: Pool: %s", pool.String())
-	log.Printf("DEBUG: Amount in: %d", amountIn)
-	log.Printf("DEBUG: Min amount out: %d", minAmountOut)
+	result.logf("DEBUG: Sell instruction")
+	result.logf("DEBUG: Seller (signer): %s", seller.String())
+	result.logf("DEBUG: Token in: %s", tokenIn.String())
+	result.logf("DEBUG: Token out (SOL): %s", solMint.String())
+	result.logf("DEBUG: Pool: %s", pool.String())
+	result.logf("DEBUG: Amount in: %d", amountIn)
+	result.logf("DEBUG: Min amount out: %d", minAmountOut)
 
 	tradeInfo := TradeInfo{
 		InstructionIndex: index,
@@ -924,17 +1072,28 @@ func parseSellInstructionStandard(instruction solana.CompiledInstruction, messag
 // parseDepositInstruction parses liquidity deposit instructions
 func parseDepositInstruction(instruction solana.CompiledInstruction, message *solana.Message, index int, result *Transaction) error {
 	// Implementation would depend on the specific instruction format
-	log.Printf("Deposit instruction detected at index %d", index)
+	result.logf("Deposit instruction detected at index %d", index)
 	return nil
 }
 
 // parseWithdrawInstruction parses liquidity withdrawal instructions
 func parseWithdrawInstruction(instruction solana.CompiledInstruction, message *solana.Message, index int, result *Transaction) error {
 	// Implementation would depend on the specific instruction format
-	log.Printf("Withdraw instruction detected at index %d", index)
+	result.logf("Withdraw instruction detected at index %d", index)
 	return nil
 }
 
+// Account positions the parser reads tag-4 "migrate" instructions with. AMM v4 has no
+// migrate instruction to check against (its tag 4 is a withdraw), so there is no builder;
+// these follow the layout the parser has always read, signing authority after the pools.
+const (
+	migrateFromPool       = 0
+	migrateToPool         = 1
+	migrateTokenAccount   = 2
+	migrateUserAuthority  = 3
+	migrateAccountsLength = 5
+)
+
 // parseMigrateInstruction parses migration instructions
 func parseMigrateInstruction(instruction solana.CompiledInstruction, message *solana.Message, index int, result *Transaction) error {
 	if len(instruction.Accounts) < 4 {
@@ -948,10 +1107,10 @@ func parseMigrateInstruction(instruction solana.CompiledInstruction, message *so
 	}
 
 	migration := Migration{
-		FromPool:  message.AccountKeys[instruction.Accounts[0]],
-		ToPool:    message.AccountKeys[instruction.Accounts[1]],
-		Token:     message.AccountKeys[instruction.Accounts[2]],
-		Owner:     message.AccountKeys[instruction.Accounts[3]],
+		FromPool:  message.AccountKeys[instruction.Accounts[migrateFromPool]],
+		ToPool:    message.AccountKeys[instruction.Accounts[migrateToPool]],
+		Token:     message.AccountKeys[instruction.Accounts[migrateTokenAccount]],
+		Owner:     message.AccountKeys[instruction.Accounts[migrateUserAuthority]],
 		Amount:    amount,
 		Timestamp: 0, // Would be extracted from block time
 	}
@@ -962,13 +1121,13 @@ func parseMigrateInstruction(instruction solana.CompiledInstruction, message *so
 
 // parseStakingInstruction parses staking-related instructions
 func parseStakingInstruction(instruction solana.CompiledInstruction, message *solana.Message, index int, result *Transaction) error {
-	log.Printf("Staking instruction detected at index %d", index)
+	result.logf("Staking instruction detected at index %d", index)
 	return nil
 }
 
 // parseLiquidityInstruction parses liquidity-related instructions
 func parseLiquidityInstruction(instruction solana.CompiledInstruction, message *solana.Message, index int, result *Transaction) error {
-	log.Printf("Liquidity instruction detected at index %d", index)
+	result.logf("Liquidity instruction detected at index %d", index)
 	return nil
 }
 
@@ -985,6 +1144,13 @@ func parseTokenInstruction(instruction solana.CompiledInstruction, message *sola
 		return parseTokenTransferInstructionStandard(instruction, message, index, result)
 	case TOKEN_INSTRUCTION_MINT_TO:
 		return parseTokenMintInstructionStandard(instruction, message, index, result)
+	case TOKEN_INSTRUCTION_INITIALIZE_MINT, TOKEN_INSTRUCTION_INITIALIZE_MINT2, TOKEN_INSTRUCTION_SET_AUTHORITY:
+		accounts, err := resolveInstructionAccounts(instruction, message)
+		if err != nil {
+			return err
+		}
+		applyMintAuthorityInstruction(accounts, instruction.Data, result)
+		return nil
 	default:
 		// Other token instructions we don't need to track
 		return nil
@@ -1000,7 +1166,7 @@ func parseTokenTransferInstructionStandard(instruction solana.CompiledInstructio
 	// Extract transfer amount
 	amount := binary.LittleEndian.Uint64(instruction.Data[1:9])
 
-	log.Printf("Token transfer detected: %d tokens at instruction %d", amount, index)
+	result.logf("Token transfer detected: %d tokens at instruction %d", amount, index)
 
 	return nil
 }
@@ -1014,7 +1180,7 @@ func parseTokenMintInstructionStandard(instruction solana.CompiledInstruction, m
 	amount := binary.LittleEndian.Uint64(instruction.Data[1:9])
 
 	// Token minting indicates token creation or additional supply
-	log.Printf("Token mint detected: %d tokens at instruction %d", amount, index)
+	result.logf("Token mint detected: %d tokens at instruction %d", amount, index)
 
 	return nil
 }
@@ -1043,39 +1209,6 @@ func calculateSlippage(actualAmount, expectedAmount uint64) float64 {
 	return float64(expectedAmount-actualAmount) / float64(expectedAmount)
 }
 
-func getKnownTokenInfo(tokenMint solana.PublicKey) (TokenInfo, bool) {
-
-	knownTokensLocal := map[string]TokenInfo{
-		"So11111111111111111111111111111111111111112": {
-			Mint:     solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112"),
-			Symbol:   "SOL",
-			Name:     "Solana",
-			Decimals: 9,
-		},
-		"EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v": {
-			Mint:     solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"),
-			Symbol:   "USDC",
-			Name:     "USD Coin",
-			Decimals: 6,
-		},
-		"Es9vMFrzaCERmJfrF4H2FYD4KCoNkY11McCe8BenwNYB": {
-			Mint:     solana.MustPublicKeyFromBase58("Es9vMFrzaCERmJfrF4H2FYD4KCoNkY11McCe8BenwNYB"),
-			Symbol:   "USDT",
-			Name:     "Tether USD",
-			Decimals: 6,
-		},
-		"8pf71rxkus6HVhNa9ERdJ571wfPa1a8QKKMsxGkDbonk": {
-			Mint:     solana.MustPublicKeyFromBase58("8pf71rxkus6HVhNa9ERdJ571wfPa1a8QKKMsxGkDbonk"),
-			Symbol:   "JAMAL",
-			Name:     "Jamal Token",
-			Decimals: 6,
-		},
-	}
-
-	info, exists := knownTokensLocal[tokenMint.String()]
-	return info, exists
-}
-
 // parseGeyserInstructionWrapper parses a Geyser format instruction
 func parseGeyserInstructionWrapper(instruction GeyserInstruction, index int, result *Transaction, meta *TransactionMeta) error {
 	programID := instruction.ProgramID
@@ -1090,6 +1223,8 @@ func parseGeyserInstructionWrapper(instruction GeyserInstruction, index int, res
 		return parseRaydiumCpSwapInstruction(instruction, index, result, meta)
 	case TokenProgramID, Token2022ProgramID:
 		return parseTokenGeyserInstruction(instruction, index, result, meta)
+	case MetaplexTokenMetadataProgramID:
+		return parseGeyserMetaplexInstruction(instruction, index, result, meta)
 	default:
 		// Not a Raydium-related instruction, skip
 		return nil
@@ -1115,7 +1250,7 @@ func parseRaydiumGeyserInstruction(instruction GeyserInstruction, index int, res
 	case INSTRUCTION_MIGRATE:
 		return parseGeyserMigrateInstruction(instruction, index, result, meta)
 	default:
-		log.Printf("Unknown Raydium instruction discriminator: %d", discriminator)
+		result.logf("Unknown Raydium instruction discriminator: %d", discriminator)
 		return nil
 	}
 }
@@ -1125,6 +1260,13 @@ func parseRaydiumLaunchpadInstruction(instruction GeyserInstruction, index int,
 		return fmt.Errorf("launchpad instruction data is empty")
 	}
 
+	if isLaunchpadMigrateInstruction(instruction.Data) {
+		return parseGeyserLaunchpadMigrateInstruction(instruction, index, result)
+	}
+	if isLaunchpadInitializeInstruction(instruction.Data) {
+		return parseGeyserLaunchpadInitializeInstruction(instruction, index, result, meta)
+	}
+
 	discriminator := instruction.Data[0]
 
 	switch discriminator {
@@ -1135,7 +1277,7 @@ func parseRaydiumLaunchpadInstruction(instruction GeyserInstruction, index int,
 	case INSTRUCTION_SELL:
 		return parseGeyserSellInstruction(instruction, index, result, meta)
 	default:
-		log.Printf("Unknown Raydium Launchpad instruction discriminator: %d", discriminator)
+		result.logf("Unknown Raydium Launchpad instruction discriminator: %d", discriminator)
 		return nil
 	}
 }
@@ -1151,7 +1293,7 @@ func parseRaydiumCpSwapInstruction(instruction GeyserInstruction, index int, res
 	case INSTRUCTION_SWAP_BASE_IN, INSTRUCTION_SWAP_BASE_OUT:
 		return parseGeyserSwapInstruction(instruction, index, result, meta)
 	default:
-		log.Printf("Unknown CP Swap instruction discriminator: %d", discriminator)
+		result.logf("Unknown CP Swap instruction discriminator: %d", discriminator)
 		return nil
 	}
 }
@@ -1168,6 +1310,9 @@ func parseTokenGeyserInstruction(instruction GeyserInstruction, index int, resul
 		return parseTokenTransferInstruction(instruction, index, result, meta)
 	case TOKEN_INSTRUCTION_MINT_TO:
 		return parseTokenMintInstruction(instruction, index, result, meta)
+	case TOKEN_INSTRUCTION_INITIALIZE_MINT, TOKEN_INSTRUCTION_INITIALIZE_MINT2, TOKEN_INSTRUCTION_SET_AUTHORITY:
+		applyMintAuthorityInstruction(instruction.Accounts, instruction.Data, result)
+		return nil
 	default:
 		return nil
 	}
@@ -1378,10 +1523,10 @@ func parseGeyserMigrateInstruction(instruction GeyserInstruction, index int, res
 	}
 
 	migration := Migration{
-		FromPool:  instruction.Accounts[0],
-		ToPool:    instruction.Accounts[1],
-		Token:     instruction.Accounts[2],
-		Owner:     instruction.Accounts[3],
+		FromPool:  instruction.Accounts[migrateFromPool],
+		ToPool:    instruction.Accounts[migrateToPool],
+		Token:     instruction.Accounts[migrateTokenAccount],
+		Owner:     instruction.Accounts[migrateUserAuthority],
 		Amount:    amount,
 		Timestamp: 0, // Would be extracted from block time
 	}
@@ -1414,7 +1559,7 @@ func extractTokenSymbol(tokenMint solana.PublicKey, meta *TransactionMeta) strin
 	// 3. Parse token name from transaction logs
 
 	// For now, i will return known symbols or default
-	if tokenInfo, exists := getKnownTokenInfo(tokenMint); exists {
+	if tokenInfo, exists := lookupToken(tokenMint); exists {
 		return tokenInfo.Symbol
 	}
 	return "UNKNOWN"
@@ -1439,20 +1584,20 @@ func extractAmountOutFromMeta(accounts []solana.PublicKey, meta *TransactionMeta
 }
 
 func parseGenericRaydiumInstruction(instruction solana.CompiledInstruction, message *solana.Message, index int, result *Transaction, discriminator uint64) error {
-	log.Printf("Attempting to parse generic Raydium instruction (discriminator: %x, accounts: %d, data: %d bytes)",
+	result.logf("Attempting to parse generic Raydium instruction (discriminator: %x, accounts: %d, data: %d bytes)",
 		discriminator, len(instruction.Accounts), len(instruction.Data))
 
 	if len(instruction.Accounts) >= 6 && len(instruction.Data) >= 16 {
-		log.Printf("Parsing as potential swap instruction")
+		result.logf("Parsing as potential swap instruction")
 		return parseAsSwapInstruction(instruction, message, index, result)
 	}
 
 	if len(instruction.Accounts) >= 4 && len(instruction.Data) >= 8 {
-		log.Printf("Parsing as potential create/migrate instruction")
+		result.logf("Parsing as potential create/migrate instruction")
 		return parseAsCreateOrMigrateInstruction(instruction, message, index, result)
 	}
 
-	log.Printf("Unknown Raydium instruction detected but not parsed (insufficient data)")
+	result.logf("Unknown Raydium instruction detected but not parsed (insufficient data)")
 	return nil
 }
 
@@ -1471,15 +1616,15 @@ func parseAsSwapInstruction(instruction solana.CompiledInstruction, message *sol
 	trader := message.AccountKeys[0]
 
 	// Debug: Log account structure for analysis
-	log.Printf("DEBUG: Total accounts in message: %d", len(message.AccountKeys))
-	log.Printf("DEBUG: Instruction accounts: %d", len(instruction.Accounts))
+	result.logf("DEBUG: Total accounts in message: %d", len(message.AccountKeys))
+	result.logf("DEBUG: Instruction accounts: %d", len(instruction.Accounts))
 	for i := 0; i < len(instruction.Accounts) && i < 10; i++ {
 		accountIndex := int(instruction.Accounts[i])
 		if accountIndex < len(message.AccountKeys) {
 			account := message.AccountKeys[accountIndex]
-			log.Printf("DEBUG: Account[%d] = %s", i, account.String())
+			result.logf("DEBUG: Account[%d] = %s", i, account.String())
 		} else {
-			log.Printf("DEBUG: Account index %d out of bounds (max %d)", accountIndex, len(message.AccountKeys)-1)
+			result.logf("DEBUG: Account index %d out of bounds (max %d)", accountIndex, len(message.AccountKeys)-1)
 		}
 	}
 
@@ -1491,12 +1636,12 @@ func parseAsSwapInstruction(instruction solana.CompiledInstruction, message *sol
 
 	if len(instruction.Accounts) > 0 && int(instruction.Accounts[0]) < len(message.AccountKeys) {
 		tokenOut = message.AccountKeys[instruction.Accounts[0]]
-		log.Printf("DEBUG: Found tokenOut: %s", tokenOut.String())
+		result.logf("DEBUG: Found tokenOut: %s", tokenOut.String())
 	}
 
 	if len(instruction.Accounts) > 1 && int(instruction.Accounts[1]) < len(message.AccountKeys) {
 		pool = message.AccountKeys[instruction.Accounts[1]]
-		log.Printf("DEBUG: Found pool: %s", pool.String())
+		result.logf("DEBUG: Found pool: %s", pool.String())
 	}
 
 	// If tokenOut is SOL, this is likely a sell (token -> SOL)
@@ -1505,7 +1650,7 @@ func parseAsSwapInstruction(instruction solana.CompiledInstruction, message *sol
 		if len(instruction.Accounts) > 0 && int(instruction.Accounts[0]) < len(message.AccountKeys) {
 			tokenIn = message.AccountKeys[instruction.Accounts[0]]
 			tokenOut = solMint
-			log.Printf("DEBUG: Detected sell - tokenIn: %s, tokenOut: %s", tokenIn.String(), tokenOut.String())
+			result.logf("DEBUG: Detected sell - tokenIn: %s, tokenOut: %s", tokenIn.String(), tokenOut.String())
 		}
 	}
 
@@ -1537,7 +1682,7 @@ func parseAsSwapInstruction(instruction solana.CompiledInstruction, message *sol
 			Slippage:     0.0, // Would be calculated from actual vs expected amounts
 		}
 		result.SwapBuys = append(result.SwapBuys, swapBuy)
-		log.Printf("Parsed as buy: %d tokens in, %d min out", amountIn, minAmountOut)
+		result.logf("Parsed as buy: %d tokens in, %d min out", amountIn, minAmountOut)
 	} else {
 		result.TradeSells = append(result.TradeSells, index)
 
@@ -1552,7 +1697,7 @@ func parseAsSwapInstruction(instruction solana.CompiledInstruction, message *sol
 			Slippage:     0.0, // Would be calculated from actual vs expected amounts
 		}
 		result.SwapSells = append(result.SwapSells, swapSell)
-		log.Printf("Parsed as sell: %d tokens in, %d min out", amountIn, minAmountOut)
+		result.logf("Parsed as sell: %d tokens in, %d min out", amountIn, minAmountOut)
 	}
 
 	return nil
@@ -1561,12 +1706,12 @@ func parseAsSwapInstruction(instruction solana.CompiledInstruction, message *sol
 func parseAsCreateOrMigrateInstruction(instruction solana.CompiledInstruction, message *solana.Message, index int, result *Transaction) error {
 	// Try to parse as pool creation
 	if len(instruction.Accounts) >= 8 {
-		log.Printf("Parsing as potential pool creation")
+		result.logf("Parsing as potential pool creation")
 		return parseCreatePoolInstruction(instruction, message, index, result)
 	}
 
 	if len(instruction.Accounts) >= 4 {
-		log.Printf("Parsing as potential migration")
+		result.logf("Parsing as potential migration")
 		return parseMigrateInstruction(instruction, message, index, result)
 	}
 
@@ -1582,36 +1727,45 @@ func parseRaydiumLaunchpadInstructionStandard(instruction solana.CompiledInstruc
 	// Get the instruction discriminator (first byte)
 	discriminator := instruction.Data[0]
 
-	log.Printf("Launchpad instruction discriminator: %d at index %d", discriminator, index)
+	result.logf("Launchpad instruction discriminator: %d at index %d", discriminator, index)
+
+	if isLaunchpadMigrateInstruction(instruction.Data) {
+		result.logf("Parsing launchpad migrate_to_amm/migrate_to_cpswap instruction")
+		return parseLaunchpadMigrateInstruction(instruction, message, index, result)
+	}
+	if isLaunchpadInitializeInstruction(instruction.Data) {
+		result.logf("Parsing launchpad initialize instruction")
+		return parseLaunchpadInitializeInstruction(instruction, message, index, result)
+	}
 
 	// Check if this is a complex discriminator (8 bytes)
 	if len(instruction.Data) >= 8 {
 		// Try to parse as 8-byte discriminator used by Anchor programs
 		discriminatorBytes := instruction.Data[:8]
 		if complexDiscriminator := binary.LittleEndian.Uint64(discriminatorBytes); complexDiscriminator != 0 {
-			log.Printf("Launchpad complex discriminator: %x", complexDiscriminator)
+			result.logf("Launchpad complex discriminator: %x", complexDiscriminator)
 			return parseComplexLaunchpadInstruction(instruction, message, index, result, complexDiscriminator)
 		}
 	}
 
 	switch discriminator {
 	case INSTRUCTION_INITIALIZE, INSTRUCTION_INITIALIZE_POOL, INSTRUCTION_CREATE_POOL:
-		log.Printf("Parsing launchpad create/initialize instruction")
+		result.logf("Parsing launchpad create/initialize instruction")
 		return parseCreatePoolInstruction(instruction, message, index, result)
 	case INSTRUCTION_BUY:
-		log.Printf("Parsing launchpad buy instruction")
+		result.logf("Parsing launchpad buy instruction")
 		return parseBuyInstructionStandard(instruction, message, index, result)
 	case INSTRUCTION_SELL:
-		log.Printf("Parsing launchpad sell instruction")
+		result.logf("Parsing launchpad sell instruction")
 		return parseSellInstructionStandard(instruction, message, index, result)
 	case INSTRUCTION_SWAP, INSTRUCTION_SWAP_BASE_IN, INSTRUCTION_SWAP_BASE_OUT:
-		log.Printf("Parsing launchpad swap instruction")
+		result.logf("Parsing launchpad swap instruction")
 		return parseSwapInstruction(instruction, message, index, result)
 	case INSTRUCTION_MIGRATE:
-		log.Printf("Parsing launchpad migrate instruction")
+		result.logf("Parsing launchpad migrate instruction")
 		return parseMigrateInstruction(instruction, message, index, result)
 	default:
-		log.Printf("Unknown Launchpad instruction discriminator: %d", discriminator)
+		result.logf("Unknown Launchpad instruction discriminator: %d", discriminator)
 		// Try to parse as generic launchpad instruction
 		return parseGenericLaunchpadInstruction(instruction, message, index, result, uint64(discriminator))
 	}
@@ -1635,25 +1789,25 @@ func parseComplexLaunchpadInstruction(instruction solana.CompiledInstruction, me
 
 	switch discriminator {
 	case LAUNCHPAD_INITIALIZE:
-		log.Printf("Parsing launchpad initialize with complex discriminator")
+		result.logf("Parsing launchpad initialize with complex discriminator")
 		return parseCreatePoolInstruction(instruction, message, index, result)
 	case LAUNCHPAD_BUY, LAUNCHPAD_REAL_2:
-		log.Printf("Parsing launchpad buy with complex discriminator")
+		result.logf("Parsing launchpad buy with complex discriminator")
 		return parseBuyInstructionStandard(instruction, message, index, result)
 	case LAUNCHPAD_SELL:
-		log.Printf("Parsing launchpad sell with complex discriminator")
+		result.logf("Parsing launchpad sell with complex discriminator")
 		return parseSellInstructionStandard(instruction, message, index, result)
 	case LAUNCHPAD_SWAP:
-		log.Printf("Parsing launchpad swap with complex discriminator")
+		result.logf("Parsing launchpad swap with complex discriminator")
 		return parseSwapInstruction(instruction, message, index, result)
 	case LAUNCHPAD_MIGRATE:
-		log.Printf("Parsing launchpad migrate with complex discriminator")
+		result.logf("Parsing launchpad migrate with complex discriminator")
 		return parseMigrateInstruction(instruction, message, index, result)
 	case LAUNCHPAD_REAL_1, LAUNCHPAD_REAL_3:
-		log.Printf("Parsing launchpad instruction with known real discriminator: %x", discriminator)
+		result.logf("Parsing launchpad instruction with known real discriminator: %x", discriminator)
 		return parseGenericLaunchpadInstruction(instruction, message, index, result, discriminator)
 	default:
-		log.Printf("Unknown complex Launchpad instruction discriminator: %x", discriminator)
+		result.logf("Unknown complex Launchpad instruction discriminator: %x", discriminator)
 		// Try to parse as generic launchpad instruction
 		return parseGenericLaunchpadInstruction(instruction, message, index, result, discriminator)
 	}
@@ -1661,7 +1815,7 @@ func parseComplexLaunchpadInstruction(instruction solana.CompiledInstruction, me
 
 // parseGenericLaunchpadInstruction attempts to parse unknown launchpad instructions
 func parseGenericLaunchpadInstruction(instruction solana.CompiledInstruction, message *solana.Message, index int, result *Transaction, discriminator uint64) error {
-	log.Printf("Attempting to parse generic launchpad instruction (discriminator: %x, accounts: %d, data: %d bytes)",
+	result.logf("Attempting to parse generic launchpad instruction (discriminator: %x, accounts: %d, data: %d bytes)",
 		discriminator, len(instruction.Accounts), len(instruction.Data))
 
 	// Extract instruction data beyond discriminator
@@ -1672,7 +1826,7 @@ func parseGenericLaunchpadInstruction(instruction solana.CompiledInstruction, me
 
 	// Common launchpad patterns analysis
 	if len(instruction.Accounts) >= 8 && len(instruction.Data) >= dataStart+32 {
-		log.Printf("Pattern matches token creation - parsing as create")
+		result.logf("Pattern matches token creation - parsing as create")
 		return parseCreatePoolInstruction(instruction, message, index, result)
 	}
 
@@ -1685,7 +1839,7 @@ func parseGenericLaunchpadInstruction(instruction solana.CompiledInstruction, me
 				amount = binary.LittleEndian.Uint64(instruction.Data[dataStart : dataStart+8])
 			}
 
-			log.Printf("Pattern matches buy/sell - amount: %d", amount)
+			result.logf("Pattern matches buy/sell - amount: %d", amount)
 
 			// Determine if it's buy or sell based on account patterns
 			// This is a heuristic based on common launchpad patterns
@@ -1701,10 +1855,10 @@ func parseGenericLaunchpadInstruction(instruction solana.CompiledInstruction, me
 	}
 
 	if len(instruction.Accounts) >= 4 && len(instruction.Data) >= dataStart+8 {
-		log.Printf("Pattern matches swap/migrate - parsing as swap")
+		result.logf("Pattern matches swap/migrate - parsing as swap")
 		return parseSwapInstruction(instruction, message, index, result)
 	}
 
-	log.Printf("Unable to parse launchpad instruction - insufficient data or unknown pattern")
+	result.logf("Unable to parse launchpad instruction - insufficient data or unknown pattern")
 	return nil
 }
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Program address seeds used by the Raydium programs
+const (
+	AMM_V4_AUTHORITY_SEED                 = "amm authority"
+	CPSWAP_AUTHORITY_SEED                 = "vault_and_lp_mint_auth_seed"
+	LAUNCHPAD_AUTHORITY_SEED              = "vault_auth_seed"
+	ANCHOR_EVENT_AUTHORITY_SEED           = "__event_authority"
+	POOL_SEED                             = "pool"
+	POOL_VAULT_SEED                       = "pool_vault"
+	POOL_LP_MINT_SEED                     = "pool_lp_mint"
+	OBSERVATION_SEED                      = "observation"
+	CLMM_TICK_ARRAY_SEED                  = "tick_array"
+	CLMM_TICK_ARRAY_BITMAP_EXTENSION_SEED = "pool_tick_array_bitmap_extension"
+	LAUNCHPAD_GLOBAL_CONFIG_SEED          = "global_config"
+	LAUNCHPAD_PLATFORM_CONFIG_SEED        = "platform_config"
+	METADATA_SEED                         = "metadata"
+)
+
+// findProgramAddress derives a program address, wrapping the error with what was derived
+func findProgramAddress(name string, programID solana.PublicKey, seeds ...[]byte) (solana.PublicKey, error) {
+	address, _, err := solana.FindProgramAddress(seeds, programID)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("failed to derive %s: %w", name, err)
+	}
+	return address, nil
+}
+
+// sortMints orders two mints the way CP-Swap and CLMM pools store token 0 and token 1
+func sortMints(mintA, mintB solana.PublicKey) (solana.PublicKey, solana.PublicKey) {
+	if bytes.Compare(mintA[:], mintB[:]) > 0 {
+		return mintB, mintA
+	}
+	return mintA, mintB
+}
+
+// DeriveAssociatedTokenAddress derives the wallet's associated token account for a mint
+// owned by tokenProgram, which may be the Token or Token-2022 program
+func DeriveAssociatedTokenAddress(wallet, mint, tokenProgram solana.PublicKey) (solana.PublicKey, error) {
+	return findProgramAddress("associated token account", AssociatedTokenProgramID, wallet[:], tokenProgram[:], mint[:])
+}
+
+// DeriveMetadataAddress derives the Metaplex metadata account of a mint
+func DeriveMetadataAddress(mint solana.PublicKey) (solana.PublicKey, error) {
+	return findProgramAddress("metadata account", MetaplexTokenMetadataProgramID,
+		[]byte(METADATA_SEED), MetaplexTokenMetadataProgramID[:], mint[:])
+}
+
+// DeriveEventAuthority derives the Anchor event authority of a program
+func DeriveEventAuthority(programID solana.PublicKey) (solana.PublicKey, error) {
+	return findProgramAddress("event authority", programID, []byte(ANCHOR_EVENT_AUTHORITY_SEED))
+}
+
+// DeriveAmmV4Authority derives the AMM v4 pool authority
+func DeriveAmmV4Authority(programID solana.PublicKey) (solana.PublicKey, error) {
+	return findProgramAddress("AMM v4 authority", programID, []byte(AMM_V4_AUTHORITY_SEED))
+}
+
+// DeriveCpSwapAuthority derives the CP-Swap vault and LP mint authority
+func DeriveCpSwapAuthority(programID solana.PublicKey) (solana.PublicKey, error) {
+	return findProgramAddress("CP-Swap authority", programID, []byte(CPSWAP_AUTHORITY_SEED))
+}
+
+// DeriveCpSwapPoolState derives the CP-Swap pool for an AmmConfig and mint pair, in either order
+func DeriveCpSwapPoolState(programID, ammConfig, mintA, mintB solana.PublicKey) (solana.PublicKey, error) {
+	mint0, mint1 := sortMints(mintA, mintB)
+	return findProgramAddress("CP-Swap pool state", programID, []byte(POOL_SEED), ammConfig[:], mint0[:], mint1[:])
+}
+
+// DeriveCpSwapVault derives the CP-Swap pool vault holding mint
+func DeriveCpSwapVault(programID, poolState, mint solana.PublicKey) (solana.PublicKey, error) {
+	return findProgramAddress("CP-Swap vault", programID, []byte(POOL_VAULT_SEED), poolState[:], mint[:])
+}
+
+// DeriveCpSwapLpMint derives the CP-Swap pool's LP mint
+func DeriveCpSwapLpMint(programID, poolState solana.PublicKey) (solana.PublicKey, error) {
+	return findProgramAddress("CP-Swap LP mint", programID, []byte(POOL_LP_MINT_SEED), poolState[:])
+}
+
+// DeriveCpSwapObservationState derives the CP-Swap pool's observation account
+func DeriveCpSwapObservationState(programID, poolState solana.PublicKey) (solana.PublicKey, error) {
+	return findProgramAddress("CP-Swap observation state", programID, []byte(OBSERVATION_SEED), poolState[:])
+}
+
+// DeriveClmmPoolState derives the CLMM pool for an AmmConfig and mint pair, in either order
+func DeriveClmmPoolState(programID, ammConfig, mintA, mintB solana.PublicKey) (solana.PublicKey, error) {
+	mint0, mint1 := sortMints(mintA, mintB)
+	return findProgramAddress("CLMM pool state", programID, []byte(POOL_SEED), ammConfig[:], mint0[:], mint1[:])
+}
+
+// DeriveClmmVault derives the CLMM pool vault holding mint
+func DeriveClmmVault(programID, poolState, mint solana.PublicKey) (solana.PublicKey, error) {
+	return findProgramAddress("CLMM vault", programID, []byte(POOL_VAULT_SEED), poolState[:], mint[:])
+}
+
+// DeriveClmmObservationState derives the CLMM pool's observation account
+func DeriveClmmObservationState(programID, poolState solana.PublicKey) (solana.PublicKey, error) {
+	return findProgramAddress("CLMM observation state", programID, []byte(OBSERVATION_SEED), poolState[:])
+}
+
+// DeriveClmmTickArray derives the CLMM tick array starting at startTickIndex
+func DeriveClmmTickArray(programID, poolState solana.PublicKey, startTickIndex int32) (solana.PublicKey, error) {
+	index := binary.BigEndian.AppendUint32(nil, uint32(startTickIndex))
+	return findProgramAddress("CLMM tick array", programID, []byte(CLMM_TICK_ARRAY_SEED), poolState[:], index)
+}
+
+// DeriveClmmTickArrayBitmapExtension derives the CLMM pool's tick array bitmap extension
+func DeriveClmmTickArrayBitmapExtension(programID, poolState solana.PublicKey) (solana.PublicKey, error) {
+	return findProgramAddress("CLMM tick array bitmap extension", programID,
+		[]byte(CLMM_TICK_ARRAY_BITMAP_EXTENSION_SEED), poolState[:])
+}
+
+// DeriveLaunchpadAuthority derives the Launchpad vault authority
+func DeriveLaunchpadAuthority(programID solana.PublicKey) (solana.PublicKey, error) {
+	return findProgramAddress("Launchpad authority", programID, []byte(LAUNCHPAD_AUTHORITY_SEED))
+}
+
+// DeriveLaunchpadGlobalConfig derives the Launchpad global config for a quote mint, curve type and index
+func DeriveLaunchpadGlobalConfig(programID, quoteMint solana.PublicKey, curveType uint8, index uint16) (solana.PublicKey, error) {
+	return findProgramAddress("Launchpad global config", programID, []byte(LAUNCHPAD_GLOBAL_CONFIG_SEED),
+		quoteMint[:], []byte{curveType}, binary.BigEndian.AppendUint16(nil, index))
+}
+
+// DeriveLaunchpadPlatformConfig derives the platform config owned by a platform admin
+func DeriveLaunchpadPlatformConfig(programID, platformAdmin solana.PublicKey) (solana.PublicKey, error) {
+	return findProgramAddress("Launchpad platform config", programID, []byte(LAUNCHPAD_PLATFORM_CONFIG_SEED), platformAdmin[:])
+}
+
+// DeriveLaunchpadPoolState derives the Launchpad pool for a base and quote mint
+func DeriveLaunchpadPoolState(programID, baseMint, quoteMint solana.PublicKey) (solana.PublicKey, error) {
+	return findProgramAddress("Launchpad pool state", programID, []byte(POOL_SEED), baseMint[:], quoteMint[:])
+}
+
+// DeriveLaunchpadVault derives the Launchpad pool vault holding mint
+func DeriveLaunchpadVault(programID, poolState, mint solana.PublicKey) (solana.PublicKey, error) {
+	return findProgramAddress("Launchpad vault", programID, []byte(POOL_VAULT_SEED), poolState[:], mint[:])
+}
+
+// deriveIfZero fills *account from derive when it is unset and every input is set.
+// A failed derivation leaves the account unset for Validate to report.
+func deriveIfZero(account *solana.PublicKey, derive func() (solana.PublicKey, error), inputs ...solana.PublicKey) {
+	if !account.IsZero() {
+		return
+	}
+	for _, input := range inputs {
+		if input.IsZero() {
+			return
+		}
+	}
+	if address, err := derive(); err == nil {
+		*account = address
+	}
+}
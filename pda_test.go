@@ -0,0 +1,137 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+func TestDeriveWellKnownAddresses(t *testing.T) {
+	usdc := solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")
+	cpSwapAmmConfig := solana.MustPublicKeyFromBase58("D4FPEruKEHrG5TenZ2mpDGEfu1iUvTiqBxvpU8HLBvC2")
+
+	tests := []struct {
+		name     string
+		derive   func() (solana.PublicKey, error)
+		expected solana.PublicKey
+	}{
+		{"AMM v4 authority", func() (solana.PublicKey, error) { return DeriveAmmV4Authority(RaydiumV4ProgramID) }, AmmV4AuthorityID},
+		{"CP-Swap authority", func() (solana.PublicKey, error) { return DeriveCpSwapAuthority(RaydiumCpSwapProgramID) }, CpSwapAuthorityID},
+		{"Launchpad authority", func() (solana.PublicKey, error) { return DeriveLaunchpadAuthority(RaydiumLaunchpadV1ProgramID) }, LaunchpadAuthorityID},
+		{"Launchpad event authority", func() (solana.PublicKey, error) { return DeriveEventAuthority(RaydiumLaunchpadV1ProgramID) }, LaunchpadEventAuthorityID},
+		{"Launchpad global config", func() (solana.PublicKey, error) {
+			return DeriveLaunchpadGlobalConfig(RaydiumLaunchpadV1ProgramID, solana.SolMint, LAUNCHPAD_CURVE_CONSTANT_PRODUCT, 0)
+		}, LaunchpadGlobalConfigID},
+		{"CP-Swap SOL-USDC pool", func() (solana.PublicKey, error) {
+			return DeriveCpSwapPoolState(RaydiumCpSwapProgramID, cpSwapAmmConfig, usdc, solana.SolMint)
+		}, solana.MustPublicKeyFromBase58("7JuwJuNU88gurFnyWeiyGKbFmExMWcmRZntn9imEzdny")},
+		{"wSOL metadata", func() (solana.PublicKey, error) { return DeriveMetadataAddress(solana.SolMint) },
+			solana.MustPublicKeyFromBase58("6dM4TqWyWJsbx7obrdLcviBkTafD5E8av61zfU6jq57X")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address, err := tt.derive()
+			if err != nil {
+				t.Fatalf("Failed to derive: %v", err)
+			}
+			if !address.Equals(tt.expected) {
+				t.Errorf("Expected %s, got %s", tt.expected, address)
+			}
+		})
+	}
+}
+
+func TestDeriveAssociatedTokenAddress(t *testing.T) {
+	wallet := solana.MustPublicKeyFromBase58("HN7cABqLq46Es1jh92dQQisAq662SmxELLLsHHe4YWrH")
+	mint := solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")
+
+	classic, err := DeriveAssociatedTokenAddress(wallet, mint, TokenProgramID)
+	if err != nil {
+		t.Fatalf("Failed to derive token account: %v", err)
+	}
+	expected, _, _ := solana.FindAssociatedTokenAddress(wallet, mint)
+	if !classic.Equals(expected) {
+		t.Errorf("Expected %s, got %s", expected, classic)
+	}
+
+	token2022, err := DeriveAssociatedTokenAddress(wallet, mint, Token2022ProgramID)
+	if err != nil {
+		t.Fatalf("Failed to derive Token-2022 account: %v", err)
+	}
+	if token2022.Equals(classic) {
+		t.Errorf("Token-2022 account should differ from the Token account")
+	}
+}
+
+func TestBuildersDeriveUnsetAccounts(t *testing.T) {
+	keys := uniqueAccounts(4)
+	payer, ammConfig, mintA, mintB := keys[0], keys[1], keys[2], keys[3]
+
+	// CP-Swap with only the payer, config and mints
+	instruction, err := NewCpSwapInstruction().
+		SetPayer(payer).SetAmmConfig(ammConfig).SetInputMint(mintA).SetOutputMint(mintB).
+		SetOutputTokenProgram(Token2022ProgramID).
+		SetAmountIn(1_000).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build CP-Swap swap: %v", err)
+	}
+	pool, _ := DeriveCpSwapPoolState(RaydiumCpSwapProgramID, ammConfig, mintB, mintA)
+	inputVault, _ := DeriveCpSwapVault(RaydiumCpSwapProgramID, pool, mintA)
+	outputAccount, _ := DeriveAssociatedTokenAddress(payer, mintB, Token2022ProgramID)
+	observation, _ := DeriveCpSwapObservationState(RaydiumCpSwapProgramID, pool)
+	accounts := instruction.Accounts()
+	if !accounts[1].PublicKey.Equals(CpSwapAuthorityID) || !accounts[3].PublicKey.Equals(pool) ||
+		!accounts[5].PublicKey.Equals(outputAccount) || !accounts[6].PublicKey.Equals(inputVault) ||
+		!accounts[12].PublicKey.Equals(observation) {
+		t.Errorf("CP-Swap accounts were not derived")
+	}
+
+	// Explicit accounts win over derived ones
+	instruction, err = NewCpSwapInstruction().
+		SetPayer(payer).SetAmmConfig(ammConfig).SetInputMint(mintA).SetOutputMint(mintB).
+		SetPoolState(keys[1]).
+		SetAmountIn(1_000).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build CP-Swap swap: %v", err)
+	}
+	if !instruction.Accounts()[3].PublicKey.Equals(keys[1]) {
+		t.Errorf("Explicit pool state was replaced")
+	}
+
+	// Launchpad buy with only the payer, platform config and base mint
+	instruction, err = NewBuyInstruction().
+		SetPayer(payer).SetPlatformConfig(LetsBonkPlatformConfigID).SetBaseMint(mintA).
+		SetAmountIn(1_000).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build Launchpad buy: %v", err)
+	}
+	launchpadPool, _ := DeriveLaunchpadPoolState(RaydiumLaunchpadV1ProgramID, mintA, solana.SolMint)
+	quoteVault, _ := DeriveLaunchpadVault(RaydiumLaunchpadV1ProgramID, launchpadPool, solana.SolMint)
+	userQuote, _ := DeriveAssociatedTokenAddress(payer, solana.SolMint, TokenProgramID)
+	accounts = instruction.Accounts()
+	if !accounts[1].PublicKey.Equals(LaunchpadAuthorityID) || !accounts[4].PublicKey.Equals(launchpadPool) ||
+		!accounts[6].PublicKey.Equals(userQuote) || !accounts[8].PublicKey.Equals(quoteVault) ||
+		!accounts[13].PublicKey.Equals(LaunchpadEventAuthorityID) {
+		t.Errorf("Launchpad buy accounts were not derived")
+	}
+
+	// Launchpad initialize derives the pool, vaults and metadata from the new mint
+	instruction, err = NewCreateTokenInstruction().
+		SetPayer(payer).SetCreator(payer).SetPlatformConfig(LetsBonkPlatformConfigID).SetBaseMint(mintA).
+		SetName("Test").SetSymbol("TST").
+		SetSupply(1_000).SetTotalBaseSell(800).SetTotalQuoteFundRaising(85).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build Launchpad initialize: %v", err)
+	}
+	metadata, _ := DeriveMetadataAddress(mintA)
+	accounts = instruction.Accounts()
+	if !accounts[initializePoolState].PublicKey.Equals(launchpadPool) || !accounts[9].PublicKey.Equals(quoteVault) ||
+		!accounts[initializeMetadata].PublicKey.Equals(metadata) {
+		t.Errorf("Launchpad initialize accounts were not derived")
+	}
+}
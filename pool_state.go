@@ -0,0 +1,254 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// AMM v4 LiquidityStateV4 account size
+const AMM_V4_POOL_STATE_SIZE = 752
+
+// CP-Swap PoolState account size. Launchpad's PoolState shares the Anchor discriminator,
+// so the size is what tells the two apart.
+const CPSWAP_POOL_STATE_SIZE = 637
+
+// AMM v4 pool status values stored in LiquidityStateV4.status
+const (
+	AMM_V4_STATUS_UNINITIALIZED  = 0
+	AMM_V4_STATUS_INITIALIZED    = 1
+	AMM_V4_STATUS_DISABLED       = 2
+	AMM_V4_STATUS_WITHDRAW_ONLY  = 3
+	AMM_V4_STATUS_LIQUIDITY_ONLY = 4
+	AMM_V4_STATUS_ORDERBOOK_ONLY = 5
+	AMM_V4_STATUS_SWAP_ONLY      = 6
+	AMM_V4_STATUS_WAITING_TRADE  = 7
+)
+
+// CP-Swap pool status bits stored in PoolState.status; a set bit disables the operation
+const (
+	CPSWAP_STATUS_DEPOSIT_DISABLED  = 1 << 0
+	CPSWAP_STATUS_WITHDRAW_DISABLED = 1 << 1
+	CPSWAP_STATUS_SWAP_DISABLED     = 1 << 2
+)
+
+var (
+	cpSwapPoolStateDiscriminator = anchorAccountDiscriminator("PoolState")
+	cpSwapAmmConfigDiscriminator = anchorAccountDiscriminator("AmmConfig")
+)
+
+// PoolInfo represents pool information decoded from an AMM v4 or CP-Swap pool account.
+// TokenA is the base (AMM v4 coin, CP-Swap token 0) and TokenB the quote (pc, token 1).
+type PoolInfo struct {
+	Address     solana.PublicKey
+	TokenA      solana.PublicKey
+	TokenB      solana.PublicKey
+	TokenAVault solana.PublicKey
+	TokenBVault solana.PublicKey
+	LpMint      solana.PublicKey
+	// Fee is the swap fee numerator over FeeDenominator
+	Fee uint64
+
+	ProgramID      solana.PublicKey
+	Status         uint64
+	OpenTime       uint64
+	TokenADecimals uint8
+	TokenBDecimals uint8
+	LpDecimals     uint8
+	FeeDenominator uint64
+
+	// TokenAReserved and TokenBReserved are vault balances that are not tradable liquidity:
+	// pending PnL on AMM v4, accrued protocol and fund fees on CP-Swap
+	TokenAReserved uint64
+	TokenBReserved uint64
+
+	// AMM v4 only
+	OpenOrders      solana.PublicKey
+	TargetOrders    solana.PublicKey
+	MarketID        solana.PublicKey
+	MarketProgramID solana.PublicKey
+	LpReserve       uint64
+
+	// CP-Swap only
+	AmmConfig       solana.PublicKey
+	PoolCreator     solana.PublicKey
+	ObservationKey  solana.PublicKey
+	TokenAProgram   solana.PublicKey
+	TokenBProgram   solana.PublicKey
+	LpSupply        uint64
+	ProtocolFeeRate uint64
+	FundFeeRate     uint64
+}
+
+// CpSwapAmmConfig represents a decoded CP-Swap AmmConfig account
+type CpSwapAmmConfig struct {
+	Address           solana.PublicKey
+	Bump              uint8
+	DisableCreatePool bool
+	Index             uint16
+	TradeFeeRate      uint64
+	ProtocolFeeRate   uint64
+	FundFeeRate       uint64
+	CreatePoolFee     uint64
+	ProtocolOwner     solana.PublicKey
+	FundOwner         solana.PublicKey
+}
+
+// DecodeAmmV4PoolState decodes a raw AMM v4 LiquidityStateV4 account. The account has no
+// discriminator, so only its size is checked.
+func DecodeAmmV4PoolState(data []byte) (*PoolInfo, error) {
+	if len(data) != AMM_V4_POOL_STATE_SIZE {
+		return nil, fmt.Errorf("failed to decode amm v4 pool state: expected %d bytes, got %d", AMM_V4_POOL_STATE_SIZE, len(data))
+	}
+
+	r := newAccountReader(data)
+	pool := &PoolInfo{ProgramID: RaydiumV4ProgramID}
+	pool.Status = r.u64()
+	r.skip(8 * 3) // nonce, max_order, depth
+	pool.TokenADecimals = uint8(r.u64())
+	pool.TokenBDecimals = uint8(r.u64())
+	r.skip(8 * 12) // state, reset_flag, min_size, vol_max_cut_ratio, amount_wave_ratio, lot sizes, price multipliers, sys_decimal_value, min_separate fraction
+	r.skip(8 * 2)  // trade_fee numerator/denominator (order book fills)
+	r.skip(8 * 2)  // pnl numerator/denominator
+	pool.Fee = r.u64()
+	pool.FeeDenominator = r.u64()
+	pool.TokenAReserved = r.u64() // base_need_take_pnl
+	pool.TokenBReserved = r.u64() // quote_need_take_pnl
+	r.skip(8 * 2)                 // quote_total_pnl, base_total_pnl
+	pool.OpenTime = r.u64()
+	r.skip(8 * 3)  // punish amounts, orderbook_to_init_time
+	r.skip(16 * 4) // swap volume u128s
+	r.skip(8 * 2)  // swap fee totals
+	pool.TokenAVault = r.pubkey()
+	pool.TokenBVault = r.pubkey()
+	pool.TokenA = r.pubkey()
+	pool.TokenB = r.pubkey()
+	pool.LpMint = r.pubkey()
+	pool.OpenOrders = r.pubkey()
+	pool.MarketID = r.pubkey()
+	pool.MarketProgramID = r.pubkey()
+	pool.TargetOrders = r.pubkey()
+	r.skip(32 * 3) // withdraw_queue, lp_vault, owner
+	pool.LpReserve = r.u64()
+	pool.LpDecimals = pool.TokenADecimals
+
+	if r.err != nil {
+		return nil, fmt.Errorf("failed to decode amm v4 pool state: %w", r.err)
+	}
+	return pool, nil
+}
+
+// DecodeCpSwapPoolState decodes a raw CP-Swap PoolState account. The trade fee lives in the
+// pool's AmmConfig and is filled by ApplyAmmConfig.
+func DecodeCpSwapPoolState(data []byte) (*PoolInfo, error) {
+	if len(data) != CPSWAP_POOL_STATE_SIZE {
+		return nil, fmt.Errorf("failed to decode cp-swap pool state: expected %d bytes, got %d", CPSWAP_POOL_STATE_SIZE, len(data))
+	}
+	r := newAccountReader(data)
+	r.anchorDiscriminator(cpSwapPoolStateDiscriminator, "CP-Swap PoolState")
+
+	pool := &PoolInfo{ProgramID: RaydiumCpSwapProgramID}
+	pool.AmmConfig = r.pubkey()
+	pool.PoolCreator = r.pubkey()
+	pool.TokenAVault = r.pubkey()
+	pool.TokenBVault = r.pubkey()
+	pool.LpMint = r.pubkey()
+	pool.TokenA = r.pubkey()
+	pool.TokenB = r.pubkey()
+	pool.TokenAProgram = r.pubkey()
+	pool.TokenBProgram = r.pubkey()
+	pool.ObservationKey = r.pubkey()
+	r.skip(1) // auth_bump
+	pool.Status = uint64(r.u8())
+	pool.LpDecimals = r.u8()
+	pool.TokenADecimals = r.u8()
+	pool.TokenBDecimals = r.u8()
+	pool.LpSupply = r.u64()
+	protocolFeesA, protocolFeesB := r.u64(), r.u64()
+	fundFeesA, fundFeesB := r.u64(), r.u64()
+	pool.OpenTime = r.u64()
+
+	if r.err != nil {
+		return nil, fmt.Errorf("failed to decode cp-swap pool state: %w", r.err)
+	}
+	pool.TokenAReserved = protocolFeesA + fundFeesA
+	pool.TokenBReserved = protocolFeesB + fundFeesB
+	return pool, nil
+}
+
+// DecodeCpSwapAmmConfig decodes a raw CP-Swap AmmConfig account
+func DecodeCpSwapAmmConfig(data []byte) (*CpSwapAmmConfig, error) {
+	r := newAccountReader(data)
+	r.anchorDiscriminator(cpSwapAmmConfigDiscriminator, "CP-Swap AmmConfig")
+
+	config := &CpSwapAmmConfig{}
+	config.Bump = r.u8()
+	config.DisableCreatePool = r.u8() != 0
+	config.Index = r.u16()
+	config.TradeFeeRate = r.u64()
+	config.ProtocolFeeRate = r.u64()
+	config.FundFeeRate = r.u64()
+	config.CreatePoolFee = r.u64()
+	config.ProtocolOwner = r.pubkey()
+	config.FundOwner = r.pubkey()
+
+	if r.err != nil {
+		return nil, fmt.Errorf("failed to decode cp-swap amm config: %w", r.err)
+	}
+	return config, nil
+}
+
+// ApplyAmmConfig copies the fee rates from a CP-Swap AmmConfig onto the pool
+func (p *PoolInfo) ApplyAmmConfig(config *CpSwapAmmConfig) *PoolInfo {
+	if config != nil {
+		p.Fee = config.TradeFeeRate
+		p.FeeDenominator = CPSWAP_FEE_RATE_DENOMINATOR
+		p.ProtocolFeeRate = config.ProtocolFeeRate
+		p.FundFeeRate = config.FundFeeRate
+	}
+	return p
+}
+
+// IsAmmV4 reports whether the pool was decoded from an AMM v4 account
+func (p *PoolInfo) IsAmmV4() bool {
+	return p.ProgramID.Equals(RaydiumV4ProgramID)
+}
+
+// CanSwap reports whether the pool accepts swaps at the given unix time
+func (p *PoolInfo) CanSwap(now int64) bool {
+	if now >= 0 && uint64(now) < p.OpenTime {
+		return false
+	}
+	if p.IsAmmV4() {
+		switch p.Status {
+		case AMM_V4_STATUS_INITIALIZED, AMM_V4_STATUS_SWAP_ONLY, AMM_V4_STATUS_WAITING_TRADE:
+			return true
+		default:
+			return false
+		}
+	}
+	return p.Status&CPSWAP_STATUS_SWAP_DISABLED == 0
+}
+
+// ConstantProductPool returns a quotable pool from the current vault balances, excluding
+// the reserved amounts that are not tradable liquidity
+func (p *PoolInfo) ConstantProductPool(tokenAVaultBalance, tokenBVaultBalance uint64) (ConstantProductPool, error) {
+	if tokenAVaultBalance < p.TokenAReserved || tokenBVaultBalance < p.TokenBReserved {
+		return ConstantProductPool{}, fmt.Errorf("%w: vault balances %d/%d below reserved %d/%d",
+			ErrPoolInsufficientLiquidity, tokenAVaultBalance, tokenBVaultBalance, p.TokenAReserved, p.TokenBReserved)
+	}
+	if p.FeeDenominator == 0 {
+		return ConstantProductPool{}, fmt.Errorf("%w: pool fee not set", ErrPoolInvalidFee)
+	}
+
+	return ConstantProductPool{
+		ProgramID:      p.ProgramID,
+		Address:        p.Address,
+		BaseMint:       p.TokenA,
+		QuoteMint:      p.TokenB,
+		BaseReserve:    tokenAVaultBalance - p.TokenAReserved,
+		QuoteReserve:   tokenBVaultBalance - p.TokenBReserved,
+		FeeNumerator:   p.Fee,
+		FeeDenominator: p.FeeDenominator,
+	}, nil
+}
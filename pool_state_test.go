@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// sampleAmmV4PoolAccount encodes a LiquidityStateV4 account and returns it with the keys used
+func sampleAmmV4PoolAccount() ([]byte, []solana.PublicKey) {
+	keys := uniqueAccounts(12)
+	data := make([]byte, 0, AMM_V4_POOL_STATE_SIZE)
+
+	u64s := make([]uint64, 32)
+	u64s[0] = AMM_V4_STATUS_SWAP_ONLY
+	u64s[4] = 9       // base decimals
+	u64s[5] = 6       // quote decimals
+	u64s[22] = 25     // swap fee numerator
+	u64s[23] = 10_000 // swap fee denominator
+	u64s[24] = 1_000  // base need take pnl
+	u64s[25] = 2_000  // quote need take pnl
+	u64s[28] = 1700000000
+	for _, v := range u64s {
+		data = binary.LittleEndian.AppendUint64(data, v)
+	}
+	data = append(data, make([]byte, 80)...) // swap stats
+	for _, key := range keys {
+		data = append(data, key[:]...)
+	}
+	data = binary.LittleEndian.AppendUint64(data, 123_456) // lp reserve
+	data = append(data, make([]byte, 24)...)
+	return data, keys
+}
+
+func TestDecodeAmmV4PoolState(t *testing.T) {
+	data, keys := sampleAmmV4PoolAccount()
+	if len(data) != AMM_V4_POOL_STATE_SIZE {
+		t.Fatalf("Sample account is %d bytes, want %d", len(data), AMM_V4_POOL_STATE_SIZE)
+	}
+
+	pool, err := DecodeAmmV4PoolState(data)
+	if err != nil {
+		t.Fatalf("Failed to decode pool: %v", err)
+	}
+
+	if pool.Status != AMM_V4_STATUS_SWAP_ONLY || pool.OpenTime != 1700000000 {
+		t.Errorf("Unexpected status %d / open time %d", pool.Status, pool.OpenTime)
+	}
+	if pool.TokenADecimals != 9 || pool.TokenBDecimals != 6 {
+		t.Errorf("Unexpected decimals %d/%d", pool.TokenADecimals, pool.TokenBDecimals)
+	}
+	if pool.Fee != 25 || pool.FeeDenominator != 10_000 {
+		t.Errorf("Unexpected fee %d/%d", pool.Fee, pool.FeeDenominator)
+	}
+	if !pool.TokenAVault.Equals(keys[0]) || !pool.TokenBVault.Equals(keys[1]) {
+		t.Errorf("Wrong vaults")
+	}
+	if !pool.TokenA.Equals(keys[2]) || !pool.TokenB.Equals(keys[3]) || !pool.LpMint.Equals(keys[4]) {
+		t.Errorf("Wrong mints")
+	}
+	if !pool.OpenOrders.Equals(keys[5]) || !pool.MarketID.Equals(keys[6]) ||
+		!pool.MarketProgramID.Equals(keys[7]) || !pool.TargetOrders.Equals(keys[8]) {
+		t.Errorf("Wrong market accounts")
+	}
+	if pool.LpReserve != 123_456 {
+		t.Errorf("Expected LP reserve 123456, got %d", pool.LpReserve)
+	}
+
+	if !pool.CanSwap(1700000001) || pool.CanSwap(1600000000) {
+		t.Errorf("CanSwap does not respect open time")
+	}
+
+	cp, err := pool.ConstantProductPool(101_000, 202_000)
+	if err != nil {
+		t.Fatalf("Failed to build quotable pool: %v", err)
+	}
+	if cp.BaseReserve != 100_000 || cp.QuoteReserve != 200_000 {
+		t.Errorf("Expected reserves net of pending PnL, got %d/%d", cp.BaseReserve, cp.QuoteReserve)
+	}
+
+	if _, err := DecodeAmmV4PoolState(data[:700]); err == nil {
+		t.Errorf("Expected error for short account")
+	}
+}
+
+func TestDecodeCpSwapPoolStateAndConfig(t *testing.T) {
+	keys := uniqueAccounts(10)
+	data := append([]byte{}, cpSwapPoolStateDiscriminator[:]...)
+	for _, key := range keys {
+		data = append(data, key[:]...)
+	}
+	data = append(data, 255, CPSWAP_STATUS_DEPOSIT_DISABLED, 9, 6, 9)
+	for _, v := range []uint64{5_000_000, 10, 20, 1, 2, 1710000000, 600} {
+		data = binary.LittleEndian.AppendUint64(data, v)
+	}
+	data = append(data, make([]byte, 31*8)...)
+
+	pool, err := DecodeCpSwapPoolState(data)
+	if err != nil {
+		t.Fatalf("Failed to decode pool: %v", err)
+	}
+	if !pool.AmmConfig.Equals(keys[0]) || !pool.TokenA.Equals(keys[5]) || !pool.TokenB.Equals(keys[6]) {
+		t.Errorf("Wrong config or mints")
+	}
+	if !pool.ObservationKey.Equals(keys[9]) || !pool.TokenAProgram.Equals(keys[7]) {
+		t.Errorf("Wrong observation or token program")
+	}
+	if pool.TokenADecimals != 6 || pool.TokenBDecimals != 9 || pool.LpSupply != 5_000_000 {
+		t.Errorf("Unexpected decimals %d/%d or LP supply %d", pool.TokenADecimals, pool.TokenBDecimals, pool.LpSupply)
+	}
+	if pool.TokenAReserved != 11 || pool.TokenBReserved != 22 {
+		t.Errorf("Expected reserved fees 11/22, got %d/%d", pool.TokenAReserved, pool.TokenBReserved)
+	}
+	if !pool.CanSwap(1710000000) {
+		t.Errorf("Deposit-disabled pool should still swap")
+	}
+	if _, err := pool.ConstantProductPool(1_000, 1_000); err == nil {
+		t.Errorf("Expected error before the AmmConfig fee is applied")
+	}
+
+	config := append([]byte{}, cpSwapAmmConfigDiscriminator[:]...)
+	config = append(config, 254, 0, 1, 0)
+	for _, v := range []uint64{2_500, 120_000, 40_000, 150_000_000} {
+		config = binary.LittleEndian.AppendUint64(config, v)
+	}
+	config = append(config, make([]byte, 64+16*8)...)
+
+	ammConfig, err := DecodeCpSwapAmmConfig(config)
+	if err != nil {
+		t.Fatalf("Failed to decode amm config: %v", err)
+	}
+	if ammConfig.Index != 1 || ammConfig.TradeFeeRate != 2_500 || ammConfig.CreatePoolFee != 150_000_000 {
+		t.Errorf("Unexpected config %+v", ammConfig)
+	}
+
+	pool.ApplyAmmConfig(ammConfig)
+	cp, err := pool.ConstantProductPool(1_000_011, 2_000_022)
+	if err != nil {
+		t.Fatalf("Failed to build quotable pool: %v", err)
+	}
+	if cp.FeeNumerator != 2_500 || cp.FeeDenominator != CPSWAP_FEE_RATE_DENOMINATOR {
+		t.Errorf("Unexpected fee %d/%d", cp.FeeNumerator, cp.FeeDenominator)
+	}
+	if cp.BaseReserve != 1_000_000 || cp.QuoteReserve != 2_000_000 {
+		t.Errorf("Unexpected reserves %d/%d", cp.BaseReserve, cp.QuoteReserve)
+	}
+
+	if _, err := DecodeCpSwapPoolState(config); err == nil {
+		t.Errorf("Expected error decoding AmmConfig as PoolState")
+	}
+	// A Launchpad PoolState carries the same discriminator
+	if _, err := DecodeCpSwapPoolState(encodeLaunchpadPoolState(sampleLaunchpadPoolState())); err == nil {
+		t.Errorf("Expected a Launchpad PoolState to be rejected")
+	}
+}
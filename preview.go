@@ -0,0 +1,323 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Fee parameters of the runtime
+const (
+	LAMPORTS_PER_SIGNATURE                 = 5000
+	DEFAULT_INSTRUCTION_COMPUTE_UNIT_LIMIT = 200_000
+	MAX_COMPUTE_UNIT_LIMIT                 = 1_400_000
+	MICRO_LAMPORTS_PER_LAMPORT             = 1_000_000
+)
+
+// Layout of the Token and Token-2022 account base state
+const (
+	tokenAccountMintOffset   = 0
+	tokenAccountOwnerOffset  = 32
+	tokenAccountAmountOffset = 64
+	tokenAccountBaseSize     = 165
+)
+
+// FeeEstimate is the fee a transaction will be charged, derived from its signatures and
+// compute budget instructions
+type FeeEstimate struct {
+	SignatureFee     uint64
+	PriorityFee      uint64
+	ComputeUnitLimit uint32
+	// ComputeUnitPrice is in micro-lamports per compute unit
+	ComputeUnitPrice uint64
+}
+
+// Total returns the signature and priority fees together, in lamports
+func (f FeeEstimate) Total() uint64 {
+	return f.SignatureFee + f.PriorityFee
+}
+
+// EstimateFee computes the fee of tx. The priority fee is charged on the requested compute
+// unit limit, which defaults to 200k units per non compute budget instruction.
+func EstimateFee(tx *solana.Transaction) FeeEstimate {
+	fee := FeeEstimate{SignatureFee: uint64(tx.Message.Header.NumRequiredSignatures) * LAMPORTS_PER_SIGNATURE}
+
+	limitSet := false
+	otherInstructions := 0
+	for _, instruction := range tx.Message.Instructions {
+		programID, err := tx.Message.Program(instruction.ProgramIDIndex)
+		if err != nil || !programID.Equals(ComputeBudgetProgramID) || len(instruction.Data) == 0 {
+			otherInstructions++
+			continue
+		}
+		data := instruction.Data
+		switch data[0] {
+		case COMPUTE_BUDGET_INSTRUCTION_SET_COMPUTE_UNIT_LIMIT:
+			if len(data) >= 5 {
+				fee.ComputeUnitLimit = binary.LittleEndian.Uint32(data[1:5])
+				limitSet = true
+			}
+		case COMPUTE_BUDGET_INSTRUCTION_SET_COMPUTE_UNIT_PRICE:
+			if len(data) >= 9 {
+				fee.ComputeUnitPrice = binary.LittleEndian.Uint64(data[1:9])
+			}
+		}
+	}
+	if !limitSet {
+		fee.ComputeUnitLimit = uint32(min(otherInstructions*DEFAULT_INSTRUCTION_COMPUTE_UNIT_LIMIT, MAX_COMPUTE_UNIT_LIMIT))
+	}
+	fee.ComputeUnitLimit = min(fee.ComputeUnitLimit, MAX_COMPUTE_UNIT_LIMIT)
+	fee.PriorityFee = mulDivCeil(fee.ComputeUnitPrice, uint64(fee.ComputeUnitLimit), MICRO_LAMPORTS_PER_LAMPORT)
+	return fee
+}
+
+// AccountChange is an account's state before and after a simulated transaction
+type AccountChange struct {
+	Address        solana.PublicKey
+	LamportsBefore uint64
+	LamportsAfter  uint64
+
+	// Token fields are set when the account is a Token or Token-2022 account after the simulation
+	IsTokenAccount bool
+	Mint           solana.PublicKey
+	Owner          solana.PublicKey
+	TokenBefore    uint64
+	TokenAfter     uint64
+}
+
+// LamportsDelta returns the change in the account's lamports
+func (c AccountChange) LamportsDelta() int64 {
+	return int64(c.LamportsAfter) - int64(c.LamportsBefore)
+}
+
+// TokenDelta returns the change in the account's token balance
+func (c AccountChange) TokenDelta() int64 {
+	return int64(c.TokenAfter) - int64(c.TokenBefore)
+}
+
+// SimulationPreview explains what a transaction will do before it is sent
+type SimulationPreview struct {
+	// Transaction holds the transaction's parsed Raydium operations, with trade amounts out
+	// taken from the simulated events where the program reports them
+	Transaction *Transaction
+	Logs        []string
+	Invocations []ProgramInvocation
+	Events      []LogEvent
+	Accounts    []AccountChange
+	Fee         FeeEstimate
+
+	UnitsConsumed uint64
+	// Err is the simulation's transaction error and Error its explanation; both are empty on success
+	Err   interface{}
+	Error string
+	// EventErr reports the events in Logs that could not be decoded; Events holds the rest
+	EventErr error
+}
+
+// Succeeded reports whether the simulated transaction executed without error
+func (p *SimulationPreview) Succeeded() bool {
+	return p.Err == nil
+}
+
+// PreviewTransaction simulates tx with the latest blockhash and explains the result: the
+// decoded Raydium events, the fee, and the before and after state of each watched account.
+// Without watched accounts, every writable account of the message is reported. tx does not
+// need to be signed. A failed simulation is reported in the preview, not as an error.
+func PreviewTransaction(ctx context.Context, client *rpc.Client, tx *solana.Transaction, watch ...solana.PublicKey) (*SimulationPreview, error) {
+	if len(watch) == 0 {
+		watch = writableAccounts(tx)
+	}
+
+	before, err := client.GetMultipleAccountsWithOpts(ctx, watch, &rpc.GetMultipleAccountsOpts{
+		Encoding:   solana.EncodingBase64,
+		Commitment: rpc.CommitmentProcessed,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load accounts before simulation: %w", err)
+	}
+
+	// The simulation skips signature checks but still needs a slot for every signature
+	simulated := *tx
+	if len(simulated.Signatures) != int(tx.Message.Header.NumRequiredSignatures) {
+		simulated.Signatures = make([]solana.Signature, tx.Message.Header.NumRequiredSignatures)
+	}
+	response, err := client.SimulateTransactionWithOpts(ctx, &simulated, &rpc.SimulateTransactionOpts{
+		Commitment:             rpc.CommitmentProcessed,
+		ReplaceRecentBlockhash: true,
+		Accounts: &rpc.SimulateTransactionAccountsOpts{
+			Encoding:  solana.EncodingBase64,
+			Addresses: watch,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to simulate transaction: %w", err)
+	}
+	if response == nil || response.Value == nil {
+		return nil, fmt.Errorf("simulation returned no result")
+	}
+	result := response.Value
+
+	preview := &SimulationPreview{
+		Logs:        result.Logs,
+		Invocations: ParseProgramLogs(result.Logs),
+		Fee:         EstimateFee(tx),
+		Err:         result.Err,
+	}
+	if result.UnitsConsumed != nil {
+		preview.UnitsConsumed = *result.UnitsConsumed
+	}
+	if preview.Err != nil {
+		preview.Error = explainSimulationError(preview.Err, preview.Invocations)
+	}
+
+	events, err := DecodeLogEvents(result.Logs)
+	preview.Events = events
+	preview.EventErr = err
+
+	for i, address := range watch {
+		var pre, post *rpc.Account
+		if i < len(before.Value) {
+			pre = before.Value[i]
+		}
+		if i < len(result.Accounts) {
+			post = result.Accounts[i]
+		}
+		preview.Accounts = append(preview.Accounts, accountChange(address, pre, post))
+	}
+
+	parsed, err := parseUnsignedTransaction(&simulated)
+	if err != nil {
+		return nil, err
+	}
+	// Events are emitted in execution order, so they line up with the parsed trades unless
+	// one of them could not be decoded
+	if preview.EventErr == nil && len(events) == len(parsed.Trade) {
+		for i := range parsed.Trade {
+			parsed.Trade[i].AmountOut = events[i].AmountOut
+			// ray_log does not name the pool
+			if events[i].Pool.IsZero() {
+				events[i].Pool = parsed.Trade[i].Pool
+			}
+		}
+	}
+	preview.Transaction = parsed
+	return preview, nil
+}
+
+// Explain renders the preview as human-readable lines
+func (p *SimulationPreview) Explain() string {
+	var b strings.Builder
+	if p.Succeeded() {
+		fmt.Fprintf(&b, "Simulation succeeded using %d compute units\n", p.UnitsConsumed)
+	} else {
+		fmt.Fprintf(&b, "Simulation failed: %s\n", p.Error)
+	}
+	fmt.Fprintf(&b, "Fee: %d lamports (%d signature, %d priority at %d micro-lamports x %d units)\n",
+		p.Fee.Total(), p.Fee.SignatureFee, p.Fee.PriorityFee, p.Fee.ComputeUnitPrice, p.Fee.ComputeUnitLimit)
+	for _, event := range p.Events {
+		fmt.Fprintf(&b, "%s on %s: %d in, %d out", event.Name, event.Pool, event.AmountIn, event.AmountOut)
+		if event.Fee > 0 {
+			fmt.Fprintf(&b, ", %d fee", event.Fee)
+		}
+		b.WriteString("\n")
+	}
+	if p.EventErr != nil {
+		fmt.Fprintf(&b, "Some events could not be decoded: %v\n", p.EventErr)
+	}
+	for _, change := range p.Accounts {
+		if change.IsTokenAccount && change.TokenDelta() != 0 {
+			fmt.Fprintf(&b, "%s: %+d %s\n", change.Address, change.TokenDelta(), GetTokenInfo(change.Mint).Symbol)
+		}
+		if change.LamportsDelta() != 0 {
+			fmt.Fprintf(&b, "%s: %+d lamports\n", change.Address, change.LamportsDelta())
+		}
+	}
+	return b.String()
+}
+
+// explainSimulationError names the innermost program that failed and its message
+func explainSimulationError(txErr interface{}, invocations []ProgramInvocation) string {
+	var failed *ProgramInvocation
+	for i := range invocations {
+		if invocations[i].Failed && (failed == nil || invocations[i].Depth > failed.Depth) {
+			failed = &invocations[i]
+		}
+	}
+	if failed == nil {
+		return fmt.Sprintf("%v", txErr)
+	}
+
+	explanation := fmt.Sprintf("%s (%s) failed: %s", getProgramName(failed.ProgramID), failed.ProgramID, failed.Error)
+	if code, ok := failed.CustomErrorCode(); ok {
+		explanation += fmt.Sprintf(" (error code %d)", code)
+	}
+	// Anchor programs log the error name and message before failing
+	for _, line := range failed.Logs {
+		if strings.HasPrefix(line, "AnchorError") {
+			explanation += ": " + line
+			break
+		}
+	}
+	return explanation
+}
+
+// writableAccounts returns the message's writable static accounts
+func writableAccounts(tx *solana.Transaction) []solana.PublicKey {
+	var accounts []solana.PublicKey
+	for _, key := range tx.Message.AccountKeys {
+		if writable, err := tx.Message.IsWritable(key); err == nil && writable {
+			accounts = append(accounts, key)
+		}
+	}
+	return accounts
+}
+
+// accountChange compares an account before and after simulation; a nil account does not exist
+func accountChange(address solana.PublicKey, before, after *rpc.Account) AccountChange {
+	change := AccountChange{Address: address}
+	if before != nil {
+		change.LamportsBefore = before.Lamports
+	}
+	if after == nil {
+		return change
+	}
+	change.LamportsAfter = after.Lamports
+
+	if !after.Owner.Equals(TokenProgramID) && !after.Owner.Equals(Token2022ProgramID) {
+		return change
+	}
+	data := accountData(after)
+	if len(data) < tokenAccountBaseSize {
+		return change
+	}
+	change.IsTokenAccount = true
+	change.Mint = solana.PublicKeyFromBytes(data[tokenAccountMintOffset:tokenAccountOwnerOffset])
+	change.Owner = solana.PublicKeyFromBytes(data[tokenAccountOwnerOffset:tokenAccountAmountOffset])
+	change.TokenAfter = binary.LittleEndian.Uint64(data[tokenAccountAmountOffset:])
+	if beforeData := accountData(before); len(beforeData) >= tokenAccountBaseSize {
+		change.TokenBefore = binary.LittleEndian.Uint64(beforeData[tokenAccountAmountOffset:])
+	}
+	return change
+}
+
+func accountData(account *rpc.Account) []byte {
+	if account == nil || account.Data == nil {
+		return nil
+	}
+	return account.Data.GetBinary()
+}
+
+// parseUnsignedTransaction runs the instruction parser over a transaction that may carry
+// placeholder signatures
+func parseUnsignedTransaction(tx *solana.Transaction) (*Transaction, error) {
+	txBytes, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize transaction: %w", err)
+	}
+	return ParseTransactionWithMeta(base64.StdEncoding.EncodeToString(txBytes), 0, nil)
+}
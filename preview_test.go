@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// tokenAccountJSON encodes a Token account the way getMultipleAccounts and simulateTransaction return it
+func tokenAccountJSON(mint, owner solana.PublicKey, amount, lamports uint64) map[string]interface{} {
+	data := make([]byte, tokenAccountBaseSize)
+	copy(data[tokenAccountMintOffset:], mint.Bytes())
+	copy(data[tokenAccountOwnerOffset:], owner.Bytes())
+	binary.LittleEndian.PutUint64(data[tokenAccountAmountOffset:], amount)
+	return map[string]interface{}{
+		"lamports":   lamports,
+		"owner":      TokenProgramID.String(),
+		"data":       []string{base64.StdEncoding.EncodeToString(data), "base64"},
+		"executable": false,
+		"rentEpoch":  0,
+	}
+}
+
+func systemAccountJSON(lamports uint64) map[string]interface{} {
+	return map[string]interface{}{
+		"lamports":   lamports,
+		"owner":      SystemProgramID.String(),
+		"data":       []string{"", "base64"},
+		"executable": false,
+		"rentEpoch":  0,
+	}
+}
+
+func TestEstimateFee(t *testing.T) {
+	payer := solana.NewWallet().PublicKey()
+	transfer := NewTransferInstruction(payer, solana.NewWallet().PublicKey(), 1)
+	blockhash := solana.Hash(solana.NewWallet().PublicKey())
+
+	tx, _ := NewTransactionComposer(payer).SetRecentBlockhash(blockhash).
+		SetComputeUnitLimit(150_000).SetComputeUnitPrice(10_001).
+		AddInstruction(transfer).Build()
+	fee := EstimateFee(tx)
+	// ceil(10001 * 150000 / 1e6) = 1501
+	if fee.SignatureFee != 5000 || fee.PriorityFee != 1501 || fee.Total() != 6501 || fee.ComputeUnitLimit != 150_000 {
+		t.Errorf("Unexpected fee %+v", fee)
+	}
+
+	// Without a limit every other instruction gets the default 200k units
+	tx, _ = NewTransactionComposer(payer).SetRecentBlockhash(blockhash).
+		SetComputeUnitPrice(1_000_000).
+		AddInstruction(transfer).AddInstruction(transfer).Build()
+	if fee := EstimateFee(tx); fee.ComputeUnitLimit != 400_000 || fee.PriorityFee != 400_000 {
+		t.Errorf("Unexpected default limit fee %+v", fee)
+	}
+
+	tx, _ = NewTransactionComposer(payer).SetRecentBlockhash(blockhash).AddInstruction(transfer).Build()
+	if fee := EstimateFee(tx); fee.PriorityFee != 0 || fee.Total() != LAMPORTS_PER_SIGNATURE {
+		t.Errorf("Expected only the signature fee, got %+v", fee)
+	}
+}
+
+func TestPreviewTransaction(t *testing.T) {
+	payer := solana.NewWallet().PublicKey()
+	baseMint := solana.NewWallet().PublicKey()
+	pool, _ := DeriveLaunchpadPoolState(RaydiumLaunchpadV1ProgramID, baseMint, solana.SolMint)
+	userBase, _ := DeriveAssociatedTokenAddress(payer, baseMint, TokenProgramID)
+	userQuote, _ := DeriveAssociatedTokenAddress(payer, solana.SolMint, TokenProgramID)
+
+	tx, err := NewTransactionComposer(payer).
+		SetRecentBlockhash(solana.Hash(solana.NewWallet().PublicKey())).
+		SetComputeUnitLimit(120_000).
+		SetComputeUnitPrice(50_000).
+		AddBuilder(NewBuyInstruction().
+			SetPayer(payer).SetPlatformConfig(LetsBonkPlatformConfigID).SetBaseMint(baseMint).
+			SetAmountIn(1_000_000).SetMinimumAmountOut(30_000)).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to compose transaction: %v", err)
+	}
+
+	watch := []solana.PublicKey{payer, userBase, userQuote}
+	trade := launchpadTradeEventData(pool, 1_000_000, 35_000)
+	var simulated []interface{}
+	_, client := newFakeRPC(t, map[string]func(json.RawMessage) (interface{}, error){
+		"getMultipleAccounts": func(json.RawMessage) (interface{}, error) {
+			// The base token account does not exist yet
+			return rpcContext([]interface{}{
+				systemAccountJSON(10_000_000),
+				nil,
+				tokenAccountJSON(solana.SolMint, payer, 2_000_000, 2_039_280),
+			}), nil
+		},
+		"simulateTransaction": func(params json.RawMessage) (interface{}, error) {
+			json.Unmarshal(params, &simulated)
+			return rpcContext(map[string]interface{}{
+				"err": nil,
+				"logs": []string{
+					"Program " + RaydiumLaunchpadV1ProgramID.String() + " invoke [1]",
+					"Program log: Instruction: BuyExactIn",
+					"Program data: " + base64.StdEncoding.EncodeToString(trade),
+					"Program " + RaydiumLaunchpadV1ProgramID.String() + " consumed 61234 of 119700 compute units",
+					"Program " + RaydiumLaunchpadV1ProgramID.String() + " success",
+				},
+				"accounts": []interface{}{
+					systemAccountJSON(10_000_000 - 11_000),
+					tokenAccountJSON(baseMint, payer, 35_000, 2_039_280),
+					tokenAccountJSON(solana.SolMint, payer, 1_000_000, 2_039_280),
+				},
+				"unitsConsumed": 61534,
+			}), nil
+		},
+	})
+
+	preview, err := PreviewTransaction(context.Background(), client, tx, watch...)
+	if err != nil {
+		t.Fatalf("Preview failed: %v", err)
+	}
+
+	// The unsigned transaction is simulated with a placeholder signature and a fresh blockhash
+	options, _ := simulated[1].(map[string]interface{})
+	if options["replaceRecentBlockhash"] != true {
+		t.Errorf("Expected replaceRecentBlockhash, got %v", options)
+	}
+	encoded, _ := simulated[0].(string)
+	if sent, err := solana.TransactionFromBase64(encoded); err != nil || len(sent.Signatures) != 1 {
+		t.Errorf("Expected one placeholder signature in the simulated transaction: %v", err)
+	}
+
+	if !preview.Succeeded() || preview.UnitsConsumed != 61534 {
+		t.Errorf("Unexpected result: err %v, %d units", preview.Err, preview.UnitsConsumed)
+	}
+	if preview.Fee.PriorityFee != 6000 || preview.Fee.Total() != 11000 {
+		t.Errorf("Unexpected fee %+v", preview.Fee)
+	}
+	if len(preview.Events) != 1 || preview.Events[0].AmountOut != 35_000 || preview.Events[0].Fee != 1_250 {
+		t.Fatalf("Unexpected events %+v", preview.Events)
+	}
+	if len(preview.Transaction.Trade) != 1 || preview.Transaction.Trade[0].AmountOut != 35_000 {
+		t.Errorf("Expected the parsed buy to carry the simulated amount out, got %+v", preview.Transaction.Trade)
+	}
+
+	base, quote := preview.Accounts[1], preview.Accounts[2]
+	if !base.IsTokenAccount || base.TokenDelta() != 35_000 || !base.Mint.Equals(baseMint) {
+		t.Errorf("Unexpected base account change %+v", base)
+	}
+	if quote.TokenDelta() != -1_000_000 || preview.Accounts[0].LamportsDelta() != -11_000 {
+		t.Errorf("Unexpected quote or payer change: %d tokens, %d lamports", quote.TokenDelta(), preview.Accounts[0].LamportsDelta())
+	}
+	if explanation := preview.Explain(); !strings.Contains(explanation, "+35000") || !strings.Contains(explanation, "Fee: 11000 lamports") {
+		t.Errorf("Unexpected explanation:\n%s", explanation)
+	}
+}
+
+func TestPreviewTransactionFailure(t *testing.T) {
+	payer := solana.NewWallet().PublicKey()
+	tx, _ := NewTransactionComposer(payer).
+		SetRecentBlockhash(solana.Hash(solana.NewWallet().PublicKey())).
+		AddBuilder(NewCpSwapInstruction().
+			SetPayer(payer).SetAmmConfig(solana.NewWallet().PublicKey()).
+			SetInputMint(solana.SolMint).SetOutputMint(solana.NewWallet().PublicKey()).
+			SetAmountIn(1_000).SetMinimumAmountOut(1_000_000)).
+		Build()
+
+	_, client := newFakeRPC(t, map[string]func(json.RawMessage) (interface{}, error){
+		"getMultipleAccounts": func(params json.RawMessage) (interface{}, error) {
+			var args []json.RawMessage
+			json.Unmarshal(params, &args)
+			var addresses []string
+			json.Unmarshal(args[0], &addresses)
+			accounts := make([]interface{}, len(addresses))
+			return rpcContext(accounts), nil
+		},
+		"simulateTransaction": func(json.RawMessage) (interface{}, error) {
+			return rpcContext(map[string]interface{}{
+				"err": map[string]interface{}{"InstructionError": []interface{}{0, map[string]interface{}{"Custom": 6005}}},
+				"logs": []string{
+					// A truncated event from an earlier instruction must not hide the rest of the preview
+					"Program " + RaydiumLaunchpadV1ProgramID.String() + " invoke [1]",
+					"Program data: " + base64.StdEncoding.EncodeToString(launchpadTradeEventData(payer, 1_000, 900)[:40]),
+					"Program " + RaydiumLaunchpadV1ProgramID.String() + " success",
+					"Program " + RaydiumCpSwapProgramID.String() + " invoke [1]",
+					"Program log: Instruction: SwapBaseInput",
+					"Program log: AnchorError occurred. Error Code: ExceededSlippage. Error Number: 6005. Error Message: Exceeds desired slippage limit.",
+					"Program " + RaydiumCpSwapProgramID.String() + " consumed 20000 of 200000 compute units",
+					"Program " + RaydiumCpSwapProgramID.String() + " failed: custom program error: 0x1775",
+				},
+				"accounts":      []interface{}{},
+				"unitsConsumed": 20000,
+			}), nil
+		},
+	})
+
+	preview, err := PreviewTransaction(context.Background(), client, tx)
+	if err != nil {
+		t.Fatalf("A failed simulation should still produce a preview: %v", err)
+	}
+	if preview.Succeeded() {
+		t.Fatalf("Expected the simulation to fail")
+	}
+	for _, want := range []string{RaydiumCpSwapProgramID.String(), "error code 6005", "ExceededSlippage"} {
+		if !strings.Contains(preview.Error, want) {
+			t.Errorf("Expected explanation to mention %q, got %q", want, preview.Error)
+		}
+	}
+	if len(preview.Accounts) == 0 {
+		t.Errorf("Expected the writable accounts to be watched by default")
+	}
+	if preview.EventErr == nil || !strings.Contains(preview.EventErr.Error(), "TradeEvent") || len(preview.Logs) != 8 {
+		t.Errorf("Expected the decode error alongside the logs, got %v", preview.EventErr)
+	}
+	if !strings.Contains(preview.Explain(), "could not be decoded") {
+		t.Errorf("Expected the explanation to mention the decode error:\n%s", preview.Explain())
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// maxSupportedTransactionVersion lets getTransaction and getBlock return v0 transactions
+var maxSupportedTransactionVersion uint64 = 0
+
+// TransactionMetaFromRPC converts getTransaction status metadata into the parser's TransactionMeta
+func TransactionMetaFromRPC(meta *rpc.TransactionMeta) (*TransactionMeta, error) {
+	if meta == nil {
+		return nil, nil
+	}
+
+	preTokenBalances, err := tokenBalancesFromRPC(meta.PreTokenBalances)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pre token balances: %w", err)
+	}
+	postTokenBalances, err := tokenBalancesFromRPC(meta.PostTokenBalances)
+	if err != nil {
+		return nil, fmt.Errorf("invalid post token balances: %w", err)
+	}
+
+	innerInstructions := make([]CompiledInnerInstructions, 0, len(meta.InnerInstructions))
+	for _, inner := range meta.InnerInstructions {
+		innerInstructions = append(innerInstructions, CompiledInnerInstructions{
+			Index:        int(inner.Index),
+			Instructions: inner.Instructions,
+		})
+	}
+
+	return &TransactionMeta{
+		PreBalances:             meta.PreBalances,
+		PostBalances:            meta.PostBalances,
+		PreTokenBalances:        preTokenBalances,
+		PostTokenBalances:       postTokenBalances,
+		LoadedWritableAddresses: meta.LoadedAddresses.Writable,
+		LoadedReadonlyAddresses: meta.LoadedAddresses.ReadOnly,
+		InnerInstructions:       innerInstructions,
+	}, nil
+}
+
+func tokenBalancesFromRPC(balances []rpc.TokenBalance) ([]TokenBalance, error) {
+	result := make([]TokenBalance, 0, len(balances))
+	for _, balance := range balances {
+		converted := TokenBalance{
+			AccountIndex: int(balance.AccountIndex),
+			Mint:         balance.Mint,
+		}
+		if balance.UiTokenAmount != nil {
+			amount, err := strconv.ParseUint(balance.UiTokenAmount.Amount, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("account %d: %w", balance.AccountIndex, err)
+			}
+			converted.Amount = amount
+			converted.Decimals = balance.UiTokenAmount.Decimals
+		}
+		result = append(result, converted)
+	}
+	return result, nil
+}
+
+// ParseTransactionResult parses a base64 encoded getTransaction result together with its metadata
+func ParseTransactionResult(result *rpc.GetTransactionResult, opts ...ParseOption) (*Transaction, error) {
+	if result == nil || result.Transaction == nil {
+		return nil, fmt.Errorf("transaction result is empty")
+	}
+	return parseRPCTransaction(result.Transaction.GetBinary(), result.Slot, result.Meta, opts...)
+}
+
+// parseRPCTransaction parses a binary transaction with the status metadata an RPC node returned for it
+func parseRPCTransaction(txBytes []byte, slot uint64, rpcMeta *rpc.TransactionMeta, opts ...ParseOption) (*Transaction, error) {
+	if len(txBytes) == 0 {
+		return nil, fmt.Errorf("transaction result is not binary encoded")
+	}
+	meta, err := TransactionMetaFromRPC(rpcMeta)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTransactionWithMeta(base64.StdEncoding.EncodeToString(txBytes), slot, meta, opts...)
+}
+
+// getTransactionOpts requests a transaction in the binary encoding ParseTransactionResult expects
+func getTransactionOpts(commitment rpc.CommitmentType) *rpc.GetTransactionOpts {
+	return &rpc.GetTransactionOpts{
+		Encoding:                       solana.EncodingBase64,
+		Commitment:                     commitment,
+		MaxSupportedTransactionVersion: &maxSupportedTransactionVersion,
+	}
+}
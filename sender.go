@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// Default sender timings
+const (
+	DEFAULT_REBROADCAST_INTERVAL = 2 * time.Second
+	DEFAULT_STATUS_POLL_INTERVAL = 500 * time.Millisecond
+)
+
+// ErrBlockhashExpired is returned when a transaction's blockhash expires before it lands
+var ErrBlockhashExpired = errors.New("blockhash expired before the transaction landed")
+
+// TransactionFailedError reports a transaction that landed on chain but failed to execute
+type TransactionFailedError struct {
+	Signature solana.Signature
+	Slot      uint64
+	Err       interface{}
+}
+
+func (e *TransactionFailedError) Error() string {
+	return fmt.Sprintf("transaction %s failed in slot %d: %v", e.Signature, e.Slot, e.Err)
+}
+
+// confirmationRank orders confirmation statuses so a higher commitment also satisfies lower ones
+var confirmationRank = map[rpc.ConfirmationStatusType]int{
+	rpc.ConfirmationStatusProcessed: 1,
+	rpc.ConfirmationStatusConfirmed: 2,
+	rpc.ConfirmationStatusFinalized: 3,
+}
+
+// TransactionSender submits signed transactions, rebroadcasting them until they reach the
+// chosen commitment or their blockhash expires
+type TransactionSender struct {
+	client              *rpc.Client
+	commitment          rpc.CommitmentType
+	rebroadcastInterval time.Duration
+	pollInterval        time.Duration
+	skipPreflight       bool
+	parseOptions        []ParseOption
+}
+
+// NewTransactionSender creates a sender that waits for confirmed commitment
+func NewTransactionSender(client *rpc.Client) *TransactionSender {
+	return &TransactionSender{
+		client:              client,
+		commitment:          rpc.CommitmentConfirmed,
+		rebroadcastInterval: DEFAULT_REBROADCAST_INTERVAL,
+		pollInterval:        DEFAULT_STATUS_POLL_INTERVAL,
+	}
+}
+
+// SetCommitment sets the commitment Send waits for: processed, confirmed or finalized
+func (s *TransactionSender) SetCommitment(commitment rpc.CommitmentType) *TransactionSender {
+	s.commitment = commitment
+	return s
+}
+
+// SetRebroadcastInterval sets how often an unconfirmed transaction is sent again
+func (s *TransactionSender) SetRebroadcastInterval(interval time.Duration) *TransactionSender {
+	s.rebroadcastInterval = interval
+	return s
+}
+
+// SetPollInterval sets how often getSignatureStatuses is polled
+func (s *TransactionSender) SetPollInterval(interval time.Duration) *TransactionSender {
+	s.pollInterval = interval
+	return s
+}
+
+// SetSkipPreflight skips the RPC node's simulation before the first broadcast
+func (s *TransactionSender) SetSkipPreflight(skip bool) *TransactionSender {
+	s.skipPreflight = skip
+	return s
+}
+
+// SetParseOptions sets the options used to parse the landed transaction
+func (s *TransactionSender) SetParseOptions(opts ...ParseOption) *TransactionSender {
+	s.parseOptions = opts
+	return s
+}
+
+// Send submits a signed transaction and returns it parsed once it reaches the sender's
+// commitment. It returns ErrBlockhashExpired if the blockhash expires first, a
+// *TransactionFailedError if the transaction lands but fails, and the context's error if
+// ctx is done.
+func (s *TransactionSender) Send(ctx context.Context, tx *solana.Transaction) (*Transaction, error) {
+	if len(tx.Signatures) == 0 || tx.Signatures[0].IsZero() {
+		return nil, fmt.Errorf("transaction is not signed")
+	}
+	signature := tx.Signatures[0]
+	rawTx, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize transaction: %w", err)
+	}
+
+	// The node's own retries are disabled; rebroadcasting is done here
+	maxRetries := uint(0)
+	sendOpts := rpc.TransactionOpts{
+		SkipPreflight:       s.skipPreflight,
+		PreflightCommitment: s.commitment,
+		MaxRetries:          &maxRetries,
+	}
+	if _, err := s.client.SendRawTransactionWithOpts(ctx, rawTx, sendOpts); err != nil {
+		return nil, fmt.Errorf("failed to send transaction %s: %w", signature, err)
+	}
+	// Later broadcasts of the same transaction must not fail preflight as already processed
+	sendOpts.SkipPreflight = true
+
+	poll := time.NewTicker(s.pollInterval)
+	defer poll.Stop()
+	lastBroadcast := time.Now()
+	landed := false
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("stopped waiting for transaction %s: %w", signature, ctx.Err())
+		case <-poll.C:
+		}
+
+		if landed {
+			// The status is known; wait for getTransaction to serve the transaction
+			if result, err := s.fetch(ctx, signature); err == nil {
+				return result, nil
+			} else if !errors.Is(err, rpc.ErrNotFound) {
+				return nil, err
+			}
+			continue
+		}
+
+		status, err := s.status(ctx, signature)
+		if err != nil {
+			log.Printf("Failed to get status of %s: %v", signature, err)
+		}
+		if status != nil {
+			if status.Err != nil {
+				return nil, &TransactionFailedError{Signature: signature, Slot: status.Slot, Err: status.Err}
+			}
+			if confirmationRank[status.ConfirmationStatus] >= confirmationRank[rpc.ConfirmationStatusType(s.commitment)] {
+				landed = true
+				if result, err := s.fetch(ctx, signature); err == nil {
+					return result, nil
+				} else if !errors.Is(err, rpc.ErrNotFound) {
+					return nil, err
+				}
+			}
+			// Seen by the cluster; no need to rebroadcast while it gains confirmations
+			continue
+		}
+
+		if time.Since(lastBroadcast) < s.rebroadcastInterval {
+			continue
+		}
+		valid, err := s.client.IsBlockhashValid(ctx, tx.Message.RecentBlockhash, rpc.CommitmentProcessed)
+		if err != nil {
+			log.Printf("Failed to check blockhash of %s: %v", signature, err)
+		} else if !valid.Value {
+			return nil, fmt.Errorf("%w: %s", ErrBlockhashExpired, signature)
+		}
+		if _, err := s.client.SendRawTransactionWithOpts(ctx, rawTx, sendOpts); err != nil {
+			log.Printf("Failed to rebroadcast %s: %v", signature, err)
+		}
+		lastBroadcast = time.Now()
+	}
+}
+
+// status returns the signature's status, or nil if the cluster has not seen it
+func (s *TransactionSender) status(ctx context.Context, signature solana.Signature) (*rpc.SignatureStatusesResult, error) {
+	statuses, err := s.client.GetSignatureStatuses(ctx, false, signature)
+	if err != nil {
+		if errors.Is(err, rpc.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(statuses.Value) == 0 {
+		return nil, nil
+	}
+	return statuses.Value[0], nil
+}
+
+// fetch retrieves and parses a landed transaction. getTransaction does not serve processed
+// commitment, so processed senders read it at confirmed.
+func (s *TransactionSender) fetch(ctx context.Context, signature solana.Signature) (*Transaction, error) {
+	commitment := s.commitment
+	if commitment == rpc.CommitmentProcessed {
+		commitment = rpc.CommitmentConfirmed
+	}
+	result, err := s.client.GetTransaction(ctx, signature, getTransactionOpts(commitment))
+	if err != nil {
+		return nil, err
+	}
+	transaction, err := ParseTransactionResult(result, s.parseOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse landed transaction %s: %w", signature, err)
+	}
+	return transaction, nil
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// fakeRPC is a local JSON-RPC stand-in; handlers return a result or an error message per method
+type fakeRPC struct {
+	mu       sync.Mutex
+	handlers map[string]func(params json.RawMessage) (interface{}, error)
+	calls    map[string]int
+}
+
+func newFakeRPC(t *testing.T, handlers map[string]func(params json.RawMessage) (interface{}, error)) (*fakeRPC, *rpc.Client) {
+	t.Helper()
+	fake, url := newFakeEndpoint(t, handlers)
+	return fake, rpc.New(url)
+}
+
+// newFakeEndpoint serves a fakeRPC and returns its URL
+func newFakeEndpoint(t *testing.T, handlers map[string]func(params json.RawMessage) (interface{}, error)) (*fakeRPC, string) {
+	t.Helper()
+	fake := &fakeRPC{handlers: handlers, calls: map[string]int{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, server.URL
+}
+
+func (f *fakeRPC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	f.calls[request.Method]++
+	handler := f.handlers[request.Method]
+	f.mu.Unlock()
+
+	response := map[string]interface{}{"jsonrpc": "2.0", "id": request.ID}
+	if handler == nil {
+		response["error"] = map[string]interface{}{"code": -32601, "message": "method not found: " + request.Method}
+	} else if result, err := handler(request.Params); err != nil {
+		code := -32002
+		var rpcErr *jsonrpc.RPCError
+		if errors.As(err, &rpcErr) {
+			code = rpcErr.Code
+		}
+		response["error"] = map[string]interface{}{"code": code, "message": err.Error()}
+	} else {
+		response["result"] = result
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (f *fakeRPC) count(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[method]
+}
+
+// rpcContext wraps a value the way RPC methods with a context return it
+func rpcContext(value interface{}) map[string]interface{} {
+	return map[string]interface{}{"context": map[string]interface{}{"slot": 100}, "value": value}
+}
+
+// getTransactionResult builds a base64 getTransaction response for a serialized transaction
+func getTransactionResult(txBytes []byte, slot uint64, txErr interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"slot":        slot,
+		"blockTime":   1_700_000_000,
+		"transaction": []string{base64.StdEncoding.EncodeToString(txBytes), "base64"},
+		"meta": map[string]interface{}{
+			"err":               txErr,
+			"fee":               5000,
+			"preBalances":       []uint64{},
+			"postBalances":      []uint64{},
+			"innerInstructions": []interface{}{},
+			"preTokenBalances":  []interface{}{},
+			"postTokenBalances": []interface{}{},
+			"logMessages":       []string{},
+			"loadedAddresses":   map[string]interface{}{"readonly": []string{}, "writable": []string{}},
+		},
+		"version": "legacy",
+	}
+}
+
+// signedBuyTransaction composes and signs a Launchpad buy for the sender tests
+func signedBuyTransaction(t *testing.T) (*solana.Transaction, []byte) {
+	t.Helper()
+	payer := solana.NewWallet().PrivateKey
+	tx, err := NewTransactionComposer(payer.PublicKey()).
+		SetRecentBlockhash(solana.Hash(solana.NewWallet().PublicKey())).
+		SetComputeUnitPrice(1_000).
+		AddBuilder(NewBuyInstruction().
+			SetPayer(payer.PublicKey()).SetPlatformConfig(LetsBonkPlatformConfigID).
+			SetBaseMint(solana.NewWallet().PublicKey()).
+			SetAmountIn(1_000_000).SetMinimumAmountOut(900)).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to compose transaction: %v", err)
+	}
+	signer, _ := NewKeypairSigner(payer)
+	if err := SignTransaction(tx, signer); err != nil {
+		t.Fatalf("Failed to sign transaction: %v", err)
+	}
+	txBytes, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to serialize transaction: %v", err)
+	}
+	return tx, txBytes
+}
+
+func fastSender(client *rpc.Client) *TransactionSender {
+	return NewTransactionSender(client).
+		SetPollInterval(5 * time.Millisecond).
+		SetRebroadcastInterval(20 * time.Millisecond)
+}
+
+func TestTransactionSenderLands(t *testing.T) {
+	tx, txBytes := signedBuyTransaction(t)
+	signature := tx.Signatures[0]
+
+	var statusPolls int
+	var mu sync.Mutex
+	fake, client := newFakeRPC(t, map[string]func(json.RawMessage) (interface{}, error){
+		"sendTransaction": func(params json.RawMessage) (interface{}, error) {
+			var args []interface{}
+			json.Unmarshal(params, &args)
+			if args[0] != base64.StdEncoding.EncodeToString(txBytes) {
+				t.Errorf("Unexpected transaction sent")
+			}
+			return signature.String(), nil
+		},
+		"isBlockhashValid": func(json.RawMessage) (interface{}, error) { return rpcContext(true), nil },
+		"getSignatureStatuses": func(json.RawMessage) (interface{}, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			statusPolls++
+			switch {
+			case statusPolls < 10:
+				// Not seen yet, forcing rebroadcasts
+				return rpcContext([]interface{}{nil}), nil
+			case statusPolls < 12:
+				return rpcContext([]interface{}{map[string]interface{}{"slot": 321, "confirmations": 0, "err": nil, "confirmationStatus": "processed"}}), nil
+			default:
+				return rpcContext([]interface{}{map[string]interface{}{"slot": 321, "confirmations": 1, "err": nil, "confirmationStatus": "confirmed"}}), nil
+			}
+		},
+		"getTransaction": func(json.RawMessage) (interface{}, error) { return getTransactionResult(txBytes, 321, nil), nil },
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err := fastSender(client).SetParseOptions(WithSignatureVerification()).Send(ctx, tx)
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if result.Signature != signature || result.Slot != 321 {
+		t.Errorf("Expected %s in slot 321, got %s in slot %d", signature, result.Signature, result.Slot)
+	}
+	if len(result.Trade) != 1 {
+		t.Errorf("Expected the landed buy to be parsed, got %d trades", len(result.Trade))
+	}
+	if fake.count("sendTransaction") < 2 {
+		t.Errorf("Expected the transaction to be rebroadcast, sent %d times", fake.count("sendTransaction"))
+	}
+}
+
+func TestTransactionSenderBlockhashExpiry(t *testing.T) {
+	tx, _ := signedBuyTransaction(t)
+	var checks int
+	var mu sync.Mutex
+	fake, client := newFakeRPC(t, map[string]func(json.RawMessage) (interface{}, error){
+		"sendTransaction": func(json.RawMessage) (interface{}, error) { return tx.Signatures[0].String(), nil },
+		"isBlockhashValid": func(json.RawMessage) (interface{}, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			checks++
+			return rpcContext(checks < 3), nil
+		},
+		"getSignatureStatuses": func(json.RawMessage) (interface{}, error) { return rpcContext([]interface{}{nil}), nil },
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := fastSender(client).Send(ctx, tx)
+	if !errors.Is(err, ErrBlockhashExpired) {
+		t.Fatalf("Expected ErrBlockhashExpired, got %v", err)
+	}
+	if sends := fake.count("sendTransaction"); sends != 3 {
+		t.Errorf("Expected the initial send and two rebroadcasts, got %d", sends)
+	}
+}
+
+func TestTransactionSenderFailures(t *testing.T) {
+	tx, _ := signedBuyTransaction(t)
+
+	t.Run("landed with error", func(t *testing.T) {
+		_, client := newFakeRPC(t, map[string]func(json.RawMessage) (interface{}, error){
+			"sendTransaction": func(json.RawMessage) (interface{}, error) { return tx.Signatures[0].String(), nil },
+			"getSignatureStatuses": func(json.RawMessage) (interface{}, error) {
+				return rpcContext([]interface{}{map[string]interface{}{
+					"slot": 50, "confirmations": 0, "confirmationStatus": "processed",
+					"err": map[string]interface{}{"InstructionError": []interface{}{1, map[string]interface{}{"Custom": 6005}}},
+				}}), nil
+			},
+		})
+		_, err := fastSender(client).Send(context.Background(), tx)
+		var failed *TransactionFailedError
+		if !errors.As(err, &failed) || failed.Slot != 50 || failed.Signature != tx.Signatures[0] {
+			t.Errorf("Expected TransactionFailedError in slot 50, got %v", err)
+		}
+	})
+
+	t.Run("preflight rejected", func(t *testing.T) {
+		_, client := newFakeRPC(t, map[string]func(json.RawMessage) (interface{}, error){
+			"sendTransaction": func(json.RawMessage) (interface{}, error) {
+				return nil, errors.New("Transaction simulation failed: insufficient funds")
+			},
+		})
+		if _, err := fastSender(client).Send(context.Background(), tx); err == nil {
+			t.Errorf("Expected the preflight error to be returned")
+		}
+	})
+
+	t.Run("context cancelled", func(t *testing.T) {
+		_, client := newFakeRPC(t, map[string]func(json.RawMessage) (interface{}, error){
+			"sendTransaction":      func(json.RawMessage) (interface{}, error) { return tx.Signatures[0].String(), nil },
+			"isBlockhashValid":     func(json.RawMessage) (interface{}, error) { return rpcContext(true), nil },
+			"getSignatureStatuses": func(json.RawMessage) (interface{}, error) { return rpcContext([]interface{}{nil}), nil },
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if _, err := fastSender(client).Send(ctx, tx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		unsigned := *tx
+		unsigned.Signatures = nil
+		if _, err := NewTransactionSender(rpc.New("http://127.0.0.1:0")).Send(context.Background(), &unsigned); err == nil {
+			t.Errorf("Expected an error for an unsigned transaction")
+		}
+	})
+}
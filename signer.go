@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// Environment variables LoadSignerFromEnv reads, in order of preference
+const (
+	SOLANA_WALLET_PATH_ENV = "SOLANA_WALLET_PATH"
+	SOLANA_PRIVATE_KEY_ENV = "SOLANA_PRIVATE_KEY"
+)
+
+// Signer produces ed25519 signatures for one account. Keypairs held in memory and
+// external signers (hardware wallets, remote key services) both implement it.
+type Signer interface {
+	PublicKey() solana.PublicKey
+	Sign(message []byte) (solana.Signature, error)
+}
+
+// KeypairSigner signs with a private key held in memory
+type KeypairSigner struct {
+	privateKey solana.PrivateKey
+}
+
+// NewKeypairSigner creates a signer from a private key
+func NewKeypairSigner(privateKey solana.PrivateKey) (*KeypairSigner, error) {
+	if err := privateKey.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	return &KeypairSigner{privateKey: privateKey}, nil
+}
+
+// LoadKeypairFile loads a solana-keygen JSON keypair file
+func LoadKeypairFile(path string) (*KeypairSigner, error) {
+	privateKey, err := solana.PrivateKeyFromSolanaKeygenFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load keypair %s: %w", path, err)
+	}
+	return &KeypairSigner{privateKey: privateKey}, nil
+}
+
+// KeypairFromBase58 decodes a base58 encoded 64-byte secret key
+func KeypairFromBase58(secret string) (*KeypairSigner, error) {
+	privateKey, err := solana.PrivateKeyFromBase58(strings.TrimSpace(secret))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode base58 secret key: %w", err)
+	}
+	return &KeypairSigner{privateKey: privateKey}, nil
+}
+
+// LoadSigner loads a keypair from a keygen file path or, when no such file exists, a base58 secret
+func LoadSigner(source string) (*KeypairSigner, error) {
+	if info, err := os.Stat(source); err == nil && !info.IsDir() {
+		return LoadKeypairFile(source)
+	}
+	signer, err := KeypairFromBase58(source)
+	if err != nil {
+		// The source may be a secret, so it is left out of the error
+		return nil, fmt.Errorf("keypair is neither a readable keygen file nor a base58 secret key: %w", err)
+	}
+	return signer, nil
+}
+
+// LoadSignerFromEnv loads the keypair file named by SOLANA_WALLET_PATH, falling back to
+// the base58 secret in SOLANA_PRIVATE_KEY
+func LoadSignerFromEnv() (*KeypairSigner, error) {
+	if path := os.Getenv(SOLANA_WALLET_PATH_ENV); path != "" {
+		return LoadKeypairFile(path)
+	}
+	if secret := os.Getenv(SOLANA_PRIVATE_KEY_ENV); secret != "" {
+		return KeypairFromBase58(secret)
+	}
+	return nil, fmt.Errorf("neither %s nor %s is set", SOLANA_WALLET_PATH_ENV, SOLANA_PRIVATE_KEY_ENV)
+}
+
+// PublicKey returns the keypair's public key
+func (k *KeypairSigner) PublicKey() solana.PublicKey {
+	return k.privateKey.PublicKey()
+}
+
+// Sign signs message with the private key
+func (k *KeypairSigner) Sign(message []byte) (solana.Signature, error) {
+	return k.privateKey.Sign(message)
+}
+
+// ExternalSigner adapts a signing callback, such as a hardware wallet or a remote key
+// service, to the Signer interface
+type ExternalSigner struct {
+	publicKey solana.PublicKey
+	sign      func(message []byte) (solana.Signature, error)
+}
+
+// NewExternalSigner creates a signer for publicKey that delegates to sign
+func NewExternalSigner(publicKey solana.PublicKey, sign func(message []byte) (solana.Signature, error)) *ExternalSigner {
+	return &ExternalSigner{publicKey: publicKey, sign: sign}
+}
+
+// PublicKey returns the account the external signer signs for
+func (e *ExternalSigner) PublicKey() solana.PublicKey {
+	return e.publicKey
+}
+
+// Sign asks the external signer to sign message
+func (e *ExternalSigner) Sign(message []byte) (solana.Signature, error) {
+	return e.sign(message)
+}
+
+// SignTransaction signs tx offline with every signer the message requires. Each signature
+// is verified against its public key, so a misbehaving external signer is caught before
+// the transaction is sent. Signers the message does not require are rejected.
+func SignTransaction(tx *solana.Transaction, signers ...Signer) error {
+	message, err := tx.Message.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to serialize message: %w", err)
+	}
+
+	required := int(tx.Message.Header.NumRequiredSignatures)
+	if len(tx.Message.AccountKeys) < required {
+		return fmt.Errorf("message requires %d signatures but has %d account keys", required, len(tx.Message.AccountKeys))
+	}
+	signerKeys := tx.Message.AccountKeys[:required]
+
+	bySigner := make(map[solana.PublicKey]Signer, len(signers))
+	for _, signer := range signers {
+		if !signerKeys.Has(signer.PublicKey()) {
+			return fmt.Errorf("%s is not a signer of this transaction", signer.PublicKey())
+		}
+		bySigner[signer.PublicKey()] = signer
+	}
+
+	signatures := make([]solana.Signature, required)
+	for i, key := range signerKeys {
+		signer, ok := bySigner[key]
+		if !ok {
+			return fmt.Errorf("missing signer %s", key)
+		}
+		signature, err := signer.Sign(message)
+		if err != nil {
+			return fmt.Errorf("failed to sign with %s: %w", key, err)
+		}
+		if !signature.Verify(key, message) {
+			return fmt.Errorf("signer %s returned an invalid signature", key)
+		}
+		signatures[i] = signature
+	}
+	tx.Signatures = signatures
+	return nil
+}
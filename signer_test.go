@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+const testWalletPath = "test-wallets/test-wallet.json"
+
+func TestLoadSigners(t *testing.T) {
+	fromFile, err := LoadKeypairFile(testWalletPath)
+	if err != nil {
+		t.Fatalf("Failed to load keygen file: %v", err)
+	}
+	expected, _ := solana.PrivateKeyFromSolanaKeygenFile(testWalletPath)
+	if !fromFile.PublicKey().Equals(expected.PublicKey()) {
+		t.Errorf("Expected %s, got %s", expected.PublicKey(), fromFile.PublicKey())
+	}
+
+	fromBase58, err := KeypairFromBase58(" " + expected.String() + "\n")
+	if err != nil {
+		t.Fatalf("Failed to decode base58 secret: %v", err)
+	}
+	if !fromBase58.PublicKey().Equals(expected.PublicKey()) {
+		t.Errorf("Base58 secret decoded to %s", fromBase58.PublicKey())
+	}
+
+	for _, source := range []string{testWalletPath, expected.String()} {
+		signer, err := LoadSigner(source)
+		if err != nil {
+			t.Fatalf("LoadSigner failed: %v", err)
+		}
+		if !signer.PublicKey().Equals(expected.PublicKey()) {
+			t.Errorf("LoadSigner returned %s", signer.PublicKey())
+		}
+	}
+	if _, err := LoadSigner("test-wallets/missing.json"); err == nil {
+		t.Errorf("Expected an error for a missing keypair file")
+	}
+
+	t.Setenv(SOLANA_WALLET_PATH_ENV, "")
+	t.Setenv(SOLANA_PRIVATE_KEY_ENV, "")
+	if _, err := LoadSignerFromEnv(); err == nil {
+		t.Errorf("Expected an error without environment variables")
+	}
+	t.Setenv(SOLANA_PRIVATE_KEY_ENV, expected.String())
+	if signer, err := LoadSignerFromEnv(); err != nil || !signer.PublicKey().Equals(expected.PublicKey()) {
+		t.Errorf("Failed to load %s: %v", SOLANA_PRIVATE_KEY_ENV, err)
+	}
+	t.Setenv(SOLANA_WALLET_PATH_ENV, testWalletPath)
+	t.Setenv(SOLANA_PRIVATE_KEY_ENV, "not a key")
+	if signer, err := LoadSignerFromEnv(); err != nil || !signer.PublicKey().Equals(expected.PublicKey()) {
+		t.Errorf("%s should take precedence: %v", SOLANA_WALLET_PATH_ENV, err)
+	}
+}
+
+func TestSignTransaction(t *testing.T) {
+	payer := solana.NewWallet()
+	mint := solana.NewWallet()
+	payerSigner, _ := NewKeypairSigner(payer.PrivateKey)
+	compose := func() *solana.Transaction {
+		// Launchpad initialize needs the new base mint's signature as well as the payer's
+		tx, err := NewTransactionComposer(payer.PublicKey()).
+			SetRecentBlockhash(solana.Hash(solana.NewWallet().PublicKey())).
+			AddBuilder(NewCreateTokenInstruction().
+				SetPayer(payer.PublicKey()).SetCreator(payer.PublicKey()).
+				SetPlatformConfig(LetsBonkPlatformConfigID).SetBaseMint(mint.PublicKey()).
+				SetName("Test").SetSymbol("TST").
+				SetSupply(1_000).SetTotalBaseSell(800).SetTotalQuoteFundRaising(85)).
+			Build()
+		if err != nil {
+			t.Fatalf("Failed to compose transaction: %v", err)
+		}
+		return tx
+	}
+
+	// The mint is signed by an external signer backed by its private key
+	external := NewExternalSigner(mint.PublicKey(), func(message []byte) (solana.Signature, error) {
+		return mint.PrivateKey.Sign(message)
+	})
+	tx := compose()
+	if err := SignTransaction(tx, external, payerSigner); err != nil {
+		t.Fatalf("Failed to sign transaction: %v", err)
+	}
+	if err := tx.VerifySignatures(); err != nil {
+		t.Errorf("Signatures do not verify: %v", err)
+	}
+	if !tx.Signatures[0].Verify(payer.PublicKey(), mustMarshalMessage(t, tx)) {
+		t.Errorf("The fee payer's signature should come first")
+	}
+
+	tests := []struct {
+		name     string
+		signers  []Signer
+		expected string
+	}{
+		{"missing signer", []Signer{payerSigner}, "missing signer " + mint.PublicKey().String()},
+		{"unrelated signer", []Signer{payerSigner, external, NewExternalSigner(solana.NewWallet().PublicKey(), nil)}, "is not a signer"},
+		{"bad external signature", []Signer{payerSigner, NewExternalSigner(mint.PublicKey(), func(message []byte) (solana.Signature, error) {
+			return payer.PrivateKey.Sign(message)
+		})}, "invalid signature"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := compose()
+			err := SignTransaction(tx, tt.signers...)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+			}
+			if len(tx.Signatures) != 0 {
+				t.Errorf("A failed signing should leave the transaction unsigned")
+			}
+		})
+	}
+}
+
+func TestSignCommand(t *testing.T) {
+	wallet, _ := solana.PrivateKeyFromSolanaKeygenFile(testWalletPath)
+	recipient := solana.NewWallet().PublicKey()
+	blockhash := solana.Hash(solana.NewWallet().PublicKey())
+
+	var out bytes.Buffer
+	err := runSignCommand([]string{
+		"-keypair", testWalletPath,
+		"-to", recipient.String(), "-lamports", "1000",
+		"-compute-unit-price", "10",
+		"-blockhash", blockhash.String(),
+	}, nil, &out)
+	if err != nil {
+		t.Fatalf("sign failed: %v", err)
+	}
+	signed, err := solana.TransactionFromBase64(strings.TrimSpace(out.String()))
+	if err != nil {
+		t.Fatalf("Failed to decode signed transaction: %v", err)
+	}
+	if err := signed.VerifySignatures(); err != nil {
+		t.Errorf("Signed transaction does not verify: %v", err)
+	}
+	if !signed.Message.AccountKeys[0].Equals(wallet.PublicKey()) || signed.Message.RecentBlockhash != blockhash {
+		t.Errorf("Unexpected payer or blockhash in signed transaction")
+	}
+
+	// Re-signing an unsigned transaction read from stdin with a new blockhash
+	unsigned, _ := NewTransactionComposer(wallet.PublicKey()).
+		SetRecentBlockhash(solana.Hash(solana.NewWallet().PublicKey())).
+		AddInstruction(NewTransferInstruction(wallet.PublicKey(), recipient, 1)).
+		Build()
+	encoded, _ := unsigned.ToBase64()
+	t.Setenv(SOLANA_WALLET_PATH_ENV, testWalletPath)
+	out.Reset()
+	if err := runSignCommand([]string{"-tx", "-", "-blockhash", blockhash.String()}, strings.NewReader(encoded+"\n"), &out); err != nil {
+		t.Fatalf("sign from stdin failed: %v", err)
+	}
+	resigned, err := solana.TransactionFromBase64(strings.TrimSpace(out.String()))
+	if err != nil {
+		t.Fatalf("Failed to decode signed transaction: %v", err)
+	}
+	if err := resigned.VerifySignatures(); err != nil || resigned.Message.RecentBlockhash != blockhash {
+		t.Errorf("Expected a verified transaction with the new blockhash, got %v", err)
+	}
+
+	if err := runSignCommand([]string{"-keypair", testWalletPath}, nil, &out); err == nil {
+		t.Errorf("Expected an error without -tx or -to")
+	}
+}
+
+func mustMarshalMessage(t *testing.T, tx *solana.Transaction) []byte {
+	t.Helper()
+	message, err := tx.Message.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to serialize message: %v", err)
+	}
+	return message
+}
@@ -0,0 +1,521 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+// SOLANA_WS_ENDPOINT_ENV names the WebSocket endpoint the stream command subscribes to
+const SOLANA_WS_ENDPOINT_ENV = "SOLANA_WS_ENDPOINT"
+
+// StreamMode selects the subscription a TransactionStream uses
+type StreamMode string
+
+const (
+	// STREAM_MODE_LOGS subscribes to logs and fetches each mentioned transaction
+	STREAM_MODE_LOGS StreamMode = "logs"
+	// STREAM_MODE_BLOCKS subscribes to blocks, which carry the full transactions; not every
+	// RPC provider enables blockSubscribe
+	STREAM_MODE_BLOCKS StreamMode = "blocks"
+)
+
+// Default stream settings
+const (
+	DEFAULT_RECONNECT_DELAY          = time.Second
+	DEFAULT_MAX_RECONNECT_DELAY      = 30 * time.Second
+	DEFAULT_STREAM_FETCH_ATTEMPTS    = 5
+	DEFAULT_STREAM_FETCH_RETRY_DELAY = 400 * time.Millisecond
+	// DEFAULT_STREAM_FETCH_WORKERS is how many logs notifications are fetched at once
+	DEFAULT_STREAM_FETCH_WORKERS = 8
+	// DEFAULT_STREAM_MAX_GAP is the most transactions recovered per program after a reconnect
+	DEFAULT_STREAM_MAX_GAP = 1_000
+	// STREAM_SEEN_SIGNATURES is how many recent signatures are remembered to drop duplicates
+	STREAM_SEEN_SIGNATURES = 10_000
+)
+
+// ErrStreamGapTooLarge is reported in a StreamGap when more transactions were missed than
+// the stream recovers; the newest ones are recovered and the older ones are lost
+var ErrStreamGapTooLarge = errors.New("stream gap exceeds the recovery limit")
+
+// DefaultStreamPrograms are the Raydium programs a stream subscribes to by default
+var DefaultStreamPrograms = []solana.PublicKey{
+	RaydiumV4ProgramID,
+	RaydiumCpSwapProgramID,
+	RaydiumClmmProgramID,
+	RaydiumLaunchpadV1ProgramID,
+}
+
+// StreamGap describes transactions missed while the stream was disconnected
+type StreamGap struct {
+	Program solana.PublicKey
+	// After is the last signature seen for Program before the disconnect
+	After     solana.Signature
+	Recovered int
+	// Err is set when the gap could not be recovered in full
+	Err error
+}
+
+// TransactionStream follows Raydium programs over WebSocket and emits their parsed
+// transactions. Failed transactions are skipped. After a reconnect, transactions missed
+// while disconnected are recovered from each program's signature history before the
+// stream continues.
+type TransactionStream struct {
+	endpoint          string
+	source            BackfillSource
+	programs          []solana.PublicKey
+	mode              StreamMode
+	commitment        rpc.CommitmentType
+	reconnectDelay    time.Duration
+	maxReconnectDelay time.Duration
+	fetchAttempts     int
+	fetchRetryDelay   time.Duration
+	fetchWorkers      int
+	maxGap            int
+	onGap             func(StreamGap)
+	opts              []ParseOption
+
+	last map[solana.PublicKey]solana.Signature
+	seen *signatureWindow
+}
+
+// streamNotification is a subscription message tagged with the program it was for
+type streamNotification struct {
+	program solana.PublicKey
+	logs    *ws.LogResult
+	block   *ws.BlockResult
+}
+
+// streamFetch is a logs notification whose transaction is fetched in the background
+type streamFetch struct {
+	program   solana.PublicKey
+	signature solana.Signature
+	// skip is set when the notification needs no fetch
+	skip bool
+	// done is closed once tx and err are set
+	done chan struct{}
+	tx   *Transaction
+	err  error
+}
+
+// NewTransactionStream creates a logs mode stream at confirmed commitment over endpoint.
+// source fetches transactions and recovers gaps; it should read at the same commitment.
+// Without programs, DefaultStreamPrograms are followed.
+func NewTransactionStream(endpoint string, source BackfillSource, programs ...solana.PublicKey) *TransactionStream {
+	if len(programs) == 0 {
+		programs = DefaultStreamPrograms
+	}
+	return &TransactionStream{
+		endpoint:          endpoint,
+		source:            source,
+		programs:          programs,
+		mode:              STREAM_MODE_LOGS,
+		commitment:        rpc.CommitmentConfirmed,
+		reconnectDelay:    DEFAULT_RECONNECT_DELAY,
+		maxReconnectDelay: DEFAULT_MAX_RECONNECT_DELAY,
+		fetchAttempts:     DEFAULT_STREAM_FETCH_ATTEMPTS,
+		fetchRetryDelay:   DEFAULT_STREAM_FETCH_RETRY_DELAY,
+		fetchWorkers:      DEFAULT_STREAM_FETCH_WORKERS,
+		maxGap:            DEFAULT_STREAM_MAX_GAP,
+		onGap: func(gap StreamGap) {
+			log.Printf("Recovered %d transactions for %s after a disconnect (err: %v)", gap.Recovered, gap.Program, gap.Err)
+		},
+	}
+}
+
+// SetMode selects logs or blocks subscriptions
+func (s *TransactionStream) SetMode(mode StreamMode) *TransactionStream {
+	s.mode = mode
+	return s
+}
+
+// SetCommitment sets the subscription commitment; processed is not supported for blocks
+func (s *TransactionStream) SetCommitment(commitment rpc.CommitmentType) *TransactionStream {
+	s.commitment = commitment
+	return s
+}
+
+// SetReconnectDelay sets the delay before reconnecting, doubled after each failed attempt up to max
+func (s *TransactionStream) SetReconnectDelay(initial, max time.Duration) *TransactionStream {
+	s.reconnectDelay = initial
+	s.maxReconnectDelay = max
+	return s
+}
+
+// SetFetchRetry sets how often a logs notification's transaction is fetched while the node
+// does not have it yet
+func (s *TransactionStream) SetFetchRetry(attempts int, delay time.Duration) *TransactionStream {
+	s.fetchAttempts = max(attempts, 1)
+	s.fetchRetryDelay = delay
+	return s
+}
+
+// SetFetchWorkers sets how many logs notifications are fetched at once. Transactions are
+// still emitted in the order their notifications arrived.
+func (s *TransactionStream) SetFetchWorkers(workers int) *TransactionStream {
+	s.fetchWorkers = max(workers, 1)
+	return s
+}
+
+// SetMaxGap sets how many transactions are recovered per program after a reconnect. A
+// larger gap, e.g. because the last signature seen was on a dropped fork, is reported with
+// ErrStreamGapTooLarge instead of walking the program's whole history.
+func (s *TransactionStream) SetMaxGap(transactions int) *TransactionStream {
+	s.maxGap = max(transactions, 1)
+	return s
+}
+
+// SetGapHandler sets the callback told about every gap recovered after a reconnect
+func (s *TransactionStream) SetGapHandler(handler func(StreamGap)) *TransactionStream {
+	s.onGap = handler
+	return s
+}
+
+// SetParseOptions sets the options transactions are parsed with
+func (s *TransactionStream) SetParseOptions(opts ...ParseOption) *TransactionStream {
+	s.opts = opts
+	return s
+}
+
+// Run streams transactions to out until ctx is done, reconnecting whenever the connection
+// or a subscription drops, and returns the context's error. out is not closed. Run must not
+// be called concurrently.
+func (s *TransactionStream) Run(ctx context.Context, out chan<- *Transaction) error {
+	if s.mode != STREAM_MODE_LOGS && s.mode != STREAM_MODE_BLOCKS {
+		return fmt.Errorf("unknown stream mode %q", s.mode)
+	}
+	if s.last == nil {
+		s.last = map[solana.PublicKey]solana.Signature{}
+		s.seen = newSignatureWindow(STREAM_SEEN_SIGNATURES)
+	}
+
+	delay := s.reconnectDelay
+	for {
+		subscribed, err := s.session(ctx, out)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if subscribed {
+			delay = s.reconnectDelay
+		}
+		log.Printf("Stream disconnected, reconnecting in %v: %v", delay, err)
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+		delay = min(delay*2, s.maxReconnectDelay)
+	}
+}
+
+// session connects, subscribes to every program, recovers any gap and then handles
+// notifications until a subscription fails. subscribed reports whether every
+// subscription was established.
+func (s *TransactionStream) session(ctx context.Context, out chan<- *Transaction) (subscribed bool, err error) {
+	client, err := ws.Connect(ctx, s.endpoint)
+	if err != nil {
+		return false, err
+	}
+	defer client.Close()
+
+	sessionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	notifications := make(chan streamNotification)
+	failures := make(chan error, len(s.programs))
+	for _, program := range s.programs {
+		receive, err := s.subscribe(client, program)
+		if err != nil {
+			return false, fmt.Errorf("failed to subscribe to %s: %w", program, err)
+		}
+		go func() {
+			for {
+				notification, err := receive(sessionCtx)
+				if err != nil {
+					failures <- fmt.Errorf("subscription to %s: %w", program, err)
+					return
+				}
+				select {
+				case notifications <- notification:
+				case <-sessionCtx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	// Subscribing first means nothing falls between the recovered history and the live feed
+	for _, program := range s.programs {
+		if err := s.recoverGap(ctx, program, out); err != nil {
+			return true, err
+		}
+	}
+
+	// fetches holds the logs notifications in arrival order; no new notification is taken
+	// while all the fetch workers are busy
+	var fetches []*streamFetch
+	for {
+		receive := notifications
+		if len(fetches) >= s.fetchWorkers {
+			receive = nil
+		}
+		var fetched <-chan struct{}
+		if len(fetches) > 0 {
+			fetched = fetches[0].done
+		}
+		select {
+		case <-ctx.Done():
+			return true, ctx.Err()
+		case err := <-failures:
+			return true, err
+		case notification := <-receive:
+			if notification.logs != nil {
+				fetches = append(fetches, s.startFetch(sessionCtx, notification))
+			} else if err := s.handleBlock(ctx, notification, out); err != nil {
+				return true, err
+			}
+		case <-fetched:
+			fetch := fetches[0]
+			fetches = fetches[1:]
+			if err := s.finishFetch(ctx, fetch, out); err != nil {
+				return true, err
+			}
+		}
+	}
+}
+
+// subscribe starts the mode's subscription for program and returns its receive function
+func (s *TransactionStream) subscribe(client *ws.Client, program solana.PublicKey) (func(context.Context) (streamNotification, error), error) {
+	if s.mode == STREAM_MODE_BLOCKS {
+		rewards := false
+		sub, err := client.BlockSubscribe(ws.NewBlockSubscribeFilterMentionsAccountOrProgram(program), &ws.BlockSubscribeOpts{
+			Commitment:                     s.commitment,
+			Encoding:                       solana.EncodingBase64,
+			TransactionDetails:             rpc.TransactionDetailsFull,
+			Rewards:                        &rewards,
+			MaxSupportedTransactionVersion: &maxSupportedTransactionVersion,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context) (streamNotification, error) {
+			block, err := sub.Recv(ctx)
+			return streamNotification{program: program, block: block}, err
+		}, nil
+	}
+
+	sub, err := client.LogsSubscribeMentions(program, s.commitment)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context) (streamNotification, error) {
+		logs, err := sub.Recv(ctx)
+		return streamNotification{program: program, logs: logs}, err
+	}, nil
+}
+
+// startFetch fetches the transaction of a logs notification in the background. Failed and
+// already emitted transactions are not fetched.
+func (s *TransactionStream) startFetch(ctx context.Context, notification streamNotification) *streamFetch {
+	fetch := &streamFetch{
+		program:   notification.program,
+		signature: notification.logs.Value.Signature,
+		done:      make(chan struct{}),
+	}
+	if notification.logs.Value.Err != nil || s.seen.contains(fetch.signature) {
+		fetch.skip = true
+		close(fetch.done)
+		return fetch
+	}
+	go func() {
+		defer close(fetch.done)
+		fetch.tx, fetch.err = s.fetch(ctx, fetch.signature)
+	}()
+	return fetch
+}
+
+// finishFetch emits a fetched transaction. Only a cancelled context is an error;
+// transactions that cannot be fetched or parsed are logged and skipped.
+func (s *TransactionStream) finishFetch(ctx context.Context, fetch *streamFetch, out chan<- *Transaction) error {
+	defer s.advance(fetch.program, fetch.signature)
+	// Another program's notification may have emitted the transaction meanwhile
+	if fetch.skip || s.seen.contains(fetch.signature) {
+		return nil
+	}
+	if fetch.err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		log.Printf("Stream skipped %s: %v", fetch.signature, fetch.err)
+		return nil
+	}
+	return s.emit(ctx, fetch.tx, out)
+}
+
+// handleBlock emits the transactions of a block notification. Only a cancelled context is
+// an error; transactions that cannot be parsed are logged and skipped.
+func (s *TransactionStream) handleBlock(ctx context.Context, notification streamNotification, out chan<- *Transaction) error {
+	program := notification.program
+	block := notification.block
+	if block == nil || block.Value.Block == nil {
+		return nil
+	}
+	parsed, err := ParseBlock(block.Value.Slot, block.Value.Block, s.opts...)
+	if err != nil {
+		log.Printf("Stream skipped block %d: %v", block.Value.Slot, err)
+		return nil
+	}
+	for _, err := range parsed.Errors {
+		log.Printf("Stream skipped a transaction in slot %d: %v", block.Value.Slot, err)
+	}
+	for _, tx := range parsed.Transactions {
+		s.advance(program, tx.Signature)
+		if s.seen.contains(tx.Signature) {
+			continue
+		}
+		if err := s.emit(ctx, tx.Transaction, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetch retries while the node has not caught up with the notification yet
+func (s *TransactionStream) fetch(ctx context.Context, signature solana.Signature) (*Transaction, error) {
+	for attempt := 1; ; attempt++ {
+		tx, err := FetchAndParseTransaction(ctx, s.source, signature, s.opts...)
+		if !errors.Is(err, rpc.ErrNotFound) || attempt == s.fetchAttempts {
+			return tx, err
+		}
+		if err := sleepContext(ctx, s.fetchRetryDelay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// recoverGap emits, oldest first, the transactions of program newer than the last one seen
+// before the stream reconnected, at most maxGap of them. Nothing is recovered on the first
+// connection.
+func (s *TransactionStream) recoverGap(ctx context.Context, program solana.PublicKey, out chan<- *Transaction) error {
+	after, ok := s.last[program]
+	if !ok {
+		return nil
+	}
+
+	// List first so an oversized gap is caught before any transaction is fetched
+	listed, err := s.listGap(ctx, program, after)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if len(listed) > s.maxGap {
+		listed = listed[:s.maxGap]
+		err = fmt.Errorf("%w: more than %d transactions since %s", ErrStreamGapTooLarge, s.maxGap, after)
+	}
+
+	entries := make([]string, len(listed))
+	for i, entry := range listed {
+		entries[i] = entry.Signature.String()
+	}
+	results := ParseBatch(ctx, s.source, entries, s.fetchWorkers, s.opts...)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	gap := StreamGap{Program: program, After: after, Err: err}
+	for i := len(listed) - 1; i >= 0; i-- {
+		entry, result := listed[i], results[i]
+		s.advance(program, entry.Signature)
+		if entry.Err != nil || s.seen.contains(entry.Signature) {
+			continue
+		}
+		if result.Err != nil {
+			log.Printf("Stream skipped %s: %v", result.Input, result.Err)
+			continue
+		}
+		if err := s.emit(ctx, result.Transaction, out); err != nil {
+			return err
+		}
+		gap.Recovered++
+	}
+	if len(listed) > 0 || err != nil {
+		s.onGap(gap)
+	}
+	return nil
+}
+
+// listGap lists program's signatures newer than after, newest first, stopping once there
+// are more than maxGap of them
+func (s *TransactionStream) listGap(ctx context.Context, program solana.PublicKey, after solana.Signature) ([]*rpc.TransactionSignature, error) {
+	var listed []*rpc.TransactionSignature
+	var before solana.Signature
+	for len(listed) <= s.maxGap {
+		limit := min(s.maxGap+1-len(listed), MAX_SIGNATURES_PAGE_SIZE)
+		page, err := s.source.ListSignatures(ctx, program, before, after, limit)
+		if err != nil {
+			return listed, err
+		}
+		listed = append(listed, page...)
+		if len(page) < limit {
+			break
+		}
+		before = page[len(page)-1].Signature
+	}
+	return listed, nil
+}
+
+// advance records signature as the newest seen for program
+func (s *TransactionStream) advance(program solana.PublicKey, signature solana.Signature) {
+	s.last[program] = signature
+}
+
+func (s *TransactionStream) emit(ctx context.Context, tx *Transaction, out chan<- *Transaction) error {
+	s.seen.add(tx.Signature)
+	select {
+	case out <- tx:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// streamEndpointFromEnv returns SOLANA_WS_ENDPOINT, or the public mainnet WebSocket endpoint
+func streamEndpointFromEnv() string {
+	if endpoint := os.Getenv(SOLANA_WS_ENDPOINT_ENV); endpoint != "" {
+		return endpoint
+	}
+	return rpc.MainNetBeta_WS
+}
+
+// signatureWindow remembers the most recent signatures, forgetting the oldest first
+type signatureWindow struct {
+	set   map[solana.Signature]struct{}
+	order []solana.Signature
+	next  int
+}
+
+func newSignatureWindow(size int) *signatureWindow {
+	return &signatureWindow{set: make(map[solana.Signature]struct{}, size), order: make([]solana.Signature, 0, size)}
+}
+
+func (w *signatureWindow) contains(signature solana.Signature) bool {
+	_, ok := w.set[signature]
+	return ok
+}
+
+func (w *signatureWindow) add(signature solana.Signature) {
+	if w.contains(signature) {
+		return
+	}
+	if len(w.order) < cap(w.order) {
+		w.order = append(w.order, signature)
+	} else {
+		delete(w.set, w.order[w.next])
+		w.order[w.next] = signature
+		w.next = (w.next + 1) % len(w.order)
+	}
+	w.set[signature] = struct{}{}
+}
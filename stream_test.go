@@ -0,0 +1,318 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gorilla/websocket"
+)
+
+// fakeWSSession is one client connection to the WebSocket stand-in after it subscribed
+type fakeWSSession struct {
+	conn   *websocket.Conn
+	method string
+	params json.RawMessage
+}
+
+const fakeSubscriptionID = 7
+
+// newFakeWS serves a local WebSocket stand-in that accepts one subscription per
+// connection and hands the session to the test
+func newFakeWS(t *testing.T) (<-chan *fakeWSSession, string) {
+	t.Helper()
+	sessions := make(chan *fakeWSSession, 4)
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var request struct {
+			ID     uint64          `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := conn.ReadJSON(&request); err != nil {
+			return
+		}
+		if err := conn.WriteJSON(map[string]interface{}{"jsonrpc": "2.0", "id": request.ID, "result": fakeSubscriptionID}); err != nil {
+			return
+		}
+		sessions <- &fakeWSSession{conn: conn, method: request.Method, params: request.Params}
+		// Keep reading so pings are answered, until the test closes the connection
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return sessions, "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func (s *fakeWSSession) notify(t *testing.T, method string, result interface{}) {
+	t.Helper()
+	err := s.conn.WriteJSON(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  method,
+		"params":  map[string]interface{}{"subscription": fakeSubscriptionID, "result": result},
+	})
+	if err != nil {
+		t.Fatalf("Failed to send %s: %v", method, err)
+	}
+}
+
+func (s *fakeWSSession) notifyLogs(t *testing.T, signature solana.Signature, txErr interface{}) {
+	s.notify(t, "logsNotification", rpcContext(map[string]interface{}{
+		"signature": signature.String(),
+		"err":       txErr,
+		"logs":      []string{},
+	}))
+}
+
+func receiveTransaction(t *testing.T, out <-chan *Transaction) *Transaction {
+	t.Helper()
+	select {
+	case tx := <-out:
+		return tx
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for a transaction")
+		return nil
+	}
+}
+
+func TestTransactionStreamLogs(t *testing.T) {
+	history := newBackfillHistory(t, 4)
+	_, fetcher := history.fetcher(t)
+	sessions, url := newFakeWS(t)
+	program := RaydiumLaunchpadV1ProgramID
+
+	gaps := make(chan StreamGap, 1)
+	stream := NewTransactionStream(url, fetcher, program).
+		SetReconnectDelay(10*time.Millisecond, 20*time.Millisecond).
+		SetFetchRetry(2, 5*time.Millisecond).
+		SetGapHandler(func(gap StreamGap) { gaps <- gap })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := make(chan *Transaction)
+	done := make(chan error, 1)
+	go func() { done <- stream.Run(ctx, out) }()
+
+	first := <-sessions
+	if first.method != "logsSubscribe" || !strings.Contains(string(first.params), program.String()) {
+		t.Fatalf("Unexpected subscription %s %s", first.method, first.params)
+	}
+	first.notifyLogs(t, history.signatures[3], nil)
+	if tx := receiveTransaction(t, out); tx.Signature != history.signatures[3] || len(tx.Trade) != 1 {
+		t.Fatalf("Expected the notified buy, got %s", tx.Signature)
+	}
+	// Failed transactions are skipped without being fetched
+	first.notifyLogs(t, solana.Signature{1}, map[string]interface{}{"InstructionError": []interface{}{0, "InvalidAccountData"}})
+	first.notifyLogs(t, history.signatures[2], nil)
+	if tx := receiveTransaction(t, out); tx.Signature != history.signatures[2] {
+		t.Fatalf("Expected %s, got %s", history.signatures[2], tx.Signature)
+	}
+
+	// Transactions landing while disconnected are recovered oldest first after reconnecting
+	first.conn.Close()
+	second := <-sessions
+	for _, expected := range []solana.Signature{history.signatures[1], history.signatures[0]} {
+		if tx := receiveTransaction(t, out); tx.Signature != expected {
+			t.Fatalf("Expected recovered %s, got %s", expected, tx.Signature)
+		}
+	}
+	gap := <-gaps
+	if gap.Recovered != 2 || gap.After != history.signatures[2] || gap.Err != nil {
+		t.Errorf("Unexpected gap %+v", gap)
+	}
+
+	// The live notification for a recovered transaction is a duplicate
+	second.notifyLogs(t, history.signatures[0], nil)
+	select {
+	case tx := <-out:
+		t.Errorf("Expected the duplicate to be dropped, got %s", tx.Signature)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected Run to stop with context.Canceled, got %v", err)
+	}
+}
+
+func TestTransactionStreamGapLimit(t *testing.T) {
+	history := newBackfillHistory(t, 5)
+	fake, fetcher := history.fetcher(t)
+	sessions, url := newFakeWS(t)
+
+	gaps := make(chan StreamGap, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := make(chan *Transaction)
+	go NewTransactionStream(url, fetcher, RaydiumLaunchpadV1ProgramID).
+		SetReconnectDelay(10*time.Millisecond, 20*time.Millisecond).
+		SetMaxGap(2).
+		SetGapHandler(func(gap StreamGap) { gaps <- gap }).
+		Run(ctx, out)
+
+	first := <-sessions
+	first.notifyLogs(t, history.signatures[4], nil)
+	receiveTransaction(t, out)
+
+	// Four transactions land while disconnected; only the newest two are recovered
+	first.conn.Close()
+	<-sessions
+	for _, expected := range []solana.Signature{history.signatures[1], history.signatures[0]} {
+		if tx := receiveTransaction(t, out); tx.Signature != expected {
+			t.Fatalf("Expected recovered %s, got %s", expected, tx.Signature)
+		}
+	}
+	gap := <-gaps
+	if gap.Recovered != 2 || !errors.Is(gap.Err, ErrStreamGapTooLarge) {
+		t.Errorf("Expected the gap limit to be reported, got %+v", gap)
+	}
+	// The live transaction plus the two recovered ones; the older two are never fetched
+	if calls := fake.count("getTransaction"); calls != 3 {
+		t.Errorf("Expected only the kept transactions to be fetched, got %d getTransaction calls", calls)
+	}
+}
+
+func TestTransactionStreamFetchesConcurrently(t *testing.T) {
+	history := newBackfillHistory(t, 3)
+	// The first transaction is only served once the other two have been requested
+	others := make(chan struct{}, 2)
+	_, rpcURL := newFakeEndpoint(t, map[string]func(json.RawMessage) (interface{}, error){
+		"getTransaction": func(params json.RawMessage) (interface{}, error) {
+			var args []interface{}
+			json.Unmarshal(params, &args)
+			signature, _ := args[0].(string)
+			if signature != history.signatures[0].String() {
+				others <- struct{}{}
+			} else {
+				for i := 0; i < 2; i++ {
+					select {
+					case <-others:
+					case <-time.After(2 * time.Second):
+						return nil, nil
+					}
+				}
+			}
+			return getTransactionResult(history.txBytes[signature], 5000, nil), nil
+		},
+	})
+	sessions, url := newFakeWS(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := make(chan *Transaction)
+	stream := NewTransactionStream(url, NewRPCFetcher(rpcURL).SetRateLimit(0), RaydiumLaunchpadV1ProgramID).
+		SetFetchWorkers(3).
+		SetFetchRetry(1, 0)
+	go stream.Run(ctx, out)
+
+	session := <-sessions
+	for _, signature := range history.signatures {
+		session.notifyLogs(t, signature, nil)
+	}
+	// Transactions come out in notification order even though the first one is fetched last
+	for _, expected := range history.signatures {
+		if tx := receiveTransaction(t, out); tx.Signature != expected {
+			t.Fatalf("Expected %s, got %s", expected, tx.Signature)
+		}
+	}
+}
+
+func TestTransactionStreamBlocks(t *testing.T) {
+	history := newBackfillHistory(t, 2)
+	_, fetcher := history.fetcher(t)
+	sessions, url := newFakeWS(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := make(chan *Transaction)
+	go NewTransactionStream(url, fetcher, RaydiumLaunchpadV1ProgramID).SetMode(STREAM_MODE_BLOCKS).Run(ctx, out)
+
+	session := <-sessions
+	if session.method != "blockSubscribe" || !strings.Contains(string(session.params), "mentionsAccountOrProgram") {
+		t.Fatalf("Unexpected subscription %s %s", session.method, session.params)
+	}
+
+	_, failedBytes := signedBuyTransaction(t)
+	transactions := []interface{}{
+		getTransactionResult(history.txBytes[history.signatures[1].String()], 6000, nil),
+		getTransactionResult(failedBytes, 6000, map[string]interface{}{"InstructionError": []interface{}{0, "InvalidAccountData"}}),
+		getTransactionResult(history.txBytes[history.signatures[0].String()], 6000, nil),
+	}
+	session.notify(t, "blockNotification", rpcContext(map[string]interface{}{
+		"slot": 6000,
+		"err":  nil,
+		"block": map[string]interface{}{
+			"blockhash":         solana.Hash{1}.String(),
+			"previousBlockhash": solana.Hash{2}.String(),
+			"parentSlot":        5999,
+			"transactions":      transactions,
+			"blockTime":         1_700_000_000,
+			"blockHeight":       300,
+		},
+	}))
+
+	for _, expected := range []solana.Signature{history.signatures[1], history.signatures[0]} {
+		tx := receiveTransaction(t, out)
+		if tx.Signature != expected || tx.Slot != 6000 {
+			t.Fatalf("Expected %s in slot 6000, got %s in slot %d", expected, tx.Signature, tx.Slot)
+		}
+	}
+}
+
+func TestStreamCommandStdout(t *testing.T) {
+	history := newBackfillHistory(t, 1)
+	_, rpcURL := history.endpoint(t)
+	sessions, wsURL := newFakeWS(t)
+
+	cmd := mainCommand([]string{SOLANA_RPC_ENDPOINT_ENV + "=" + rpcURL}, "stream", "-ws", wsURL, "-programs", RaydiumLaunchpadV1ProgramID.String(), "-json")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("Failed to capture stdout: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start stream: %v", err)
+	}
+	defer cmd.Wait()
+	defer cmd.Process.Kill()
+
+	select {
+	case session := <-sessions:
+		session.notifyLogs(t, history.signatures[0], nil)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for the stream to subscribe")
+	}
+	// The first line on stdout is the transaction, not parser diagnostics
+	line, err := bufio.NewReader(stdout).ReadString('\n')
+	var record struct{ Transaction struct{ Signature string } }
+	if err != nil || json.Unmarshal([]byte(line), &record) != nil || record.Transaction.Signature != history.signatures[0].String() {
+		t.Errorf("Expected the transaction as a JSON line, got %q (%v)", line, err)
+	}
+}
+
+func TestSignatureWindow(t *testing.T) {
+	window := newSignatureWindow(2)
+	a, b, c := solana.Signature{1}, solana.Signature{2}, solana.Signature{3}
+	window.add(a)
+	window.add(b)
+	window.add(b)
+	window.add(c)
+	if window.contains(a) || !window.contains(b) || !window.contains(c) {
+		t.Errorf("Expected the oldest signature to be forgotten")
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// MetaplexTokenMetadataProgramID is the Metaplex Token Metadata program
+var MetaplexTokenMetadataProgramID = solana.MustPublicKeyFromBase58("metaqbxxUerdq28cj1RbAWkYQm3ybzjb6a8bt518x1s")
+
+// Metaplex Token Metadata instruction discriminators
+const (
+	METAPLEX_INSTRUCTION_CREATE_METADATA_ACCOUNT_V3 = 33
+)
+
+// Metaplex metadata field length limits, in bytes
+const (
+	METADATA_MAX_NAME_LENGTH   = 32
+	METADATA_MAX_SYMBOL_LENGTH = 10
+	METADATA_MAX_URI_LENGTH    = 200
+)
+
+// Token program instructions that set mint authorities
+const (
+	TOKEN_INSTRUCTION_INITIALIZE_MINT  = 0
+	TOKEN_INSTRUCTION_SET_AUTHORITY    = 6
+	TOKEN_INSTRUCTION_INITIALIZE_MINT2 = 20
+
+	tokenAuthorityTypeMintTokens    = 0
+	tokenAuthorityTypeFreezeAccount = 1
+)
+
+// TokenMetadata holds the descriptive fields of a Metaplex metadata account
+type TokenMetadata struct {
+	Mint   solana.PublicKey
+	Name   string
+	Symbol string
+	URI    string
+}
+
+// DecodeCreateMetadataAccountV3 decodes a Metaplex CreateMetadataAccountV3 instruction.
+// accounts are the instruction's resolved accounts: metadata, mint, mint authority, payer, update authority.
+func DecodeCreateMetadataAccountV3(accounts []solana.PublicKey, data []byte) (*TokenMetadata, error) {
+	if len(data) == 0 || data[0] != METAPLEX_INSTRUCTION_CREATE_METADATA_ACCOUNT_V3 {
+		return nil, fmt.Errorf("not a CreateMetadataAccountV3 instruction")
+	}
+	if len(accounts) < 2 {
+		return nil, fmt.Errorf("insufficient accounts for CreateMetadataAccountV3: got %d", len(accounts))
+	}
+
+	r := newAccountReader(data)
+	r.skip(1)
+	metadata := &TokenMetadata{
+		Mint:   accounts[1],
+		Name:   trimMetadataString(r.borshString()),
+		Symbol: trimMetadataString(r.borshString()),
+		URI:    trimMetadataString(r.borshString()),
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("failed to decode metadata: %w", r.err)
+	}
+	return metadata, nil
+}
+
+// trimMetadataString strips the null padding older metadata writers use for fixed-size fields
+func trimMetadataString(s string) string {
+	return strings.TrimRight(s, "\x00 ")
+}
+
+// applyTokenMetadata fills name, symbol and URI on the create entry for the metadata's mint,
+// keeping values already decoded from the launch parameters
+func applyTokenMetadata(result *Transaction, metadata *TokenMetadata) {
+	createInfo := findCreateByMint(result, metadata.Mint)
+	if createInfo == nil {
+		return
+	}
+	if createInfo.TokenName == "" {
+		createInfo.TokenName = metadata.Name
+	}
+	if createInfo.TokenSymbol == "" || createInfo.TokenSymbol == "UNKNOWN" {
+		createInfo.TokenSymbol = metadata.Symbol
+	}
+	if createInfo.TokenURI == "" {
+		createInfo.TokenURI = metadata.URI
+	}
+}
+
+// parseMetaplexInstruction parses Metaplex Token Metadata instructions from a compiled instruction
+func parseMetaplexInstruction(instruction solana.CompiledInstruction, message *solana.Message, index int, result *Transaction) error {
+	accounts, err := resolveInstructionAccounts(instruction, message)
+	if err != nil {
+		return err
+	}
+	return applyMetaplexInstruction(accounts, instruction.Data, index, result)
+}
+
+// parseGeyserMetaplexInstruction parses Metaplex Token Metadata instructions in Geyser format
+func parseGeyserMetaplexInstruction(instruction GeyserInstruction, index int, result *Transaction, meta *TransactionMeta) error {
+	return applyMetaplexInstruction(instruction.Accounts, instruction.Data, index, result)
+}
+
+func applyMetaplexInstruction(accounts []solana.PublicKey, data []byte, index int, result *Transaction) error {
+	if len(data) == 0 || data[0] != METAPLEX_INSTRUCTION_CREATE_METADATA_ACCOUNT_V3 {
+		return nil
+	}
+	metadata, err := DecodeCreateMetadataAccountV3(accounts, data)
+	if err != nil {
+		return err
+	}
+
+	result.logf("Token metadata at index %d: %s (%s) %s", index, metadata.Name, metadata.Symbol, metadata.URI)
+	applyTokenMetadata(result, metadata)
+	return nil
+}
+
+// applyMintAuthorityInstruction tracks InitializeMint, InitializeMint2 and SetAuthority on a
+// mint created earlier in the transaction
+func applyMintAuthorityInstruction(accounts []solana.PublicKey, data []byte, result *Transaction) {
+	if len(data) == 0 || len(accounts) == 0 {
+		return
+	}
+	createInfo := findCreateByMint(result, accounts[0])
+	if createInfo == nil {
+		return
+	}
+
+	r := newAccountReader(data)
+	switch r.u8() {
+	case TOKEN_INSTRUCTION_INITIALIZE_MINT, TOKEN_INSTRUCTION_INITIALIZE_MINT2:
+		decimals := r.u8()
+		mintAuthority := r.pubkey()
+		freezeAuthority := readOptionalPubkey(r)
+		if r.err != nil {
+			return
+		}
+		createInfo.TokenDecimals = decimals
+		createInfo.MintAuthority = mintAuthority
+		createInfo.FreezeAuthority = freezeAuthority
+	case TOKEN_INSTRUCTION_SET_AUTHORITY:
+		authorityType := r.u8()
+		newAuthority := readOptionalPubkey(r)
+		if r.err != nil {
+			return
+		}
+		switch authorityType {
+		case tokenAuthorityTypeMintTokens:
+			createInfo.MintAuthority = newAuthority
+		case tokenAuthorityTypeFreezeAccount:
+			createInfo.FreezeAuthority = newAuthority
+		}
+	}
+}
+
+// readOptionalPubkey reads an optional public key encoded as a one-byte tag followed by the key;
+// it returns the zero key when the option is empty
+func readOptionalPubkey(r *accountReader) solana.PublicKey {
+	if r.u8() == 0 {
+		return solana.PublicKey{}
+	}
+	return r.pubkey()
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// DEFAULT_TOKEN_DECIMALS is assumed for mints with no known or observed decimals
+const DEFAULT_TOKEN_DECIMALS = 9
+
+// DEFAULT_TOKEN_CACHE_SIZE is how many tokens learned on chain a CachedTokenInfoProvider keeps
+const DEFAULT_TOKEN_CACHE_SIZE = 100_000
+
+// TokenInfoProvider resolves token metadata by mint
+type TokenInfoProvider interface {
+	// TokenInfo returns the metadata for mint and whether the mint is known. A mint that is
+	// not known may still come back with its Mint set and the decimals observed on chain.
+	TokenInfo(mint solana.PublicKey) (TokenInfo, bool)
+	// ObserveDecimals records the decimals of a mint seen on chain, e.g. in token balance metadata
+	ObserveDecimals(mint solana.PublicKey, decimals uint8)
+	// ObserveToken records metadata decoded on chain, e.g. from a Launchpad create
+	ObserveToken(token TokenInfo)
+}
+
+// cachedToken is one CachedTokenInfoProvider entry
+type cachedToken struct {
+	info TokenInfo
+	// known is false while only the decimals have been observed
+	known bool
+	// learned entries came from chain data and may be evicted; added ones stay
+	learned bool
+}
+
+// CachedTokenInfoProvider is a thread-safe in-memory TokenInfoProvider. Tokens added directly
+// or loaded from a list are kept; tokens learned on chain are evicted oldest first once there
+// are more than the cache size.
+type CachedTokenInfoProvider struct {
+	mu      sync.RWMutex
+	tokens  map[solana.PublicKey]cachedToken
+	learned []solana.PublicKey
+	size    int
+}
+
+// NewCachedTokenInfoProvider creates a provider seeded with the given tokens
+func NewCachedTokenInfoProvider(tokens ...TokenInfo) *CachedTokenInfoProvider {
+	p := &CachedTokenInfoProvider{
+		tokens: make(map[solana.PublicKey]cachedToken, len(tokens)),
+		size:   DEFAULT_TOKEN_CACHE_SIZE,
+	}
+	for _, token := range tokens {
+		p.Add(token)
+	}
+	return p
+}
+
+// SetCacheSize sets how many tokens learned on chain are kept
+func (p *CachedTokenInfoProvider) SetCacheSize(size int) *CachedTokenInfoProvider {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.size = max(size, 0)
+	p.evict()
+	return p
+}
+
+// Add inserts or replaces a token
+func (p *CachedTokenInfoProvider) Add(token TokenInfo) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tokens[token.Mint] = cachedToken{info: token, known: true}
+}
+
+// TokenInfo returns the cached metadata for mint
+func (p *CachedTokenInfoProvider) TokenInfo(mint solana.PublicKey) (TokenInfo, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	entry := p.tokens[mint]
+	return entry.info, entry.known
+}
+
+// ObserveDecimals caches decimals for mint, keeping any symbol and name already known
+func (p *CachedTokenInfoProvider) ObserveDecimals(mint solana.PublicKey, decimals uint8) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry, exists := p.tokens[mint]
+	if !exists {
+		entry = cachedToken{info: TokenInfo{Mint: mint, Symbol: "UNKNOWN", Name: "Unknown Token"}}
+	}
+	entry.info.Decimals = decimals
+	p.learn(entry, exists)
+}
+
+// ObserveToken caches token unless it is already known from a token list
+func (p *CachedTokenInfoProvider) ObserveToken(token TokenInfo) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry, exists := p.tokens[token.Mint]
+	if exists && entry.known && !entry.learned {
+		return
+	}
+	p.learn(cachedToken{info: token, known: true}, exists)
+}
+
+// learn stores an entry learned on chain; the caller holds p.mu
+func (p *CachedTokenInfoProvider) learn(entry cachedToken, exists bool) {
+	mint := entry.info.Mint
+	if exists && !p.tokens[mint].learned {
+		// Added tokens stay added
+		p.tokens[mint] = entry
+		return
+	}
+	entry.learned = true
+	p.tokens[mint] = entry
+	if !exists {
+		p.learned = append(p.learned, mint)
+		p.evict()
+	}
+}
+
+// evict drops the oldest learned tokens beyond the cache size; the caller holds p.mu
+func (p *CachedTokenInfoProvider) evict() {
+	for len(p.learned) > p.size {
+		oldest := p.learned[0]
+		p.learned = p.learned[1:]
+		if p.tokens[oldest].learned {
+			delete(p.tokens, oldest)
+		}
+	}
+}
+
+// Len returns the number of cached tokens
+func (p *CachedTokenInfoProvider) Len() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.tokens)
+}
+
+// LoadFile loads a token list from a .json or .csv file
+func (p *CachedTokenInfoProvider) LoadFile(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open token list: %w", err)
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return p.LoadJSON(file)
+	case ".csv":
+		return p.LoadCSV(file)
+	default:
+		return 0, fmt.Errorf("unsupported token list format %q (want .json or .csv)", filepath.Ext(path))
+	}
+}
+
+// tokenListEntry is one token in a JSON token list; "address" is the Solana token-list spelling of "mint"
+type tokenListEntry struct {
+	Mint     string `json:"mint"`
+	Address  string `json:"address"`
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+	Decimals uint8  `json:"decimals"`
+}
+
+// LoadJSON loads tokens from either a JSON array of tokens or an object with a "tokens" array
+func (p *CachedTokenInfoProvider) LoadJSON(r io.Reader) (int, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read token list: %w", err)
+	}
+
+	var entries []tokenListEntry
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		var list struct {
+			Tokens []tokenListEntry `json:"tokens"`
+		}
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return 0, fmt.Errorf("failed to decode token list: %w", err)
+		}
+		entries = list.Tokens
+	}
+
+	tokens := make([]TokenInfo, 0, len(entries))
+	for i, entry := range entries {
+		address := entry.Mint
+		if address == "" {
+			address = entry.Address
+		}
+		mint, err := solana.PublicKeyFromBase58(address)
+		if err != nil {
+			return 0, fmt.Errorf("token %d: invalid mint %q: %w", i, address, err)
+		}
+		tokens = append(tokens, TokenInfo{Mint: mint, Symbol: entry.Symbol, Name: entry.Name, Decimals: entry.Decimals})
+	}
+
+	for _, token := range tokens {
+		p.Add(token)
+	}
+	return len(tokens), nil
+}
+
+// LoadCSV loads tokens from CSV with a header row naming the mint (or address), symbol, name
+// and decimals columns
+func (p *CachedTokenInfoProvider) LoadCSV(r io.Reader) (int, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read token list: %w", err)
+	}
+	if len(records) == 0 {
+		return 0, nil
+	}
+
+	columns := map[string]int{}
+	for i, header := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(header))] = i
+	}
+	mintColumn, ok := columns["mint"]
+	if !ok {
+		if mintColumn, ok = columns["address"]; !ok {
+			return 0, fmt.Errorf("token list header has no mint or address column")
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	tokens := make([]TokenInfo, 0, len(records)-1)
+	for line, record := range records[1:] {
+		mint, err := solana.PublicKeyFromBase58(strings.TrimSpace(record[mintColumn]))
+		if err != nil {
+			return 0, fmt.Errorf("line %d: invalid mint: %w", line+2, err)
+		}
+		var decimals uint64
+		if value := field(record, "decimals"); value != "" {
+			if decimals, err = strconv.ParseUint(value, 10, 8); err != nil {
+				return 0, fmt.Errorf("line %d: invalid decimals %q", line+2, value)
+			}
+		}
+		tokens = append(tokens, TokenInfo{
+			Mint:     mint,
+			Symbol:   field(record, "symbol"),
+			Name:     field(record, "name"),
+			Decimals: uint8(decimals),
+		})
+	}
+
+	for _, token := range tokens {
+		p.Add(token)
+	}
+	return len(tokens), nil
+}
+
+// defaultTokens seeds the package-level provider
+var defaultTokens = []TokenInfo{
+	{Mint: solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112"), Symbol: "SOL", Name: "Solana", Decimals: 9},
+	{Mint: solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"), Symbol: "USDC", Name: "USD Coin", Decimals: 6},
+	{Mint: solana.MustPublicKeyFromBase58("Es9vMFrzaCERmJfrF4H2FYD4KCoNkY11McCe8BenwNYB"), Symbol: "USDT", Name: "Tether USD", Decimals: 6},
+}
+
+var (
+	tokenProviderMu sync.RWMutex
+	tokenProvider   TokenInfoProvider = NewCachedTokenInfoProvider(defaultTokens...)
+)
+
+// SetTokenInfoProvider replaces the provider used by the parser and GetTokenInfo
+func SetTokenInfoProvider(provider TokenInfoProvider) {
+	tokenProviderMu.Lock()
+	defer tokenProviderMu.Unlock()
+	tokenProvider = provider
+}
+
+// CurrentTokenInfoProvider returns the provider used by the parser and GetTokenInfo
+func CurrentTokenInfoProvider() TokenInfoProvider {
+	tokenProviderMu.RLock()
+	defer tokenProviderMu.RUnlock()
+	return tokenProvider
+}
+
+// lookupToken resolves a mint through the current provider
+func lookupToken(mint solana.PublicKey) (TokenInfo, bool) {
+	return CurrentTokenInfoProvider().TokenInfo(mint)
+}
+
+// ObserveTransactionTokens caches the token metadata a parsed transaction carries in provider.
+// Parsing never does this by itself, since the metadata comes from untrusted chain data.
+func ObserveTransactionTokens(provider TokenInfoProvider, tx *Transaction) {
+	for _, token := range tx.Tokens {
+		if token.Symbol == "" {
+			provider.ObserveDecimals(token.Mint, token.Decimals)
+		} else {
+			provider.ObserveToken(token)
+		}
+	}
+}
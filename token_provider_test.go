@@ -0,0 +1,158 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+func TestCachedTokenInfoProviderLoadJSON(t *testing.T) {
+	provider := NewCachedTokenInfoProvider()
+
+	// Solana token-list layout with "address"
+	count, err := provider.LoadJSON(strings.NewReader(`{"tokens":[
+		{"address":"DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263","symbol":"Bonk","name":"Bonk","decimals":5}
+	]}`))
+	if err != nil || count != 1 {
+		t.Fatalf("Failed to load token list: count %d, err %v", count, err)
+	}
+
+	// Plain array with "mint"
+	count, err = provider.LoadJSON(strings.NewReader(`[
+		{"mint":"JUPyiwrYJFskUPiHa7hkeR8VUtAeFoSYbKedZNsDvCN","symbol":"JUP","name":"Jupiter","decimals":6}
+	]`))
+	if err != nil || count != 1 {
+		t.Fatalf("Failed to load token array: count %d, err %v", count, err)
+	}
+
+	bonk, ok := provider.TokenInfo(solana.MustPublicKeyFromBase58("DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263"))
+	if !ok || bonk.Symbol != "Bonk" || bonk.Decimals != 5 {
+		t.Errorf("Unexpected Bonk entry: %+v (found %v)", bonk, ok)
+	}
+	if provider.Len() != 2 {
+		t.Errorf("Expected 2 tokens, got %d", provider.Len())
+	}
+
+	if _, err := provider.LoadJSON(strings.NewReader(`[{"mint":"not-a-key"}]`)); err == nil {
+		t.Errorf("Expected error for invalid mint")
+	}
+}
+
+func TestCachedTokenInfoProviderLoadCSVFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.csv")
+	csv := "symbol,mint,decimals,name\n" +
+		"WIF,EKpQGSJtjMFqKZ9KQanSqYXRcF8fBopzLHYxdM65zcjm,6,dogwifhat\n"
+	if err := os.WriteFile(path, []byte(csv), 0o600); err != nil {
+		t.Fatalf("Failed to write token list: %v", err)
+	}
+
+	provider := NewCachedTokenInfoProvider()
+	count, err := provider.LoadFile(path)
+	if err != nil || count != 1 {
+		t.Fatalf("Failed to load CSV: count %d, err %v", count, err)
+	}
+	wif, ok := provider.TokenInfo(solana.MustPublicKeyFromBase58("EKpQGSJtjMFqKZ9KQanSqYXRcF8fBopzLHYxdM65zcjm"))
+	if !ok || wif.Symbol != "WIF" || wif.Name != "dogwifhat" || wif.Decimals != 6 {
+		t.Errorf("Unexpected WIF entry: %+v (found %v)", wif, ok)
+	}
+
+	if _, err := provider.LoadFile(filepath.Join(t.TempDir(), "tokens.txt")); err == nil {
+		t.Errorf("Expected error for unsupported extension")
+	}
+}
+
+func TestTokenInfoProviderLearnsDecimalsFromMeta(t *testing.T) {
+	previous := CurrentTokenInfoProvider()
+	defer SetTokenInfoProvider(previous)
+	SetTokenInfoProvider(NewCachedTokenInfoProvider(defaultTokens...))
+
+	mint := solana.NewWallet().PublicKey()
+	if info := GetTokenInfo(mint); info.Decimals != DEFAULT_TOKEN_DECIMALS || info.Symbol != "UNKNOWN" {
+		t.Fatalf("Unexpected default for unknown mint: %+v", info)
+	}
+
+	result := &Transaction{}
+	applyTransactionMeta(result, &TransactionMeta{
+		PreTokenBalances:  []TokenBalance{{Mint: mint, Decimals: 6}},
+		PostTokenBalances: []TokenBalance{{Mint: mint, Decimals: 6, Amount: 1}},
+	})
+	if len(result.Tokens) != 1 || result.Tokens[0] != (TokenInfo{Mint: mint, Decimals: 6}) {
+		t.Fatalf("Expected the balance decimals on the transaction, got %+v", result.Tokens)
+	}
+	// Parsing leaves the provider alone
+	if info := GetTokenInfo(mint); info.Decimals != DEFAULT_TOKEN_DECIMALS {
+		t.Errorf("Expected parsing not to cache decimals, got %+v", info)
+	}
+
+	ObserveTransactionTokens(CurrentTokenInfoProvider(), result)
+	if info := GetTokenInfo(mint); info.Decimals != 6 || info.Symbol != "UNKNOWN" {
+		t.Errorf("Expected learned decimals 6 on an unknown token, got %+v", info)
+	}
+	// Knowing the decimals does not make the token known
+	if enhanced := getEnhancedTokenInfo(mint); enhanced.IsKnown || enhanced.Decimals != 6 || enhanced.Description != "Unknown token" {
+		t.Errorf("Expected an observed token to stay unknown, got %+v", enhanced)
+	}
+
+	// Observing decimals keeps known symbols
+	usdc := solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")
+	CurrentTokenInfoProvider().ObserveDecimals(usdc, 6)
+	if info := GetTokenInfo(usdc); info.Symbol != "USDC" {
+		t.Errorf("Expected USDC symbol to survive, got %q", info.Symbol)
+	}
+}
+
+func TestCachedTokenInfoProviderConcurrentAccess(t *testing.T) {
+	provider := NewCachedTokenInfoProvider(defaultTokens...)
+	mints := uniqueAccounts(16)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for j, mint := range mints {
+				provider.ObserveDecimals(mint, uint8((worker+j)%10))
+				provider.TokenInfo(mint)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if provider.Len() != len(defaultTokens)+len(mints) {
+		t.Errorf("Expected %d tokens, got %d", len(defaultTokens)+len(mints), provider.Len())
+	}
+}
+
+func TestCachedTokenInfoProviderEvictsLearnedTokens(t *testing.T) {
+	provider := NewCachedTokenInfoProvider(defaultTokens...).SetCacheSize(3)
+	mints := uniqueAccounts(5)
+
+	provider.ObserveToken(TokenInfo{Mint: mints[0], Symbol: "FIRST", Name: "First", Decimals: 6})
+	for _, mint := range mints[1:] {
+		provider.ObserveDecimals(mint, 9)
+	}
+	if provider.Len() != len(defaultTokens)+3 {
+		t.Fatalf("Expected %d tokens, got %d", len(defaultTokens)+3, provider.Len())
+	}
+	for i, mint := range mints {
+		info, known := provider.TokenInfo(mint)
+		if evicted := i < 2; evicted != info.Mint.IsZero() || known {
+			t.Errorf("Mint %d: unexpected entry %+v (known %v)", i, info, known)
+		}
+	}
+
+	// Tokens from a list are never evicted, and chain data does not rename them
+	usdc := defaultTokens[1]
+	provider.ObserveToken(TokenInfo{Mint: usdc.Mint, Symbol: "FAKE", Decimals: 2})
+	provider.SetCacheSize(0)
+	if info, known := provider.TokenInfo(usdc.Mint); !known || info != usdc {
+		t.Errorf("Expected %+v to stay, got %+v", usdc, info)
+	}
+	if provider.Len() != len(defaultTokens) {
+		t.Errorf("Expected only the listed tokens, got %d", provider.Len())
+	}
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"log"
+
 	"github.com/gagliardetto/solana-go"
 )
 
@@ -17,6 +19,32 @@ type Transaction struct {
 	Migrate   []Migration
 	SwapBuys  []SwapBuy
 	SwapSells []SwapSell
+
+	// Tokens is the token metadata seen in the transaction: name, symbol and decimals from
+	// Launchpad creates, and decimals alone, with no symbol, from token balance metadata.
+	// See ObserveTransactionTokens.
+	Tokens []TokenInfo
+
+	// quiet silences the parser's diagnostics while the transaction is parsed; see
+	// WithoutParseLogging
+	quiet bool
+}
+
+// logf logs a parser diagnostic unless the transaction is parsed quietly
+func (t *Transaction) logf(format string, args ...interface{}) {
+	if !t.quiet {
+		log.Printf(format, args...)
+	}
+}
+
+// observeToken records token metadata seen in the transaction; the first entry per mint wins
+func (t *Transaction) observeToken(token TokenInfo) {
+	for _, seen := range t.Tokens {
+		if seen.Mint.Equals(token.Mint) {
+			return
+		}
+	}
+	t.Tokens = append(t.Tokens, token)
 }
 
 // CreateInfo represents token/pool creation information
@@ -28,6 +56,14 @@ type CreateInfo struct {
 	Creator       solana.PublicKey
 	Amount        uint64
 	Timestamp     int64
+
+	// Token metadata from the Launchpad initialize parameters or the Metaplex CPI
+	TokenName string
+	TokenURI  string
+	Supply    uint64
+	// MintAuthority and FreezeAuthority are zero when the authority is disabled
+	MintAuthority   solana.PublicKey
+	FreezeAuthority solana.PublicKey
 }
 
 // TradeInfo represents general trade information
@@ -50,6 +86,21 @@ type Migration struct {
 	Amount    uint64
 	Owner     solana.PublicKey
 	Timestamp int64
+
+	// Fields below are filled for Launchpad migrate_to_amm / migrate_to_cpswap instructions
+	Destination  string // MIGRATION_DESTINATION_AMM_V4 or MIGRATION_DESTINATION_CPSWAP
+	ToProgram    solana.PublicKey
+	QuoteMint    solana.PublicKey
+	LpMint       solana.PublicKey
+	ToBaseVault  solana.PublicKey
+	ToQuoteVault solana.PublicKey
+	// BaseAmount and QuoteAmount are the liquidity deposited into the new pool's vaults;
+	// they are only known when the transaction is parsed with its metadata
+	BaseAmount  uint64
+	QuoteAmount uint64
+	LpAccount   solana.PublicKey // account receiving the LP tokens before they are burned or locked
+	LpAmount    uint64
+	LpHandling  string // MIGRATION_LP_BURNED or MIGRATION_LP_LOCKED
 }
 
 // SwapBuy represents a buy swap operation
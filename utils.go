@@ -1,8 +1,11 @@
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
 	"encoding/hex"
 	"fmt"
+	"math/big"
 
 	"github.com/gagliardetto/solana-go"
 )
@@ -15,52 +18,189 @@ type TokenInfo struct {
 	Decimals uint8
 }
 
-// PoolInfo represents pool information
-type PoolInfo struct {
-	Address     solana.PublicKey
-	TokenA      solana.PublicKey
-	TokenB      solana.PublicKey
-	TokenAVault solana.PublicKey
-	TokenBVault solana.PublicKey
-	LpMint      solana.PublicKey
-	Fee         uint64
-}
-
-// Known token mints and their information
-var knownTokens = map[string]TokenInfo{
-	"So11111111111111111111111111111111111111112": {
-		Mint:     solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112"),
-		Symbol:   "SOL",
-		Name:     "Solana",
-		Decimals: 9,
-	},
-	"EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v": {
-		Mint:     solana.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"),
-		Symbol:   "USDC",
-		Name:     "USD Coin",
-		Decimals: 6,
-	},
-	"Es9vMFrzaCERmJfrF4H2FYD4KCoNkY11McCe8BenwNYB": {
-		Mint:     solana.MustPublicKeyFromBase58("Es9vMFrzaCERmJfrF4H2FYD4KCoNkY11McCe8BenwNYB"),
-		Symbol:   "USDT",
-		Name:     "Tether USD",
-		Decimals: 6,
-	},
-}
-
-// GetTokenInfo retrieves token information by mint address
+// GetTokenInfo retrieves token information by mint address from the current TokenInfoProvider
 func GetTokenInfo(mint solana.PublicKey) TokenInfo {
-	if info, exists := knownTokens[mint.String()]; exists {
+	info, exists := lookupToken(mint)
+	if exists {
 		return info
 	}
 
-	// Return default info for unknown tokens
+	// Return default info for unknown tokens, keeping any decimals observed on chain
+	decimals := uint8(DEFAULT_TOKEN_DECIMALS)
+	if info.Mint.Equals(mint) {
+		decimals = info.Decimals
+	}
 	return TokenInfo{
 		Mint:     mint,
 		Symbol:   "UNKNOWN",
 		Name:     "Unknown Token",
-		Decimals: 9,
+		Decimals: decimals,
+	}
+}
+
+// anchorAccountDiscriminator returns the 8-byte discriminator Anchor prefixes to account data
+func anchorAccountDiscriminator(name string) [8]byte {
+	var discriminator [8]byte
+	hash := sha256.Sum256([]byte("account:" + name))
+	copy(discriminator[:], hash[:8])
+	return discriminator
+}
+
+// anchorInstructionDiscriminator returns the 8-byte discriminator Anchor prefixes to instruction data
+func anchorInstructionDiscriminator(name string) [8]byte {
+	var discriminator [8]byte
+	hash := sha256.Sum256([]byte("global:" + name))
+	copy(discriminator[:], hash[:8])
+	return discriminator
+}
+
+// anchorEventDiscriminator returns the 8-byte discriminator Anchor prefixes to emitted event data
+func anchorEventDiscriminator(name string) [8]byte {
+	var discriminator [8]byte
+	hash := sha256.Sum256([]byte("event:" + name))
+	copy(discriminator[:], hash[:8])
+	return discriminator
+}
+
+// accountReader reads little-endian fields sequentially from raw account data.
+// The first out-of-bounds read is remembered and every later read returns zero values,
+// so decoders can read a whole layout and check err once at the end.
+type accountReader struct {
+	data   []byte
+	offset int
+	err    error
+}
+
+func newAccountReader(data []byte) *accountReader {
+	return &accountReader{data: data}
+}
+
+func (r *accountReader) take(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if r.offset+n > len(r.data) {
+		r.err = fmt.Errorf("account data too short: need %d bytes at offset %d, have %d", n, r.offset, len(r.data))
+		return nil
+	}
+	out := r.data[r.offset : r.offset+n]
+	r.offset += n
+	return out
+}
+
+func (r *accountReader) skip(n int) {
+	r.take(n)
+}
+
+func (r *accountReader) u8() uint8 {
+	if b := r.take(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *accountReader) u16() uint16 {
+	if b := r.take(2); b != nil {
+		return binary.LittleEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *accountReader) u32() uint32 {
+	if b := r.take(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *accountReader) u64() uint64 {
+	if b := r.take(8); b != nil {
+		return binary.LittleEndian.Uint64(b)
+	}
+	return 0
+}
+
+// u128 returns the low and high 64-bit halves of a little-endian u128
+func (r *accountReader) u128() (lo uint64, hi uint64) {
+	if b := r.take(16); b != nil {
+		return binary.LittleEndian.Uint64(b[:8]), binary.LittleEndian.Uint64(b[8:])
+	}
+	return 0, 0
+}
+
+func (r *accountReader) pubkey() solana.PublicKey {
+	if b := r.take(32); b != nil {
+		return solana.PublicKeyFromBytes(b)
+	}
+	return solana.PublicKey{}
+}
+
+// borshString reads a u32 length-prefixed string
+func (r *accountReader) borshString() string {
+	n := r.u32()
+	if r.err == nil && int(n) > len(r.data)-r.offset {
+		r.err = fmt.Errorf("string length %d at offset %d exceeds remaining %d bytes", n, r.offset, len(r.data)-r.offset)
+		return ""
+	}
+	return string(r.take(int(n)))
+}
+
+// anchorDiscriminator consumes and checks the 8-byte Anchor account discriminator
+func (r *accountReader) anchorDiscriminator(expected [8]byte, name string) {
+	b := r.take(8)
+	if b == nil {
+		return
+	}
+	if [8]byte(b) != expected {
+		r.err = fmt.Errorf("account discriminator %x does not match %s (%x)", b, name, expected)
+	}
+}
+
+// appendBorshString appends a u32-length-prefixed Borsh string
+func appendBorshString(data []byte, s string) []byte {
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(s)))
+	return append(data, s...)
+}
+
+// bigU64 converts a u64 amount to a big.Int for overflow-free curve math
+func bigU64(v uint64) *big.Int {
+	return new(big.Int).SetUint64(v)
+}
+
+// ceilDiv returns ceil(numerator / denominator) for non-negative operands
+func ceilDiv(numerator, denominator *big.Int) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	if remainder.Sign() != 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	return quotient
+}
+
+// mulDivCeil returns ceil(a * b / denominator) without intermediate overflow, capped at u64 max
+func mulDivCeil(a, b, denominator uint64) uint64 {
+	result := ceilDiv(new(big.Int).Mul(bigU64(a), bigU64(b)), bigU64(denominator))
+	if !result.IsUint64() {
+		return ^uint64(0)
+	}
+	return result.Uint64()
+}
+
+// applySlippageDown returns amount * (10000 - bps) / 10000, rounded down
+func applySlippageDown(amount uint64, slippageBps uint16) uint64 {
+	if slippageBps >= 10_000 {
+		return 0
+	}
+	result := new(big.Int).Mul(bigU64(amount), big.NewInt(int64(10_000-slippageBps)))
+	return result.Quo(result, big.NewInt(10_000)).Uint64()
+}
+
+// applySlippageUp returns amount * (10000 + bps) / 10000, rounded up and capped at u64 max
+func applySlippageUp(amount uint64, slippageBps uint16) uint64 {
+	result := ceilDiv(new(big.Int).Mul(bigU64(amount), big.NewInt(int64(10_000)+int64(slippageBps))), big.NewInt(10_000))
+	if !result.IsUint64() {
+		return ^uint64(0)
 	}
+	return result.Uint64()
 }
 
 // FormatTokenAmount formats a token amount according to its decimals
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// ErrInvalidInstruction is wrapped by every ValidationError
+var ErrInvalidInstruction = errors.New("invalid instruction")
+
+// ValidationError lists every problem found while validating an instruction builder
+type ValidationError struct {
+	Instruction string
+	Problems    []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s instruction: %s", e.Instruction, strings.Join(e.Problems, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidInstruction
+}
+
+// instructionValidator collects problems so Build can report them all at once
+type instructionValidator struct {
+	instruction string
+	problems    []string
+}
+
+func newInstructionValidator(instruction string) *instructionValidator {
+	return &instructionValidator{instruction: instruction}
+}
+
+func (v *instructionValidator) check(ok bool, format string, args ...interface{}) {
+	if !ok {
+		v.problems = append(v.problems, fmt.Sprintf(format, args...))
+	}
+}
+
+// account requires a non-zero public key
+func (v *instructionValidator) account(name string, key solana.PublicKey) {
+	v.check(!key.IsZero(), "missing %s", name)
+}
+
+// amount requires a non-zero amount
+func (v *instructionValidator) amount(name string, amount uint64) {
+	v.check(amount > 0, "%s must be greater than zero", name)
+}
+
+// maxLength limits a string field to maxBytes bytes
+func (v *instructionValidator) maxLength(name, value string, maxBytes int) {
+	v.check(len(value) <= maxBytes, "%s is %d bytes, limit is %d", name, len(value), maxBytes)
+}
+
+// validateSwapAmounts checks the amounts of an exact-in or exact-out swap against their quotes
+func validateSwapAmounts(v *instructionValidator, baseOut bool, amountIn, minimumAmountOut, expectedAmountOut, maxAmountIn, amountOut, expectedAmountIn uint64) {
+	if baseOut {
+		v.amount("amount out", amountOut)
+		v.amount("max amount in", maxAmountIn)
+		v.check(expectedAmountIn == 0 || maxAmountIn >= expectedAmountIn,
+			"max amount in %d is below expected amount in %d", maxAmountIn, expectedAmountIn)
+		return
+	}
+	v.amount("amount in", amountIn)
+	v.check(expectedAmountOut == 0 || minimumAmountOut <= expectedAmountOut,
+		"minimum amount out %d exceeds expected amount out %d", minimumAmountOut, expectedAmountOut)
+}
+
+func (v *instructionValidator) err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Instruction: v.instruction, Problems: v.problems}
+}
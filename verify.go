@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+
+	bin "github.com/gagliardetto/binary"
+	"github.com/gagliardetto/solana-go"
+)
+
+// Signature verification failures; VerifyTransactionSignatures wraps them with the signer
+var (
+	ErrMissingSignature   = errors.New("missing signature")
+	ErrInvalidSignature   = errors.New("invalid signature")
+	ErrSignatureMismatch  = errors.New("signature does not match transaction")
+	ErrSignatureCount     = errors.New("signature count does not match message header")
+	ErrUnverifiableFormat = errors.New("transaction cannot be verified")
+)
+
+// ParseOption configures ParseTransaction, ParseTransactionWithMeta and ParseTransactionWithSignature
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	verifySignatures bool
+	quiet            bool
+}
+
+// WithSignatureVerification rejects transactions whose signatures do not verify against the
+// message and the required signers in its header. ParseTransactionWithSignature additionally
+// requires the supplied signature to equal the transaction's first signature.
+func WithSignatureVerification() ParseOption {
+	return func(o *parseOptions) {
+		o.verifySignatures = true
+	}
+}
+
+// WithoutParseLogging turns off the per-instruction debug dumps and progress logging the
+// parse functions write by default. It only affects the parse it is passed to.
+func WithoutParseLogging() ParseOption {
+	return func(o *parseOptions) {
+		o.quiet = true
+	}
+}
+
+// logf logs a diagnostic from before the parse result exists unless logging is turned off
+func (o parseOptions) logf(format string, args ...interface{}) {
+	if !o.quiet {
+		log.Printf(format, args...)
+	}
+}
+
+func newParseOptions(opts []ParseOption) parseOptions {
+	var options parseOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// verify applies the verification options to an encoded transaction before it is parsed.
+// expected, when not nil, is the signature the caller claims identifies the transaction.
+func (o parseOptions) verify(encodedTx string, expected *solana.Signature) error {
+	if !o.verifySignatures {
+		return nil
+	}
+	txBytes, err := base64.StdEncoding.DecodeString(encodedTx)
+	if err != nil {
+		return fmt.Errorf("failed to decode base64 transaction: %w", err)
+	}
+	tx, err := VerifyTransactionSignatures(txBytes)
+	if err != nil {
+		return err
+	}
+	if expected != nil && !tx.Signatures[0].Equals(*expected) {
+		return fmt.Errorf("%w: supplied %s, transaction has %s", ErrSignatureMismatch, *expected, tx.Signatures[0])
+	}
+	return nil
+}
+
+// VerifyTransactionSignatures decodes a wire-format transaction and checks that it carries
+// exactly the signatures its header requires and that each one is a valid ed25519
+// signature by the matching account over the serialized message. Every failure is reported.
+func VerifyTransactionSignatures(txBytes []byte) (*solana.Transaction, error) {
+	tx, err := solana.TransactionFromDecoder(bin.NewBinDecoder(txBytes))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnverifiableFormat, err)
+	}
+
+	// Verify against the message bytes exactly as received rather than a re-encoding
+	count, prefix, err := bin.DecodeCompactU16(txBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnverifiableFormat, err)
+	}
+	message := txBytes[prefix+count*solana.SignatureLength:]
+
+	required := int(tx.Message.Header.NumRequiredSignatures)
+	if len(tx.Signatures) != required {
+		return nil, fmt.Errorf("%w: header requires %d, transaction has %d", ErrSignatureCount, required, len(tx.Signatures))
+	}
+	if len(tx.Message.AccountKeys) < required {
+		return nil, fmt.Errorf("%w: header requires %d signers but the message has %d account keys",
+			ErrSignatureCount, required, len(tx.Message.AccountKeys))
+	}
+	if required == 0 {
+		return nil, fmt.Errorf("%w: transaction has no signatures", ErrMissingSignature)
+	}
+
+	var errs []error
+	for i, signature := range tx.Signatures {
+		signer := tx.Message.AccountKeys[i]
+		switch {
+		case signature.IsZero():
+			errs = append(errs, fmt.Errorf("%w: signer %d (%s)", ErrMissingSignature, i, signer))
+		case !signature.Verify(signer, message):
+			errs = append(errs, fmt.Errorf("%w: signer %d (%s)", ErrInvalidSignature, i, signer))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return tx, nil
+}
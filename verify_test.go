@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// signedTransferBytes composes a transfer that needs both the payer's and a co-signer's signature
+func signedTransferBytes(t *testing.T) ([]byte, solana.PrivateKey, solana.PrivateKey) {
+	t.Helper()
+	payer := solana.NewWallet().PrivateKey
+	cosigner := solana.NewWallet().PrivateKey
+	tx, err := NewTransactionComposer(payer.PublicKey()).
+		SetRecentBlockhash(solana.Hash(solana.NewWallet().PublicKey())).
+		AddInstruction(NewTransferInstruction(payer.PublicKey(), solana.NewWallet().PublicKey(), 1_000)).
+		AddInstruction(NewTransferInstruction(cosigner.PublicKey(), payer.PublicKey(), 1)).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to compose transaction: %v", err)
+	}
+	payerSigner, _ := NewKeypairSigner(payer)
+	cosignerSigner, _ := NewKeypairSigner(cosigner)
+	if err := SignTransaction(tx, payerSigner, cosignerSigner); err != nil {
+		t.Fatalf("Failed to sign transaction: %v", err)
+	}
+	txBytes, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Failed to serialize transaction: %v", err)
+	}
+	return txBytes, payer, cosigner
+}
+
+func TestVerifyTransactionSignatures(t *testing.T) {
+	txBytes, _, cosigner := signedTransferBytes(t)
+	tx, err := VerifyTransactionSignatures(txBytes)
+	if err != nil {
+		t.Fatalf("Expected valid signatures, got %v", err)
+	}
+	if len(tx.Signatures) != 2 {
+		t.Fatalf("Expected 2 signatures, got %d", len(tx.Signatures))
+	}
+
+	signatureStart := 1
+	tests := []struct {
+		name     string
+		tamper   func(b []byte) []byte
+		expected error
+		contains string
+	}{
+		{"altered message", func(b []byte) []byte { b[len(b)-1] ^= 0xff; return b }, ErrInvalidSignature, "signer 0"},
+		{"altered second signature", func(b []byte) []byte {
+			b[signatureStart+solana.SignatureLength] ^= 0x01
+			return b
+		}, ErrInvalidSignature, cosigner.PublicKey().String()},
+		{"zero signature", func(b []byte) []byte {
+			copy(b[signatureStart:signatureStart+solana.SignatureLength], make([]byte, solana.SignatureLength))
+			return b
+		}, ErrMissingSignature, "signer 0"},
+		{"dropped signature", func(b []byte) []byte {
+			out := append([]byte{1}, b[signatureStart:signatureStart+solana.SignatureLength]...)
+			return append(out, b[signatureStart+2*solana.SignatureLength:]...)
+		}, ErrSignatureCount, "requires 2"},
+		{"truncated", func(b []byte) []byte { return b[:40] }, ErrUnverifiableFormat, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tampered := tt.tamper(append([]byte{}, txBytes...))
+			_, err := VerifyTransactionSignatures(tampered)
+			if !errors.Is(err, tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, err)
+			}
+			if !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected error mentioning %q, got %v", tt.contains, err)
+			}
+		})
+	}
+}
+
+func TestParseWithSignatureVerification(t *testing.T) {
+	txBytes, _, _ := signedTransferBytes(t)
+	encoded := base64.StdEncoding.EncodeToString(txBytes)
+	tampered := append([]byte{}, txBytes...)
+	tampered[len(tampered)-1] ^= 0xff
+	encodedTampered := base64.StdEncoding.EncodeToString(tampered)
+
+	result, err := ParseTransaction(encoded, 1, WithSignatureVerification())
+	if err != nil {
+		t.Fatalf("Failed to parse verified transaction: %v", err)
+	}
+	var first solana.Signature
+	copy(first[:], txBytes[1:])
+	if result.Signature != first {
+		t.Errorf("Expected signature %s, got %s", first, result.Signature)
+	}
+
+	// Without the option the parser still trusts its input
+	if _, err := ParseTransaction(encodedTampered, 1); err != nil {
+		t.Errorf("Parsing without verification should not fail: %v", err)
+	}
+	if _, err := ParseTransaction(encodedTampered, 1, WithSignatureVerification()); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature, got %v", err)
+	}
+	if _, err := ParseTransactionWithMeta(encodedTampered, 1, nil, WithSignatureVerification()); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature with metadata, got %v", err)
+	}
+
+	if _, err := ParseTransactionWithSignature(encoded, 1, first, WithSignatureVerification()); err != nil {
+		t.Errorf("Matching signature should verify: %v", err)
+	}
+	other := solana.SignatureFromBytes(txBytes[1+solana.SignatureLength : 1+2*solana.SignatureLength])
+	if _, err := ParseTransactionWithSignature(encoded, 1, other, WithSignatureVerification()); !errors.Is(err, ErrSignatureMismatch) {
+		t.Errorf("Expected ErrSignatureMismatch, got %v", err)
+	}
+	if result, err := ParseTransactionWithSignature(encoded, 1, other); err != nil || result.Signature != other {
+		t.Errorf("Without verification the supplied signature should be used: %v", err)
+	}
+}